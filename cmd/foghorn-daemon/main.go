@@ -1,6 +1,12 @@
 package main
 
-import "github.com/pfarrer/foghorn/internal/daemon"
+import (
+	// Embed the time zone database, so schedule time zones also resolve in
+	// images without zoneinfo.
+	_ "time/tzdata"
+
+	"github.com/pfarrer/foghorn/internal/daemon"
+)
 
 func main() {
 	daemon.Run()
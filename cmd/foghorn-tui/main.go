@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pfarrer/foghorn/internal/statusapi"
@@ -15,6 +16,12 @@ func main() {
 		help      bool
 		statusURL string
 		logLevel  string
+		token     string
+		tokenFile string
+		caFile    string
+		certFile  string
+		keyFile   string
+		insecure  bool
 	)
 
 	flag.BoolVar(&help, "h", false, "Show help message")
@@ -23,6 +30,12 @@ func main() {
 	flag.StringVar(&statusURL, "u", statusapi.DefaultBaseURL, "Daemon status API base URL")
 	flag.StringVar(&logLevel, "l", "info", "Log level label for display")
 	flag.StringVar(&logLevel, "log-level", "info", "Log level label for display")
+	flag.StringVar(&token, "token", os.Getenv("FOGHORN_STATUS_TOKEN"), "Bearer token for the status API")
+	flag.StringVar(&tokenFile, "token-file", "", "File containing the bearer token for the status API")
+	flag.StringVar(&caFile, "ca-cert", "", "CA file for verifying the status API certificate")
+	flag.StringVar(&certFile, "client-cert", "", "Client certificate file for mutual TLS")
+	flag.StringVar(&keyFile, "client-key", "", "Client private key file for mutual TLS")
+	flag.BoolVar(&insecure, "insecure-skip-verify", false, "Skip verification of the status API certificate")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Foghorn TUI Client\n\n")
@@ -32,6 +45,16 @@ func main() {
 		fmt.Fprintf(os.Stderr, "      Daemon status API base URL (default: %s)\n", statusapi.DefaultBaseURL)
 		fmt.Fprintf(os.Stderr, "  -l, --log-level <level>\n")
 		fmt.Fprintf(os.Stderr, "      Log level label for display (default: info)\n")
+		fmt.Fprintf(os.Stderr, "  --token <token>\n")
+		fmt.Fprintf(os.Stderr, "      Bearer token for the status API (default: $FOGHORN_STATUS_TOKEN)\n")
+		fmt.Fprintf(os.Stderr, "  --token-file <path>\n")
+		fmt.Fprintf(os.Stderr, "      File containing the bearer token for the status API\n")
+		fmt.Fprintf(os.Stderr, "  --ca-cert <path>\n")
+		fmt.Fprintf(os.Stderr, "      CA file for verifying the status API certificate\n")
+		fmt.Fprintf(os.Stderr, "  --client-cert <path>, --client-key <path>\n")
+		fmt.Fprintf(os.Stderr, "      Client certificate and key for mutual TLS\n")
+		fmt.Fprintf(os.Stderr, "  --insecure-skip-verify\n")
+		fmt.Fprintf(os.Stderr, "      Skip verification of the status API certificate\n")
 		fmt.Fprintf(os.Stderr, "  -h, --help\n")
 		fmt.Fprintf(os.Stderr, "      Show help message\n")
 	}
@@ -42,7 +65,28 @@ func main() {
 		return
 	}
 
-	model, err := tui.NewRemoteModel(statusURL, logLevel)
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading token file: %v\n", err)
+			os.Exit(1)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	var clientOpts []statusapi.ClientOption
+	if token != "" {
+		clientOpts = append(clientOpts, statusapi.WithToken(token))
+	}
+	if caFile != "" || certFile != "" || keyFile != "" || insecure {
+		tlsConfig, err := statusapi.ClientTLSConfig(caFile, certFile, keyFile, insecure)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring TLS: %v\n", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, statusapi.WithClientTLS(tlsConfig))
+	}
+
+	model, err := tui.NewRemoteModel(statusURL, logLevel, clientOpts...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error connecting to daemon status API: %v\n", err)
 		os.Exit(1)
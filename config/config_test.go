@@ -2,9 +2,12 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/pfarrer/foghorn/evaluator"
 	"gopkg.in/yaml.v3"
 )
 
@@ -116,12 +119,353 @@ func TestValidateRequiredFields(t *testing.T) {
 			wantErr: true,
 			errMsg:  "check_container_debug_output must be one of off, on_failure, always",
 		},
+		{
+			name:    "unknown state backend",
+			config:  "state_backend: postgres\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    evaluation: []\n    enabled: true",
+			wantErr: true,
+			errMsg:  "state_backend must be one of log, sqlite",
+		},
 		{
 			name:    "negative debug output max chars",
 			config:  "debug_output_max_chars: -1\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    evaluation: []\n    enabled: true",
 			wantErr: true,
 			errMsg:  "debug_output_max_chars cannot be negative",
 		},
+		{
+			name:    "unsupported evaluation type",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    evaluation:\n      - type: regex\n        condition: 'status'\n    enabled: true",
+			wantErr: true,
+			errMsg:  "evaluation rule 1: unsupported evaluation type",
+		},
+		{
+			name:    "invalid evaluation condition",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    evaluation:\n      - type: json\n        condition: \"status = 'pass'\"\n    enabled: true",
+			wantErr: true,
+			errMsg:  "invalid condition",
+		},
+		{
+			name:    "valid evaluation rules",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    evaluation:\n      - type: json\n        condition: \"status == 'pass'\"\n        severity: warn\n      - type: threshold\n        condition: data.latency_ms\n        threshold: 500\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "notifier with unknown type",
+			config:  "notifiers:\n  - name: pager\n    type: sms\nchecks: []",
+			wantErr: true,
+			errMsg:  "notifier pager: type must be one of webhook, email, exec",
+		},
+		{
+			name:    "webhook notifier without url",
+			config:  "notifiers:\n  - name: hook\n    type: webhook\nchecks: []",
+			wantErr: true,
+			errMsg:  "url is required for webhook notifiers",
+		},
+		{
+			name:    "duplicate notifier names",
+			config:  "notifiers:\n  - name: hook\n    type: exec\n    command: [true]\n  - name: hook\n    type: exec\n    command: [true]\nchecks: []",
+			wantErr: true,
+			errMsg:  "duplicate name",
+		},
+		{
+			name:    "notifier with invalid status filter",
+			config:  "notifiers:\n  - name: hook\n    type: webhook\n    url: http://example.com\n    statuses: [unknown]\nchecks: []",
+			wantErr: true,
+			errMsg:  "statuses must only contain",
+		},
+		{
+			name:    "notifier with invalid template",
+			config:  "notifiers:\n  - name: hook\n    type: webhook\n    url: http://example.com\n    template: '{{.Check'\nchecks: []",
+			wantErr: true,
+			errMsg:  "invalid template",
+		},
+		{
+			name:    "valid notifiers",
+			config:  "notifiers:\n  - name: hook\n    type: webhook\n    url: http://example.com\n    tags: [prod]\n  - name: mail\n    type: email\n    smtp_host: smtp.example.com\n    from: a@example.com\n    to: [b@example.com]\n    password: secret://smtp/password\nchecks: []",
+			wantErr: false,
+		},
+		{
+			name:    "invalid container memory",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    container:\n      memory: lots\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: container: invalid memory",
+		},
+		{
+			name:    "negative container cpus",
+			config:  "container_defaults:\n  cpus: -1\nchecks: []",
+			wantErr: true,
+			errMsg:  "container_defaults: cpus cannot be negative",
+		},
+		{
+			name:    "invalid capability",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    container:\n      cap_add: [net-raw]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "invalid capability",
+		},
+		{
+			name:    "unknown ulimit",
+			config:  "container_defaults:\n  ulimits:\n    files: {soft: 1, hard: 2}\nchecks: []",
+			wantErr: true,
+			errMsg:  "unknown ulimit",
+		},
+		{
+			name:    "ulimit soft above hard",
+			config:  "container_defaults:\n  ulimits:\n    nofile: {soft: 2048, hard: 1024}\nchecks: []",
+			wantErr: true,
+			errMsg:  "soft <= hard",
+		},
+		{
+			name:    "relative tmpfs path",
+			config:  "container_defaults:\n  tmpfs:\n    cache: size=1m\nchecks: []",
+			wantErr: true,
+			errMsg:  "must be absolute",
+		},
+		{
+			name:    "valid container settings",
+			config:  "container_defaults:\n  memory: 128m\n  pids_limit: 64\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    container:\n      memory: 1g\n      cpus: 0.5\n      read_only_rootfs: false\n      cap_add: [NET_RAW, cap_net_bind_service]\n      user: '1000:1000'\n      ulimits:\n        nofile: {soft: 1024, hard: 2048}\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "valid retry settings",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    retries: 2\n    retry_delay: 10s\n    retry_max_delay: 1m\n    backoff: exponential\n    failure_threshold: 3\n    recovery_threshold: 2\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "too many retries",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    retries: 11\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: retries must be between 0 and 10",
+		},
+		{
+			name:    "retry max delay shorter than the delay",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    retries: 3\n    retry_delay: 10s\n    retry_max_delay: 5s\n    backoff: exponential\n    enabled: true",
+			wantErr: true,
+			errMsg:  "retry_max_delay cannot be shorter than retry_delay",
+		},
+		{
+			name:    "invalid retry max delay",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    retries: 3\n    retry_max_delay: 0s\n    enabled: true",
+			wantErr: true,
+			errMsg:  "retry_max_delay must be a positive duration",
+		},
+		{
+			name:    "unknown backoff",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    retries: 1\n    backoff: linear\n    enabled: true",
+			wantErr: true,
+			errMsg:  "backoff must be one of constant, exponential",
+		},
+		{
+			name:    "negative failure threshold",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    failure_threshold: -1\n    enabled: true",
+			wantErr: true,
+			errMsg:  "failure_threshold cannot be negative",
+		},
+		{
+			name:    "valid dependencies",
+			config:  "checks:\n  - name: dns\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [dns]\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "unknown dependency",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [dns]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check api: depends_on: unknown check \"dns\"",
+		},
+		{
+			name:    "dependency cycle",
+			config:  "checks:\n  - name: a\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [b]\n    enabled: true\n  - name: b\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [c]\n    enabled: true\n  - name: c\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [b]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "dependency cycle: b -> c -> b",
+		},
+		{
+			name:    "self dependency",
+			config:  "checks:\n  - name: a\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    depends_on: [a]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "dependency cycle: a -> a",
+		},
+		{
+			name:    "valid maintenance windows",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true\nmaintenance_windows:\n  - name: deploy\n    cron: '0 2 * * *'\n    duration: 30m\n    checks: [api]\n  - name: migration\n    start: '2025-06-01T22:00:00Z'\n    end: '2025-06-02T01:00:00Z'\n    tags: [storage]\n    mode: record",
+			wantErr: false,
+		},
+		{
+			name:    "maintenance window with cron and start",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true\nmaintenance_windows:\n  - name: deploy\n    cron: '0 2 * * *'\n    duration: 30m\n    start: '2025-06-01T22:00:00Z'\n    checks: [api]",
+			wantErr: true,
+			errMsg:  "maintenance window deploy: use either cron and duration or start and end",
+		},
+		{
+			name:    "maintenance window ending before start",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true\nmaintenance_windows:\n  - name: deploy\n    start: '2025-06-02T22:00:00Z'\n    end: '2025-06-01T22:00:00Z'\n    checks: [api]",
+			wantErr: true,
+			errMsg:  "end must be after start",
+		},
+		{
+			name:    "maintenance window for unknown check",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true\nmaintenance_windows:\n  - name: deploy\n    cron: '0 2 * * *'\n    duration: 30m\n    checks: [web]",
+			wantErr: true,
+			errMsg:  "maintenance window deploy: unknown check \"web\"",
+		},
+		{
+			name:    "valid time zones",
+			config:  "timezone: Europe/Berlin\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 9 * * 1-5'\n      timezone: America/New_York\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "unknown global time zone",
+			config:  "timezone: Mars/Olympus\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true",
+			wantErr: true,
+			errMsg:  "timezone: unknown time zone \"Mars/Olympus\"",
+		},
+		{
+			name:    "unknown check time zone",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 9 * * *'\n      timezone: CEST\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule timezone: unknown time zone \"CEST\"",
+		},
+		{
+			name:    "valid splay and jitter",
+			config:  "splay: 30s\njitter: 10s\nchecks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n      splay: 0s\n    enabled: true\n  - name: report\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 9 * * *'\n      jitter: 2m\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "negative global splay",
+			config:  "splay: -5s\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    enabled: true",
+			wantErr: true,
+			errMsg:  "config: splay must be a non-negative duration",
+		},
+		{
+			name:    "jitter on an interval check",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n      jitter: 10s\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: jitter only applies to cron schedules",
+		},
+		{
+			name:    "splay on a cron check",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '* * * * *'\n      splay: 10s\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: splay only applies to interval schedules",
+		},
+		{
+			name:    "valid start policies",
+			config:  "checks:\n  - name: certs\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 3 * * *'\n      on_start: if_overdue\n      catch_up: once\n    enabled: true\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n      on_start: skip\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "invalid on_start",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 3 * * *'\n      on_start: always\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: on_start must be one of run, skip, if_overdue",
+		},
+		{
+			name:    "invalid catch_up",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 3 * * *'\n      catch_up: all\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: catch_up must be one of none, once",
+		},
+		{
+			name:    "catch_up with on_start skip",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      cron: '0 3 * * *'\n      on_start: skip\n      catch_up: once\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: catch_up only applies with on_start if_overdue",
+		},
+		{
+			name:    "valid schedule windows",
+			config:  "checks:\n  - name: api\n    image: test/image:1.0.0\n    schedule:\n      interval: 15m\n      windows:\n        - name: business\n          days: [MON-FRI]\n          start: '08:00'\n          end: '18:00'\n          interval: 1m\n  - name: batch\n    image: test/image:1.0.0\n    schedule:\n      jitter: 30s\n      windows:\n        - days: [SAT, sun]\n          timezone: Europe/Berlin\n          cron: '0 */6 * * *'\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "schedule window with cron and interval",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      windows:\n        - interval: 1m\n          cron: '* * * * *'\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: window 1: exactly one of cron or interval is required",
+		},
+		{
+			name:    "schedule window with invalid day",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      windows:\n        - name: business\n          days: [MON-FRY]\n          interval: 1m\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: window business: days: invalid day \"FRY\" (use SUN-SAT)",
+		},
+		{
+			name:    "schedule window without end",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      windows:\n        - start: '08:00'\n          interval: 1m\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: window 1: end: must be a time of day such as 08:00",
+		},
+		{
+			name:    "splay with schedule windows",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: 15m\n      splay: 30s\n      windows:\n        - start: '08:00'\n          end: '18:00'\n          interval: 1m\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: schedule: splay cannot be combined with windows",
+		},
+		{
+			name:    "invalid network mode",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      mode: overlay\n    enabled: true",
+			wantErr: true,
+			errMsg:  "check test: network: mode must be one of bridge, host, none",
+		},
+		{
+			name:    "networks with mode none",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      mode: none\n      networks: [backend]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "networks cannot be combined with mode none",
+		},
+		{
+			name:    "dns with host network",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      mode: host\n      dns: [10.0.0.2]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "dns settings cannot be combined with mode host",
+		},
+		{
+			name:    "dns server hostname",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      dns: [dns.example.com]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "must be an IP address",
+		},
+		{
+			name:    "extra host without ip",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      extra_hosts: [api.example.com]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "must be host:ip",
+		},
+		{
+			name:    "duplicate network",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      networks: [backend, backend]\n    enabled: true",
+			wantErr: true,
+			errMsg:  "listed more than once",
+		},
+		{
+			name:    "valid network settings",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network:\n      networks: [backend, monitoring_net]\n      dns: [10.0.0.2, 'fd00::53']\n      dns_search: [svc.internal]\n      extra_hosts: ['api.example.com:10.0.0.5', 'v6.example.com:fd00::5', 'host.docker.internal:host-gateway']\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "offline check shorthand",
+			config:  "checks:\n  - name: test\n    image: test/image:1.0.0\n    schedule:\n      interval: '1m'\n    network: none\n    enabled: true",
+			wantErr: false,
+		},
+		{
+			name:    "status api token without secret reference",
+			config:  "status_api:\n  tokens:\n    - name: dashboard\n      token: plaintext\nchecks: []",
+			wantErr: true,
+			errMsg:  "token must be a secret:// reference",
+		},
+		{
+			name:    "status api invalid scope",
+			config:  "status_api:\n  tokens:\n    - name: dashboard\n      token: secret://dashboard\n      scope: admin\nchecks: []",
+			wantErr: true,
+			errMsg:  "scope must be read or operator",
+		},
+		{
+			name:    "status api client ca without certificate",
+			config:  "status_api:\n  tls:\n    client_ca_file: /etc/foghorn/ca.pem\nchecks: []",
+			wantErr: true,
+			errMsg:  "client_ca_file requires cert_file and key_file",
+		},
+		{
+			name:    "valid status api settings",
+			config:  "status_api:\n  tls:\n    cert_file: /etc/foghorn/tls.crt\n    key_file: /etc/foghorn/tls.key\n    client_ca_file: /etc/foghorn/ca.pem\n  tokens:\n    - name: dashboard\n      token: secret://status/dashboard\n    - name: ops\n      token: secret://status/ops\n      scope: operator\nchecks: []",
+			wantErr: false,
+		},
 		{
 			name:    "valid debug output config",
 			config:  "check_container_debug_output: on_failure\ndebug_output_max_chars: 2048\nchecks:\n  - name: test\n    image: test/image:1.0.0\n    check_container_debug_output: always\n    schedule:\n      interval: '1m'\n    evaluation: []\n    enabled: true",
@@ -148,6 +492,177 @@ func TestValidateRequiredFields(t *testing.T) {
 	}
 }
 
+func TestLoadResolvesContainerSettings(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	content := `container_defaults:
+  memory: 128m
+  ulimits:
+    nofile: {soft: 1024, hard: 1024}
+---
+name: defaults
+image: test/image:1.0.0
+schedule:
+  interval: 1m
+---
+name: custom
+image: test/image:1.0.0
+schedule:
+  interval: 1m
+container:
+  memory: "0"
+  cpus: 2
+  read_only_rootfs: false
+  cap_drop: []
+  user: nobody
+  ulimits:
+    nproc: {soft: 64, hard: 64}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	defaults := cfg.Checks[0].Container
+	if defaults.Memory != "128m" || *defaults.CPUs != 1 || *defaults.PidsLimit != 256 || !*defaults.ReadOnlyRootfs || !*defaults.NoNewPrivileges {
+		t.Fatalf("defaults not applied: %+v", defaults)
+	}
+	if strings.Join(defaults.CapDrop, ",") != "ALL" || defaults.Tmpfs["/tmp"] == "" || defaults.Ulimits["nofile"].Hard != 1024 {
+		t.Fatalf("default lists not applied: %+v", defaults)
+	}
+
+	custom := cfg.Checks[1].Container
+	if memory, _ := custom.MemoryBytes(); memory != 0 {
+		t.Fatalf("memory \"0\" should disable the limit, got %d", memory)
+	}
+	if *custom.CPUs != 2 || *custom.ReadOnlyRootfs || len(custom.CapDrop) != 0 || custom.User != "nobody" {
+		t.Fatalf("check overrides not applied: %+v", custom)
+	}
+	if custom.Ulimits["nofile"].Soft != 1024 || custom.Ulimits["nproc"].Soft != 64 {
+		t.Fatalf("ulimits should merge by name: %+v", custom.Ulimits)
+	}
+}
+
+func TestLoadResolvesScheduleSpread(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	content := `splay: 30s
+jitter: 10s
+---
+name: interval
+image: test/image:1.0.0
+schedule:
+  interval: 1m
+---
+name: windowed
+image: test/image:1.0.0
+schedule:
+  interval: 15m
+  windows:
+    - start: "08:00"
+      end: "18:00"
+      interval: 1m
+---
+name: cron
+image: test/image:1.0.0
+schedule:
+  cron: "0 * * * *"
+  jitter: 1m
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	interval, windowed, cron := cfg.Checks[0].Schedule, cfg.Checks[1].Schedule, cfg.Checks[2].Schedule
+	if interval.SplayDuration() != 30*time.Second || interval.Jitter != "" {
+		t.Errorf("interval schedule = %+v, want the global splay only", interval)
+	}
+	if windowed.Splay != "" || windowed.Jitter != "" {
+		t.Errorf("windowed schedule = %+v, want no splay or jitter", windowed)
+	}
+	if cron.JitterDuration() != time.Minute || cron.Splay != "" {
+		t.Errorf("cron schedule = %+v, want its own jitter only", cron)
+	}
+}
+
+func TestLoadCompilesEvaluationRules(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	content := `name: api
+image: test/image:1.0.0
+schedule:
+  interval: 1m
+evaluation:
+  - type: threshold
+    condition: data.latency_ms
+    threshold: 500
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	check := &cfg.Checks[0]
+	if check.evaluation == nil {
+		t.Fatalf("evaluation rules should be compiled on load")
+	}
+	outcome := check.Evaluate(evaluator.Result{Status: "pass", Data: map[string]interface{}{"latency_ms": float64(900)}})
+	if outcome.Status != evaluator.StatusFail {
+		t.Errorf("Evaluate() status = %s, want fail", outcome.Status)
+	}
+}
+
+func TestScheduleWindowHoursAndDays(t *testing.T) {
+	w := ScheduleWindow{Days: []string{"fri-mon", "WED"}, Start: "22:30", End: "06:00"}
+	days, err := w.Weekdays()
+	if err != nil {
+		t.Fatalf("Weekdays() error = %v", err)
+	}
+	want := []time.Weekday{time.Sunday, time.Monday, time.Wednesday, time.Friday, time.Saturday}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("Weekdays() = %v, want %v", days, want)
+	}
+	start, end, err := w.Hours()
+	if err != nil || start != 22*time.Hour+30*time.Minute || end != 6*time.Hour {
+		t.Errorf("Hours() = %v, %v, %v", start, end, err)
+	}
+	if _, _, err := (ScheduleWindow{Start: "08:00", End: "08:00"}).Hours(); err == nil {
+		t.Errorf("Hours() should reject an empty window")
+	}
+}
+
+func TestNetworkConfigShorthand(t *testing.T) {
+	tests := []struct {
+		value string
+		want  NetworkConfig
+	}{
+		{value: "none", want: NetworkConfig{Mode: NetworkModeNone}},
+		{value: "host", want: NetworkConfig{Mode: NetworkModeHost}},
+		{value: "backend", want: NetworkConfig{Networks: []string{"backend"}}},
+		{value: "{mode: bridge, dns: [10.0.0.2]}", want: NetworkConfig{Mode: NetworkModeBridge, DNS: []string{"10.0.0.2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var check CheckConfig
+			if err := yaml.Unmarshal([]byte("network: "+tt.value), &check); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(check.Network, tt.want) {
+				t.Fatalf("Network = %+v, want %+v", check.Network, tt.want)
+			}
+		})
+	}
+}
+
 func TestHelpfulErrorMessages(t *testing.T) {
 	t.Run("non-existent file", func(t *testing.T) {
 		_, err := Load("../non-existent.yaml")
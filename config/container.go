@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// ContainerConfig holds resource limits and hardening options for check
+// containers. Unset fields inherit from container_defaults and the built-in
+// defaults; a memory, cpus or pids_limit of 0 removes the limit.
+type ContainerConfig struct {
+	Memory          string            `yaml:"memory,omitempty"`
+	CPUs            *float64          `yaml:"cpus,omitempty"`
+	PidsLimit       *int64            `yaml:"pids_limit,omitempty"`
+	ReadOnlyRootfs  *bool             `yaml:"read_only_rootfs,omitempty"`
+	CapDrop         []string          `yaml:"cap_drop,omitempty"`
+	CapAdd          []string          `yaml:"cap_add,omitempty"`
+	NoNewPrivileges *bool             `yaml:"no_new_privileges,omitempty"`
+	User            string            `yaml:"user,omitempty"`
+	Ulimits         map[string]Ulimit `yaml:"ulimits,omitempty"`
+	Tmpfs           map[string]string `yaml:"tmpfs,omitempty"`
+}
+
+type Ulimit struct {
+	Soft int64 `yaml:"soft"`
+	Hard int64 `yaml:"hard"`
+}
+
+func DefaultContainerConfig() ContainerConfig {
+	cpus := 1.0
+	pidsLimit := int64(256)
+	readOnly := true
+	noNewPrivileges := true
+	return ContainerConfig{
+		Memory:          "256m",
+		CPUs:            &cpus,
+		PidsLimit:       &pidsLimit,
+		ReadOnlyRootfs:  &readOnly,
+		CapDrop:         []string{"ALL"},
+		NoNewPrivileges: &noNewPrivileges,
+		Tmpfs:           map[string]string{"/tmp": "rw,noexec,nosuid,size=64m"},
+	}
+}
+
+// Merge returns c with every field set in override replacing its value.
+// Ulimits and tmpfs mounts are merged by key.
+func (c ContainerConfig) Merge(override ContainerConfig) ContainerConfig {
+	out := c
+	if override.Memory != "" {
+		out.Memory = override.Memory
+	}
+	if override.CPUs != nil {
+		out.CPUs = override.CPUs
+	}
+	if override.PidsLimit != nil {
+		out.PidsLimit = override.PidsLimit
+	}
+	if override.ReadOnlyRootfs != nil {
+		out.ReadOnlyRootfs = override.ReadOnlyRootfs
+	}
+	if override.CapDrop != nil {
+		out.CapDrop = override.CapDrop
+	}
+	if override.CapAdd != nil {
+		out.CapAdd = override.CapAdd
+	}
+	if override.NoNewPrivileges != nil {
+		out.NoNewPrivileges = override.NoNewPrivileges
+	}
+	if override.User != "" {
+		out.User = override.User
+	}
+	if len(override.Ulimits) > 0 {
+		out.Ulimits = make(map[string]Ulimit, len(c.Ulimits)+len(override.Ulimits))
+		for name, limit := range c.Ulimits {
+			out.Ulimits[name] = limit
+		}
+		for name, limit := range override.Ulimits {
+			out.Ulimits[name] = limit
+		}
+	}
+	if len(override.Tmpfs) > 0 {
+		out.Tmpfs = make(map[string]string, len(c.Tmpfs)+len(override.Tmpfs))
+		for target, options := range c.Tmpfs {
+			out.Tmpfs[target] = options
+		}
+		for target, options := range override.Tmpfs {
+			out.Tmpfs[target] = options
+		}
+	}
+	return out
+}
+
+// MemoryBytes returns the memory limit in bytes, 0 meaning unlimited.
+func (c ContainerConfig) MemoryBytes() (int64, error) {
+	if c.Memory == "" || c.Memory == "0" {
+		return 0, nil
+	}
+	return units.RAMInBytes(c.Memory)
+}
+
+var (
+	capabilityPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	userPattern       = regexp.MustCompile(`^[A-Za-z0-9_.-]+(:[A-Za-z0-9_.-]+)?$`)
+	knownUlimits      = map[string]bool{
+		"core": true, "cpu": true, "data": true, "fsize": true, "locks": true,
+		"memlock": true, "msgqueue": true, "nice": true, "nofile": true, "nproc": true,
+		"rss": true, "rtprio": true, "rttime": true, "sigpending": true, "stack": true,
+	}
+)
+
+func validateContainerConfig(subject string, c ContainerConfig) error {
+	if _, err := c.MemoryBytes(); err != nil {
+		return fmt.Errorf("%s: invalid memory %q: %w", subject, c.Memory, err)
+	}
+	if c.CPUs != nil && *c.CPUs < 0 {
+		return fmt.Errorf("%s: cpus cannot be negative", subject)
+	}
+	if c.PidsLimit != nil && *c.PidsLimit < 0 {
+		return fmt.Errorf("%s: pids_limit cannot be negative", subject)
+	}
+	for _, capability := range append(append([]string{}, c.CapDrop...), c.CapAdd...) {
+		if !capabilityPattern.MatchString(strings.ToUpper(capability)) {
+			return fmt.Errorf("%s: invalid capability %q", subject, capability)
+		}
+	}
+	if c.User != "" && !userPattern.MatchString(c.User) {
+		return fmt.Errorf("%s: user must be <user> or <user>:<group>", subject)
+	}
+	for name, limit := range c.Ulimits {
+		if !knownUlimits[name] {
+			return fmt.Errorf("%s: unknown ulimit %q", subject, name)
+		}
+		if limit.Soft < 0 || limit.Hard < 0 || limit.Soft > limit.Hard {
+			return fmt.Errorf("%s: ulimit %s must have 0 <= soft <= hard", subject, name)
+		}
+	}
+	for target := range c.Tmpfs {
+		if !path.IsAbs(target) {
+			return fmt.Errorf("%s: tmpfs path %q must be absolute", subject, target)
+		}
+	}
+	return nil
+}
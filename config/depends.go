@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateDependencies rejects depends_on entries that name unknown checks
+// and dependency cycles.
+func validateDependencies(checks []CheckConfig) error {
+	deps := make(map[string][]string, len(checks))
+	for _, check := range checks {
+		deps[check.Name] = check.DependsOn
+	}
+	for _, check := range checks {
+		seen := make(map[string]bool, len(check.DependsOn))
+		for _, dep := range check.DependsOn {
+			if _, ok := deps[dep]; !ok {
+				return fmt.Errorf("check %s: depends_on: unknown check %q", check.Name, dep)
+			}
+			if seen[dep] {
+				return fmt.Errorf("check %s: depends_on: %s listed more than once", check.Name, dep)
+			}
+			seen[dep] = true
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(checks))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+	for _, check := range checks {
+		if err := visit(check.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,9 +5,11 @@ import (
 	"io"
 	"os"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pfarrer/foghorn/containerimage"
+	"github.com/pfarrer/foghorn/evaluator"
 	"gopkg.in/yaml.v3"
 )
 
@@ -54,6 +56,8 @@ func Load(path string) (*Config, error) {
 	if err := validate(cfg); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	resolveContainerConfigs(cfg)
+	resolveScheduleSpread(cfg)
 
 	return cfg, nil
 }
@@ -90,6 +94,17 @@ func validate(cfg *Config) error {
 			return fmt.Errorf("state_log_period must be a positive duration")
 		}
 	}
+	switch cfg.StateBackend {
+	case "", StateBackendLog, StateBackendSQLite:
+	default:
+		return fmt.Errorf("state_backend must be one of %s, %s", StateBackendLog, StateBackendSQLite)
+	}
+	if _, err := LoadLocation(cfg.Timezone); err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	if err := validateSpread("config", cfg.Splay, cfg.Jitter); err != nil {
+		return err
+	}
 	if err := validateDebugOutputMode("config", cfg.CheckContainerDebugOutput); err != nil {
 		return err
 	}
@@ -97,6 +112,16 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("debug_output_max_chars cannot be negative")
 	}
 
+	if err := validateNotifiers(cfg.Notifiers); err != nil {
+		return err
+	}
+	if err := validateContainerConfig("container_defaults", cfg.ContainerDefaults); err != nil {
+		return err
+	}
+	if err := validateStatusAPIConfig(cfg.StatusAPI); err != nil {
+		return err
+	}
+
 	for i, check := range cfg.Checks {
 		if check.Name == "" {
 			return fmt.Errorf("check %d: name is required", i+1)
@@ -107,15 +132,103 @@ func validate(cfg *Config) error {
 		if _, err := containerimage.ParseReference(check.Image); err != nil {
 			return fmt.Errorf("check %s: invalid image tag: %w", check.Name, err)
 		}
-		if check.Schedule.Cron == "" && check.Schedule.Interval == "" {
+		if check.Schedule.Cron == "" && check.Schedule.Interval == "" && len(check.Schedule.Windows) == 0 {
 			return fmt.Errorf("check %s: schedule (cron or interval) is required", check.Name)
 		}
 		if check.Schedule.Cron != "" && check.Schedule.Interval != "" {
 			return fmt.Errorf("check %s: only one of cron or interval should be specified", check.Name)
 		}
+		if _, err := LoadLocation(check.Schedule.Timezone); err != nil {
+			return fmt.Errorf("check %s: schedule timezone: %w", check.Name, err)
+		}
+		if err := validateScheduleSpread(fmt.Sprintf("check %s: schedule", check.Name), check.Schedule); err != nil {
+			return err
+		}
+		if err := validateStartPolicy(fmt.Sprintf("check %s: schedule", check.Name), check.Schedule); err != nil {
+			return err
+		}
+		if err := validateScheduleWindows(fmt.Sprintf("check %s: schedule", check.Name), check.Schedule.Windows); err != nil {
+			return err
+		}
 		if err := validateDebugOutputMode(fmt.Sprintf("check %s", check.Name), check.CheckContainerDebugOutput); err != nil {
 			return err
 		}
+		if err := validateContainerConfig(fmt.Sprintf("check %s: container", check.Name), check.Container); err != nil {
+			return err
+		}
+		if err := validateNetworkConfig(fmt.Sprintf("check %s: network", check.Name), check.Network); err != nil {
+			return err
+		}
+		if err := validateRetryConfig(fmt.Sprintf("check %s", check.Name), check); err != nil {
+			return err
+		}
+		// The compiled rules are kept, so results are evaluated without
+		// parsing the conditions again.
+		evaluation, err := evaluator.CompileRules(check.evaluatorRules())
+		if err != nil {
+			return fmt.Errorf("check %s: %w", check.Name, err)
+		}
+		cfg.Checks[i].evaluation = evaluation
+	}
+	if err := validateDependencies(cfg.Checks); err != nil {
+		return err
+	}
+	return validateMaintenanceWindows(cfg.MaintenanceWindows, cfg.Checks)
+}
+
+func validateNotifiers(notifiers []NotifierConfig) error {
+	seen := make(map[string]bool, len(notifiers))
+	for i, n := range notifiers {
+		if n.Name == "" {
+			return fmt.Errorf("notifier %d: name is required", i+1)
+		}
+		if seen[n.Name] {
+			return fmt.Errorf("notifier %s: duplicate name", n.Name)
+		}
+		seen[n.Name] = true
+
+		switch n.Type {
+		case "webhook":
+			if n.URL == "" {
+				return fmt.Errorf("notifier %s: url is required for webhook notifiers", n.Name)
+			}
+		case "email":
+			if n.SMTPHost == "" {
+				return fmt.Errorf("notifier %s: smtp_host is required for email notifiers", n.Name)
+			}
+			if n.From == "" || len(n.To) == 0 {
+				return fmt.Errorf("notifier %s: from and to are required for email notifiers", n.Name)
+			}
+			if n.SMTPPort < 0 || n.SMTPPort > 65535 {
+				return fmt.Errorf("notifier %s: smtp_port must be between 1 and 65535", n.Name)
+			}
+		case "exec":
+			if len(n.Command) == 0 {
+				return fmt.Errorf("notifier %s: command is required for exec notifiers", n.Name)
+			}
+		default:
+			return fmt.Errorf("notifier %s: type must be one of webhook, email, exec", n.Name)
+		}
+
+		for _, status := range n.Statuses {
+			switch status {
+			case "pass", "warn", "fail", "error":
+			default:
+				return fmt.Errorf("notifier %s: statuses must only contain pass, warn, fail, error", n.Name)
+			}
+		}
+		if n.Timeout != "" {
+			timeout, err := time.ParseDuration(n.Timeout)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("notifier %s: timeout must be a positive duration", n.Name)
+			}
+		}
+		if _, err := template.New("template").Parse(n.Template); err != nil {
+			return fmt.Errorf("notifier %s: invalid template: %w", n.Name, err)
+		}
+		if _, err := template.New("subject").Parse(n.Subject); err != nil {
+			return fmt.Errorf("notifier %s: invalid subject: %w", n.Name, err)
+		}
 	}
 	return nil
 }
@@ -129,6 +242,16 @@ func validateDebugOutputMode(subject string, mode string) error {
 	}
 }
 
+// resolveContainerConfigs applies the built-in defaults and
+// container_defaults to every check, so checks carry their effective
+// container settings.
+func resolveContainerConfigs(cfg *Config) {
+	defaults := DefaultContainerConfig().Merge(cfg.ContainerDefaults)
+	for i := range cfg.Checks {
+		cfg.Checks[i].Container = defaults.Merge(cfg.Checks[i].Container)
+	}
+}
+
 func decodeInto(raw map[string]interface{}, dest interface{}) error {
 	data, err := yaml.Marshal(raw)
 	if err != nil {
@@ -150,6 +273,18 @@ func mergeConfig(dst *Config, src *Config) {
 	if src.StateLogPeriod != "" {
 		dst.StateLogPeriod = src.StateLogPeriod
 	}
+	if src.StateBackend != "" {
+		dst.StateBackend = src.StateBackend
+	}
+	if src.Timezone != "" {
+		dst.Timezone = src.Timezone
+	}
+	if src.Splay != "" {
+		dst.Splay = src.Splay
+	}
+	if src.Jitter != "" {
+		dst.Jitter = src.Jitter
+	}
 	if src.SecretStoreFile != "" {
 		dst.SecretStoreFile = src.SecretStoreFile
 	}
@@ -159,6 +294,16 @@ func mergeConfig(dst *Config, src *Config) {
 	if src.DebugOutputMaxChars != 0 {
 		dst.DebugOutputMaxChars = src.DebugOutputMaxChars
 	}
+	if src.MetricsExportData {
+		dst.MetricsExportData = true
+	}
+	dst.ContainerDefaults = dst.ContainerDefaults.Merge(src.ContainerDefaults)
+	if src.StatusAPI.TLS != (StatusTLSConfig{}) {
+		dst.StatusAPI.TLS = src.StatusAPI.TLS
+	}
+	if len(src.StatusAPI.Tokens) > 0 {
+		dst.StatusAPI.Tokens = append(dst.StatusAPI.Tokens, src.StatusAPI.Tokens...)
+	}
 	if len(src.Global) > 0 {
 		if dst.Global == nil {
 			dst.Global = make(map[string]interface{}, len(src.Global))
@@ -170,4 +315,10 @@ func mergeConfig(dst *Config, src *Config) {
 	if len(src.Checks) > 0 {
 		dst.Checks = append(dst.Checks, src.Checks...)
 	}
+	if len(src.Notifiers) > 0 {
+		dst.Notifiers = append(dst.Notifiers, src.Notifiers...)
+	}
+	if len(src.MaintenanceWindows) > 0 {
+		dst.MaintenanceWindows = append(dst.MaintenanceWindows, src.MaintenanceWindows...)
+	}
 }
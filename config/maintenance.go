@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Maintenance modes. In skip mode scheduled runs are not started during the
+// window; in both modes results are recorded as maintenance.
+const (
+	MaintenanceModeSkip   = "skip"
+	MaintenanceModeRecord = "record"
+)
+
+// MaintenanceWindowConfig is a planned window, either recurring (cron plus
+// duration) or fixed (start and end in RFC 3339). It applies to the listed
+// checks and to checks with any of the listed tags; "*" matches every check.
+type MaintenanceWindowConfig struct {
+	Name     string   `yaml:"name"`
+	Cron     string   `yaml:"cron,omitempty"`
+	Duration string   `yaml:"duration,omitempty"`
+	Start    string   `yaml:"start,omitempty"`
+	End      string   `yaml:"end,omitempty"`
+	Checks   []string `yaml:"checks,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+	Mode     string   `yaml:"mode,omitempty"`
+	Comment  string   `yaml:"comment,omitempty"`
+}
+
+// Times returns the parsed start and end of a fixed window.
+func (w MaintenanceWindowConfig) Times() (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start must be an RFC 3339 time")
+	}
+	end, err := time.Parse(time.RFC3339, w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be an RFC 3339 time")
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
+
+func validateMaintenanceWindows(windows []MaintenanceWindowConfig, checks []CheckConfig) error {
+	names := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		names[check.Name] = true
+	}
+	seen := make(map[string]bool, len(windows))
+	for i, w := range windows {
+		if w.Name == "" {
+			return fmt.Errorf("maintenance window %d: name is required", i+1)
+		}
+		if seen[w.Name] {
+			return fmt.Errorf("maintenance window %s: duplicate name", w.Name)
+		}
+		seen[w.Name] = true
+
+		recurring := w.Cron != "" || w.Duration != ""
+		fixed := w.Start != "" || w.End != ""
+		switch {
+		case recurring && fixed:
+			return fmt.Errorf("maintenance window %s: use either cron and duration or start and end", w.Name)
+		case recurring:
+			if w.Cron == "" {
+				return fmt.Errorf("maintenance window %s: cron is required with duration", w.Name)
+			}
+			duration, err := time.ParseDuration(w.Duration)
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("maintenance window %s: duration must be a positive duration", w.Name)
+			}
+		case fixed:
+			if _, _, err := w.Times(); err != nil {
+				return fmt.Errorf("maintenance window %s: %w", w.Name, err)
+			}
+		default:
+			return fmt.Errorf("maintenance window %s: cron and duration or start and end are required", w.Name)
+		}
+
+		if len(w.Checks) == 0 && len(w.Tags) == 0 {
+			return fmt.Errorf("maintenance window %s: checks or tags are required", w.Name)
+		}
+		for _, name := range w.Checks {
+			if name != "*" && !names[name] {
+				return fmt.Errorf("maintenance window %s: unknown check %q", w.Name, name)
+			}
+		}
+		switch w.Mode {
+		case "", MaintenanceModeSkip, MaintenanceModeRecord:
+		default:
+			return fmt.Errorf("maintenance window %s: mode must be one of %s, %s", w.Name, MaintenanceModeSkip, MaintenanceModeRecord)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	NetworkModeBridge = "bridge"
+	NetworkModeHost   = "host"
+	NetworkModeNone   = "none"
+)
+
+// NetworkConfig controls the networking of a check container. Without it the
+// container joins Docker's default bridge network.
+type NetworkConfig struct {
+	Mode       string   `yaml:"mode,omitempty"`
+	Networks   []string `yaml:"networks,omitempty"`
+	DNS        []string `yaml:"dns,omitempty"`
+	DNSSearch  []string `yaml:"dns_search,omitempty"`
+	DNSOptions []string `yaml:"dns_options,omitempty"`
+	ExtraHosts []string `yaml:"extra_hosts,omitempty"`
+}
+
+// UnmarshalYAML also accepts a plain string, so `network: none` selects a
+// mode and `network: backend` attaches a single named network.
+func (n *NetworkConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var name string
+		if err := value.Decode(&name); err != nil {
+			return err
+		}
+		*n = NetworkConfig{}
+		switch name {
+		case "":
+		case NetworkModeBridge, NetworkModeHost, NetworkModeNone:
+			n.Mode = name
+		default:
+			n.Networks = []string{name}
+		}
+		return nil
+	}
+
+	type plain NetworkConfig
+	return value.Decode((*plain)(n))
+}
+
+var networkNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+func validateNetworkConfig(subject string, n NetworkConfig) error {
+	switch n.Mode {
+	case "", NetworkModeBridge, NetworkModeHost, NetworkModeNone:
+	default:
+		return fmt.Errorf("%s: mode must be one of bridge, host, none", subject)
+	}
+	isolated := n.Mode == NetworkModeHost || n.Mode == NetworkModeNone
+	if isolated && len(n.Networks) > 0 {
+		return fmt.Errorf("%s: networks cannot be combined with mode %s", subject, n.Mode)
+	}
+	if isolated && (len(n.DNS) > 0 || len(n.DNSSearch) > 0 || len(n.DNSOptions) > 0) {
+		return fmt.Errorf("%s: dns settings cannot be combined with mode %s", subject, n.Mode)
+	}
+
+	seen := make(map[string]bool, len(n.Networks))
+	for _, name := range n.Networks {
+		if !networkNamePattern.MatchString(name) {
+			return fmt.Errorf("%s: invalid network name %q", subject, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s: network %q listed more than once", subject, name)
+		}
+		seen[name] = true
+	}
+	for _, server := range n.DNS {
+		if net.ParseIP(server) == nil {
+			return fmt.Errorf("%s: dns server %q must be an IP address", subject, server)
+		}
+	}
+	for _, domain := range n.DNSSearch {
+		if domain == "" || strings.ContainsAny(domain, " \t") {
+			return fmt.Errorf("%s: invalid dns_search domain %q", subject, domain)
+		}
+	}
+	for _, entry := range n.ExtraHosts {
+		if err := validateExtraHost(entry); err != nil {
+			return fmt.Errorf("%s: %w", subject, err)
+		}
+	}
+	return nil
+}
+
+// validateExtraHost accepts Docker's "host:ip" form, where ip may be an IPv6
+// address or the special host-gateway value.
+func validateExtraHost(entry string) error {
+	host, ip, ok := strings.Cut(entry, ":")
+	if !ok || host == "" || strings.ContainsAny(host, " \t") {
+		return fmt.Errorf("extra_hosts entry %q must be host:ip", entry)
+	}
+	if ip != "host-gateway" && net.ParseIP(ip) == nil {
+		return fmt.Errorf("extra_hosts entry %q has an invalid IP address", entry)
+	}
+	return nil
+}
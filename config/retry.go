@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Backoff strategies for the delay between retries of a failed check.
+const (
+	BackoffConstant    = "constant"
+	BackoffExponential = "exponential"
+)
+
+const (
+	MaxRetries           = 10
+	DefaultRetryDelay    = 5 * time.Second
+	DefaultRetryMaxDelay = 5 * time.Minute
+)
+
+func validateRetryConfig(subject string, c CheckConfig) error {
+	if c.Retries < 0 || c.Retries > MaxRetries {
+		return fmt.Errorf("%s: retries must be between 0 and %d", subject, MaxRetries)
+	}
+	if c.RetryDelay != "" {
+		delay, err := time.ParseDuration(c.RetryDelay)
+		if err != nil || delay < 0 {
+			return fmt.Errorf("%s: retry_delay must be a non-negative duration", subject)
+		}
+	}
+	if c.RetryMaxDelay != "" {
+		maxDelay, err := time.ParseDuration(c.RetryMaxDelay)
+		if err != nil || maxDelay <= 0 {
+			return fmt.Errorf("%s: retry_max_delay must be a positive duration", subject)
+		}
+		if maxDelay < c.RetryDelayDuration() {
+			return fmt.Errorf("%s: retry_max_delay cannot be shorter than retry_delay", subject)
+		}
+	}
+	switch c.Backoff {
+	case "", BackoffConstant, BackoffExponential:
+	default:
+		return fmt.Errorf("%s: backoff must be one of %s, %s", subject, BackoffConstant, BackoffExponential)
+	}
+	if c.FailureThreshold < 0 {
+		return fmt.Errorf("%s: failure_threshold cannot be negative", subject)
+	}
+	if c.RecoveryThreshold < 0 {
+		return fmt.Errorf("%s: recovery_threshold cannot be negative", subject)
+	}
+	return nil
+}
+
+// RetryDelayDuration returns the delay before the first retry, defaulting to
+// DefaultRetryDelay when retry_delay is not set.
+func (c CheckConfig) RetryDelayDuration() time.Duration {
+	if c.RetryDelay == "" {
+		return DefaultRetryDelay
+	}
+	delay, err := time.ParseDuration(c.RetryDelay)
+	if err != nil {
+		return DefaultRetryDelay
+	}
+	return delay
+}
+
+// RetryMaxDelayDuration returns the bound of the exponential backoff,
+// defaulting to DefaultRetryMaxDelay or the retry delay if that is longer.
+func (c CheckConfig) RetryMaxDelayDuration() time.Duration {
+	maxDelay, err := time.ParseDuration(c.RetryMaxDelay)
+	if err != nil || maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	return max(maxDelay, c.RetryDelayDuration())
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+func validateSpread(subject, splay, jitter string) error {
+	for _, option := range []struct{ name, value string }{{"splay", splay}, {"jitter", jitter}} {
+		if option.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(option.value); err != nil || d < 0 {
+			return fmt.Errorf("%s: %s must be a non-negative duration", subject, option.name)
+		}
+	}
+	return nil
+}
+
+// validateScheduleSpread checks the splay and jitter of a check. Splay
+// offsets interval checks and jitter delays cron checks, so each is rejected
+// on the other schedule type. Windows replace the start of the schedule, so
+// splay is rejected on them as well.
+func validateScheduleSpread(subject string, s Schedule) error {
+	if err := validateSpread(subject, s.Splay, s.Jitter); err != nil {
+		return err
+	}
+	if s.Splay != "" && s.Interval == "" {
+		return fmt.Errorf("%s: splay only applies to interval schedules, use jitter for cron", subject)
+	}
+	if s.Splay != "" && len(s.Windows) > 0 {
+		return fmt.Errorf("%s: splay cannot be combined with windows", subject)
+	}
+	if s.Jitter != "" && !s.usesCron() {
+		return fmt.Errorf("%s: jitter only applies to cron schedules, use splay for intervals", subject)
+	}
+	return nil
+}
+
+// resolveScheduleSpread applies the global splay to interval checks without
+// windows and the global jitter to checks with cron runs that do not set their
+// own.
+func resolveScheduleSpread(cfg *Config) {
+	for i := range cfg.Checks {
+		schedule := &cfg.Checks[i].Schedule
+		if schedule.Interval != "" && len(schedule.Windows) == 0 && schedule.Splay == "" {
+			schedule.Splay = cfg.Splay
+		}
+		if schedule.usesCron() && schedule.Jitter == "" {
+			schedule.Jitter = cfg.Jitter
+		}
+	}
+}
+
+// SplayDuration returns the splay bound of an interval schedule, or zero.
+func (s Schedule) SplayDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Splay)
+	return d
+}
+
+// JitterDuration returns the jitter bound of the cron runs of a schedule, or
+// zero.
+func (s Schedule) JitterDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Jitter)
+	return d
+}
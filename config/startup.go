@@ -0,0 +1,32 @@
+package config
+
+import "fmt"
+
+const (
+	OnStartRun       = "run"
+	OnStartSkip      = "skip"
+	OnStartIfOverdue = "if_overdue"
+
+	CatchUpNone = "none"
+	CatchUpOnce = "once"
+)
+
+// validateStartPolicy checks on_start and catch_up. Missed runs are only
+// caught up by checks that run at startup when overdue, so catch_up is
+// rejected with the other on_start policies.
+func validateStartPolicy(subject string, s Schedule) error {
+	switch s.OnStart {
+	case "", OnStartRun, OnStartSkip, OnStartIfOverdue:
+	default:
+		return fmt.Errorf("%s: on_start must be one of %s, %s, %s", subject, OnStartRun, OnStartSkip, OnStartIfOverdue)
+	}
+	switch s.CatchUp {
+	case "", CatchUpNone, CatchUpOnce:
+	default:
+		return fmt.Errorf("%s: catch_up must be one of %s, %s", subject, CatchUpNone, CatchUpOnce)
+	}
+	if s.CatchUp != "" && s.OnStart != "" && s.OnStart != OnStartIfOverdue {
+		return fmt.Errorf("%s: catch_up only applies with on_start %s", subject, OnStartIfOverdue)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/pfarrer/foghorn/secretstore"
+)
+
+const (
+	StatusScopeRead     = "read"
+	StatusScopeOperator = "operator"
+)
+
+// StatusAPIConfig secures the daemon status API. Without TLS settings the API
+// is served over plain HTTP, and without tokens it accepts every request.
+type StatusAPIConfig struct {
+	TLS    StatusTLSConfig     `yaml:"tls,omitempty"`
+	Tokens []StatusTokenConfig `yaml:"tokens,omitempty"`
+}
+
+type StatusTLSConfig struct {
+	CertFile     string `yaml:"cert_file,omitempty"`
+	KeyFile      string `yaml:"key_file,omitempty"`
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
+}
+
+// StatusTokenConfig grants a bearer token access to the status API. Token
+// must be a secret:// reference into the secret store.
+type StatusTokenConfig struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+	Scope string `yaml:"scope,omitempty"`
+}
+
+func (t StatusTokenConfig) EffectiveScope() string {
+	if t.Scope == "" {
+		return StatusScopeRead
+	}
+	return t.Scope
+}
+
+func validateStatusAPIConfig(c StatusAPIConfig) error {
+	tlsConfig := c.TLS
+	if (tlsConfig.CertFile == "") != (tlsConfig.KeyFile == "") {
+		return fmt.Errorf("status_api.tls: cert_file and key_file must be set together")
+	}
+	if tlsConfig.ClientCAFile != "" && tlsConfig.CertFile == "" {
+		return fmt.Errorf("status_api.tls: client_ca_file requires cert_file and key_file")
+	}
+
+	seen := make(map[string]bool, len(c.Tokens))
+	for i, token := range c.Tokens {
+		if token.Name == "" {
+			return fmt.Errorf("status_api.tokens[%d]: name is required", i)
+		}
+		if seen[token.Name] {
+			return fmt.Errorf("status_api.tokens: duplicate token name %q", token.Name)
+		}
+		seen[token.Name] = true
+		if _, ok := secretstore.ParseRef(token.Token); !ok {
+			return fmt.Errorf("status_api.tokens[%s]: token must be a %s reference", token.Name, secretstore.RefPrefix)
+		}
+		switch token.Scope {
+		case "", StatusScopeRead, StatusScopeOperator:
+		default:
+			return fmt.Errorf("status_api.tokens[%s]: scope must be %s or %s", token.Name, StatusScopeRead, StatusScopeOperator)
+		}
+	}
+	return nil
+}
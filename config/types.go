@@ -1,8 +1,18 @@
 package config
 
+import "github.com/pfarrer/foghorn/evaluator"
+
 type Schedule struct {
 	Cron     string `yaml:"cron,omitempty"`
 	Interval string `yaml:"interval,omitempty"`
+	Timezone string `yaml:"timezone,omitempty"`
+	Splay    string `yaml:"splay,omitempty"`
+	Jitter   string `yaml:"jitter,omitempty"`
+	OnStart  string `yaml:"on_start,omitempty"`
+	CatchUp  string `yaml:"catch_up,omitempty"`
+	// Windows are tried in order. Outside all of them the check follows
+	// cron or interval, or does not run when neither is set.
+	Windows []ScheduleWindow `yaml:"windows,omitempty"`
 }
 
 type EvaluationRule struct {
@@ -10,6 +20,7 @@ type EvaluationRule struct {
 	Condition string                 `yaml:"condition"`
 	Threshold float64                `yaml:"threshold,omitempty"`
 	Expected  interface{}            `yaml:"expected,omitempty"`
+	Severity  string                 `yaml:"severity,omitempty"`
 	Metadata  map[string]interface{} `yaml:"metadata,omitempty"`
 }
 
@@ -20,21 +31,99 @@ type CheckConfig struct {
 	Evaluation                []EvaluationRule       `yaml:"evaluation"`
 	Description               string                 `yaml:"description,omitempty"`
 	Tags                      []string               `yaml:"tags,omitempty"`
+	DependsOn                 []string               `yaml:"depends_on,omitempty"`
 	Enabled                   bool                   `yaml:"enabled"`
 	Env                       map[string]string      `yaml:"env,omitempty"`
 	Timeout                   string                 `yaml:"timeout,omitempty"`
+	Retries                   int                    `yaml:"retries,omitempty"`
+	RetryDelay                string                 `yaml:"retry_delay,omitempty"`
+	RetryMaxDelay             string                 `yaml:"retry_max_delay,omitempty"`
+	Backoff                   string                 `yaml:"backoff,omitempty"`
+	FailureThreshold          int                    `yaml:"failure_threshold,omitempty"`
+	RecoveryThreshold         int                    `yaml:"recovery_threshold,omitempty"`
 	CheckContainerDebugOutput string                 `yaml:"check_container_debug_output,omitempty"`
+	Container                 ContainerConfig        `yaml:"container,omitempty"`
+	Network                   NetworkConfig          `yaml:"network,omitempty"`
 	Metadata                  map[string]interface{} `yaml:"metadata,omitempty"`
+
+	// evaluation holds the compiled evaluation rules, set when the
+	// configuration is loaded.
+	evaluation *evaluator.RuleSet
+}
+
+type NotifierConfig struct {
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	Tags     []string          `yaml:"tags,omitempty"`
+	Statuses []string          `yaml:"statuses,omitempty"`
+	Template string            `yaml:"template,omitempty"`
+	Timeout  string            `yaml:"timeout,omitempty"`
+	URL      string            `yaml:"url,omitempty"`
+	Method   string            `yaml:"method,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	SMTPHost string            `yaml:"smtp_host,omitempty"`
+	SMTPPort int               `yaml:"smtp_port,omitempty"`
+	Username string            `yaml:"username,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	From     string            `yaml:"from,omitempty"`
+	To       []string          `yaml:"to,omitempty"`
+	Subject  string            `yaml:"subject,omitempty"`
+	Command  []string          `yaml:"command,omitempty"`
+	Env      map[string]string `yaml:"env,omitempty"`
 }
 
+// State backends selected with state_backend. The log backend is the
+// default.
+const (
+	StateBackendLog    = "log"
+	StateBackendSQLite = "sqlite"
+)
+
 type Config struct {
-	Checks                    []CheckConfig          `yaml:"checks"`
-	Global                    map[string]interface{} `yaml:"global,omitempty"`
-	Version                   string                 `yaml:"version,omitempty"`
-	MaxConcurrentChecks       int                    `yaml:"max_concurrent_checks,omitempty"`
-	StateLogFile              string                 `yaml:"state_log_file,omitempty"`
-	StateLogPeriod            string                 `yaml:"state_log_period,omitempty"`
-	SecretStoreFile           string                 `yaml:"secret_store_file,omitempty"`
-	CheckContainerDebugOutput string                 `yaml:"check_container_debug_output,omitempty"`
-	DebugOutputMaxChars       int                    `yaml:"debug_output_max_chars,omitempty"`
+	Checks                    []CheckConfig             `yaml:"checks"`
+	Notifiers                 []NotifierConfig          `yaml:"notifiers,omitempty"`
+	MaintenanceWindows        []MaintenanceWindowConfig `yaml:"maintenance_windows,omitempty"`
+	Global                    map[string]interface{}    `yaml:"global,omitempty"`
+	Version                   string                    `yaml:"version,omitempty"`
+	MaxConcurrentChecks       int                       `yaml:"max_concurrent_checks,omitempty"`
+	StateLogFile              string                    `yaml:"state_log_file,omitempty"`
+	StateLogPeriod            string                    `yaml:"state_log_period,omitempty"`
+	StateBackend              string                    `yaml:"state_backend,omitempty"`
+	Timezone                  string                    `yaml:"timezone,omitempty"`
+	Splay                     string                    `yaml:"splay,omitempty"`
+	Jitter                    string                    `yaml:"jitter,omitempty"`
+	SecretStoreFile           string                    `yaml:"secret_store_file,omitempty"`
+	CheckContainerDebugOutput string                    `yaml:"check_container_debug_output,omitempty"`
+	DebugOutputMaxChars       int                       `yaml:"debug_output_max_chars,omitempty"`
+	MetricsExportData         bool                      `yaml:"metrics_export_data,omitempty"`
+	ContainerDefaults         ContainerConfig           `yaml:"container_defaults,omitempty"`
+	StatusAPI                 StatusAPIConfig           `yaml:"status_api,omitempty"`
+}
+
+// Evaluate applies the evaluation rules of the check to a result. The rules
+// are compiled when the configuration is loaded; checks built otherwise
+// compile them on every call.
+func (c *CheckConfig) Evaluate(result evaluator.Result) evaluator.Outcome {
+	if c.evaluation != nil {
+		return c.evaluation.Evaluate(result)
+	}
+	return evaluator.Evaluate(c.evaluatorRules(), result)
+}
+
+func (c *CheckConfig) evaluatorRules() []evaluator.Rule {
+	rules := make([]evaluator.Rule, 0, len(c.Evaluation))
+	for _, rule := range c.Evaluation {
+		rules = append(rules, rule.EvaluatorRule())
+	}
+	return rules
+}
+
+func (r EvaluationRule) EvaluatorRule() evaluator.Rule {
+	return evaluator.Rule{
+		Type:      r.Type,
+		Condition: r.Condition,
+		Threshold: r.Threshold,
+		Expected:  r.Expected,
+		Severity:  r.Severity,
+	}
 }
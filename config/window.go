@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// ScheduleWindow runs a check on its own interval or cron schedule between
+// start and end on the listed days. A window whose end is before its start
+// runs past midnight and belongs to the day it starts on. Without start and
+// end it covers whole days, without days every day.
+type ScheduleWindow struct {
+	Name     string   `yaml:"name,omitempty"`
+	Days     []string `yaml:"days,omitempty"`
+	Start    string   `yaml:"start,omitempty"`
+	End      string   `yaml:"end,omitempty"`
+	Timezone string   `yaml:"timezone,omitempty"`
+	Interval string   `yaml:"interval,omitempty"`
+	Cron     string   `yaml:"cron,omitempty"`
+}
+
+// Weekdays returns the days of the window, such as MON-FRI or SAT, in order.
+// It returns nil for every day.
+func (w ScheduleWindow) Weekdays() ([]time.Weekday, error) {
+	var days [7]bool
+	for _, spec := range w.Days {
+		first, last, isRange := strings.Cut(strings.ToUpper(strings.TrimSpace(spec)), "-")
+		if !isRange {
+			last = first
+		}
+		from, err := parseWeekday(first)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseWeekday(last)
+		if err != nil {
+			return nil, err
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	var out []time.Weekday
+	for day, set := range days {
+		if set {
+			out = append(out, time.Weekday(day))
+		}
+	}
+	return out, nil
+}
+
+func parseWeekday(name string) (int, error) {
+	for day, weekday := range weekdayNames {
+		if name == weekday {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q (use SUN-SAT)", name)
+}
+
+// Hours returns the start and end of the window as offsets from midnight.
+// Both are zero for a window that covers whole days.
+func (w ScheduleWindow) Hours() (time.Duration, time.Duration, error) {
+	if w.Start == "" && w.End == "" {
+		return 0, 0, nil
+	}
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start: %w", err)
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("start and end must differ")
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a time of day such as 08:00, got %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func validateScheduleWindows(subject string, windows []ScheduleWindow) error {
+	seen := make(map[string]bool, len(windows))
+	for i, w := range windows {
+		name := w.Name
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		windowSubject := fmt.Sprintf("%s: window %s", subject, name)
+		if seen[name] {
+			return fmt.Errorf("%s: duplicate name", windowSubject)
+		}
+		seen[name] = true
+		if (w.Interval == "") == (w.Cron == "") {
+			return fmt.Errorf("%s: exactly one of cron or interval is required", windowSubject)
+		}
+		if _, err := w.Weekdays(); err != nil {
+			return fmt.Errorf("%s: days: %w", windowSubject, err)
+		}
+		if _, _, err := w.Hours(); err != nil {
+			return fmt.Errorf("%s: %w", windowSubject, err)
+		}
+		if _, err := LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("%s: timezone: %w", windowSubject, err)
+		}
+	}
+	return nil
+}
+
+// usesCron reports whether any runs of the schedule follow a cron
+// expression.
+func (s Schedule) usesCron() bool {
+	if s.Cron != "" {
+		return true
+	}
+	for _, w := range s.Windows {
+		if w.Cron != "" {
+			return true
+		}
+	}
+	return false
+}
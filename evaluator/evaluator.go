@@ -0,0 +1,227 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeJSON      = "json"
+	TypeThreshold = "threshold"
+
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+type Rule struct {
+	Type      string
+	Condition string
+	Threshold float64
+	Expected  interface{}
+	Severity  string
+}
+
+type Result struct {
+	Status     string
+	Message    string
+	Data       map[string]interface{}
+	Timestamp  string
+	DurationMs int64
+}
+
+type RuleFailure struct {
+	Rule   Rule
+	Status string
+	Reason string
+}
+
+type Outcome struct {
+	Status   string
+	Failures []RuleFailure
+}
+
+// RuleSet holds rules whose conditions are compiled once, so evaluating a
+// result only executes them.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	expr *Expression
+	err  error
+}
+
+func Validate(rule Rule) error {
+	_, err := compileRule(rule)
+	return err
+}
+
+// CompileRules validates rules and compiles their conditions.
+func CompileRules(rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		expr, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("evaluation rule %d: %w", i+1, err)
+		}
+		set.rules = append(set.rules, compiledRule{Rule: rule, expr: expr})
+	}
+	return set, nil
+}
+
+func compileRule(rule Rule) (*Expression, error) {
+	switch rule.Type {
+	case TypeJSON, TypeThreshold:
+	default:
+		return nil, fmt.Errorf("unsupported evaluation type %q (must be json or threshold)", rule.Type)
+	}
+	if strings.TrimSpace(rule.Condition) == "" {
+		return nil, fmt.Errorf("condition is required")
+	}
+	expr, err := Compile(rule.Condition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", rule.Condition, err)
+	}
+	switch normalizeSeverity(rule.Severity) {
+	case StatusWarn, StatusFail:
+	default:
+		return nil, fmt.Errorf("severity must be one of warn, fail")
+	}
+	switch rule.Expected.(type) {
+	case nil, bool, string, int, int64, float64:
+	default:
+		return nil, fmt.Errorf("expected must be a scalar value")
+	}
+	return expr, nil
+}
+
+// Evaluate applies rules to a check result and derives the final status.
+// Conditions are compiled on every call; checks that run repeatedly use a
+// RuleSet from CompileRules instead.
+func Evaluate(rules []Rule, result Result) Outcome {
+	set := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	for _, rule := range rules {
+		expr, err := Compile(rule.Condition)
+		set.rules = append(set.rules, compiledRule{Rule: rule, expr: expr, err: err})
+	}
+	return set.Evaluate(result)
+}
+
+// Evaluate applies the rules to a check result and derives the final status.
+// Rules start from the status reported by the check container and can only
+// make it worse, so a container error is never hidden by passing rules.
+func (s *RuleSet) Evaluate(result Result) Outcome {
+	if s == nil || len(s.rules) == 0 {
+		return Outcome{Status: result.Status}
+	}
+
+	env := resultEnv(result)
+	outcome := Outcome{Status: result.Status}
+	if outcome.Status == "" {
+		outcome.Status = StatusPass
+	}
+	for _, rule := range s.rules {
+		ok, reason := evaluateRule(rule, env)
+		if ok {
+			continue
+		}
+		severity := normalizeSeverity(rule.Severity)
+		outcome.Failures = append(outcome.Failures, RuleFailure{
+			Rule:   rule.Rule,
+			Status: severity,
+			Reason: reason,
+		})
+		if statusRank(severity) > statusRank(outcome.Status) {
+			outcome.Status = severity
+		}
+	}
+	return outcome
+}
+
+func (o Outcome) Summary() string {
+	if len(o.Failures) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(o.Failures))
+	for _, failure := range o.Failures {
+		parts = append(parts, fmt.Sprintf("%s (%s)", failure.Rule.Condition, failure.Reason))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func evaluateRule(rule compiledRule, env map[string]interface{}) (bool, string) {
+	if rule.err != nil {
+		return false, fmt.Sprintf("invalid condition: %v", rule.err)
+	}
+	expr := rule.expr
+
+	ruleEnv := env
+	if rule.Type == TypeThreshold {
+		ruleEnv = make(map[string]interface{}, len(env)+1)
+		for k, v := range env {
+			ruleEnv[k] = v
+		}
+		ruleEnv["threshold"] = rule.Threshold
+	}
+
+	value, err := expr.Eval(ruleEnv)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if rule.Type == TypeThreshold && expr.IsPath() {
+		number, ok := toFloat(value)
+		if !ok {
+			return false, fmt.Sprintf("%s is %s, not a number", rule.Condition, describe(value))
+		}
+		if number > rule.Threshold {
+			return false, fmt.Sprintf("%s is %v, above threshold %v", rule.Condition, number, rule.Threshold)
+		}
+		return true, ""
+	}
+
+	expected := rule.Expected
+	if expected == nil {
+		expected = true
+	}
+	expected = normalizeValue(expected)
+	if !valuesEqual(value, expected) {
+		return false, fmt.Sprintf("got %s, expected %s", describe(value), describe(expected))
+	}
+	return true, ""
+}
+
+func resultEnv(result Result) map[string]interface{} {
+	data := make(map[string]interface{}, len(result.Data))
+	for k, v := range result.Data {
+		data[k] = v
+	}
+	return map[string]interface{}{
+		"status":      result.Status,
+		"message":     result.Message,
+		"timestamp":   result.Timestamp,
+		"duration_ms": float64(result.DurationMs),
+		"data":        data,
+	}
+}
+
+func normalizeSeverity(severity string) string {
+	severity = strings.TrimSpace(severity)
+	if severity == "" {
+		return StatusFail
+	}
+	return severity
+}
+
+func statusRank(status string) int {
+	switch status {
+	case StatusPass:
+		return 0
+	case StatusWarn:
+		return 1
+	default:
+		return 2
+	}
+}
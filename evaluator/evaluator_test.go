@@ -0,0 +1,236 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		errMsg string
+	}{
+		{name: "unterminated string", expr: "status == 'pass", errMsg: "unterminated string"},
+		{name: "missing operand", expr: "status ==", errMsg: "expected value"},
+		{name: "unbalanced paren", expr: "(status == 'pass'", errMsg: "expected ')'"},
+		{name: "trailing token", expr: "status 'pass'", errMsg: "unexpected"},
+		{name: "invalid character", expr: "status = 'pass'", errMsg: "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			if err == nil {
+				t.Fatalf("Compile(%q) should fail", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("error = %q, want substring %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestExpressionEval(t *testing.T) {
+	env := resultEnv(Result{
+		Status:  "pass",
+		Message: "ok",
+		Data: map[string]interface{}{
+			"latency_ms": float64(120),
+			"code":       "200",
+			"tls":        map[string]interface{}{"days_left": float64(12)},
+			"healthy":    true,
+		},
+	})
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{expr: "status == 'pass'", want: true},
+		{expr: "status != \"pass\"", want: false},
+		{expr: "data.latency_ms < 500", want: true},
+		{expr: "data.latency_ms >= 500", want: false},
+		{expr: "data.code == 200", want: true},
+		{expr: "data.tls.days_left > 14 || status == 'warn'", want: false},
+		{expr: "data.healthy && !(status == 'fail')", want: true},
+		{expr: "data.missing == null", want: true},
+		{expr: "data.latency_ms > -1 and status == 'pass'", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err := expr.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionEvalTypeError(t *testing.T) {
+	expr, err := Compile("data.missing < 500")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if _, err := expr.Eval(resultEnv(Result{})); err == nil {
+		t.Fatalf("Eval() should fail comparing null with a number")
+	}
+}
+
+func TestEvaluateWithoutRulesKeepsStatus(t *testing.T) {
+	outcome := Evaluate(nil, Result{Status: "warn"})
+	if outcome.Status != "warn" {
+		t.Fatalf("Status = %q, want warn", outcome.Status)
+	}
+}
+
+func TestEvaluateCombinesRules(t *testing.T) {
+	rules := []Rule{
+		{Type: TypeJSON, Condition: "status != 'fail'"},
+		{Type: TypeJSON, Condition: "status == 'pass'", Severity: "warn"},
+		{Type: TypeThreshold, Condition: "data.latency_ms", Threshold: 500},
+	}
+
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{
+			name:   "all rules pass",
+			result: Result{Status: "pass", Data: map[string]interface{}{"latency_ms": float64(100)}},
+			want:   StatusPass,
+		},
+		{
+			name:   "warn severity rule fails",
+			result: Result{Status: "warn", Data: map[string]interface{}{"latency_ms": float64(100)}},
+			want:   StatusWarn,
+		},
+		{
+			name:   "threshold exceeded",
+			result: Result{Status: "pass", Data: map[string]interface{}{"latency_ms": float64(900)}},
+			want:   StatusFail,
+		},
+		{
+			name:   "reported fail overrides warn",
+			result: Result{Status: "fail", Data: map[string]interface{}{"latency_ms": float64(100)}},
+			want:   StatusFail,
+		},
+		{
+			name:   "missing threshold field fails",
+			result: Result{Status: "pass"},
+			want:   StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := Evaluate(rules, tt.result)
+			if outcome.Status != tt.want {
+				t.Fatalf("Status = %q, want %q (failures: %s)", outcome.Status, tt.want, outcome.Summary())
+			}
+		})
+	}
+}
+
+func TestEvaluateKeepsReportedError(t *testing.T) {
+	rules := []Rule{
+		{Type: TypeJSON, Condition: "message != 'down'"},
+		{Type: TypeThreshold, Condition: "data.latency_ms", Threshold: 500},
+	}
+	outcome := Evaluate(rules, Result{Status: "error", Message: "connection refused", Data: map[string]interface{}{"latency_ms": float64(100)}})
+	if outcome.Status != "error" || len(outcome.Failures) != 0 {
+		t.Fatalf("Status = %q, want the reported error (failures: %s)", outcome.Status, outcome.Summary())
+	}
+}
+
+func TestEvaluateExpectedValue(t *testing.T) {
+	rules := []Rule{{Type: TypeJSON, Condition: "status == 'pass'", Expected: false}}
+	if outcome := Evaluate(rules, Result{Status: "warn"}); outcome.Status != StatusWarn || len(outcome.Failures) != 0 {
+		t.Fatalf("Status = %q, want warn without failures (failures: %s)", outcome.Status, outcome.Summary())
+	}
+	if outcome := Evaluate(rules, Result{Status: "pass"}); outcome.Status != StatusFail {
+		t.Fatalf("Status = %q, want fail", outcome.Status)
+	}
+
+	rules = []Rule{{Type: TypeJSON, Condition: "data.code", Expected: 200}}
+	if outcome := Evaluate(rules, Result{Status: "pass", Data: map[string]interface{}{"code": float64(200)}}); outcome.Status != StatusPass {
+		t.Fatalf("Status = %q, want pass (failures: %s)", outcome.Status, outcome.Summary())
+	}
+}
+
+func TestEvaluateThresholdExpression(t *testing.T) {
+	rules := []Rule{{Type: TypeThreshold, Condition: "data.days_left >= threshold", Threshold: 14, Severity: "warn"}}
+
+	outcome := Evaluate(rules, Result{Status: "pass", Data: map[string]interface{}{"days_left": float64(7)}})
+	if outcome.Status != StatusWarn {
+		t.Fatalf("Status = %q, want warn", outcome.Status)
+	}
+	if len(outcome.Failures) != 1 {
+		t.Fatalf("Failures = %d, want 1", len(outcome.Failures))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{name: "valid json", rule: Rule{Type: TypeJSON, Condition: "status == 'pass'"}},
+		{name: "valid threshold", rule: Rule{Type: TypeThreshold, Condition: "data.latency_ms", Threshold: 500}},
+		{name: "unknown type", rule: Rule{Type: "regex", Condition: "x"}, wantErr: "unsupported evaluation type"},
+		{name: "missing condition", rule: Rule{Type: TypeJSON}, wantErr: "condition is required"},
+		{name: "bad condition", rule: Rule{Type: TypeJSON, Condition: "status =="}, wantErr: "invalid condition"},
+		{name: "bad severity", rule: Rule{Type: TypeJSON, Condition: "true", Severity: "critical"}, wantErr: "severity must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.rule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want substring %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompileRules(t *testing.T) {
+	rules := []Rule{
+		{Type: TypeJSON, Condition: "status != 'fail'"},
+		{Type: TypeThreshold, Condition: "data.latency_ms", Threshold: 500, Severity: "warn"},
+	}
+	set, err := CompileRules(rules)
+	if err != nil {
+		t.Fatalf("CompileRules() error = %v", err)
+	}
+	for _, result := range []Result{
+		{Status: "pass", Data: map[string]interface{}{"latency_ms": float64(100)}},
+		{Status: "pass", Data: map[string]interface{}{"latency_ms": float64(900)}},
+		{Status: "error"},
+	} {
+		got, want := set.Evaluate(result), Evaluate(rules, result)
+		if got.Status != want.Status || got.Summary() != want.Summary() {
+			t.Errorf("compiled outcome = %+v, want %+v", got, want)
+		}
+	}
+
+	_, err = CompileRules(append(rules, Rule{Type: TypeJSON, Condition: "status =="}))
+	if err == nil || !strings.Contains(err.Error(), "evaluation rule 3: invalid condition") {
+		t.Fatalf("CompileRules() error = %v, want the invalid third rule", err)
+	}
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	CheckQueued    = "check.queued"
+	CheckStarted   = "check.started"
+	CheckCompleted = "check.completed"
+	CheckRetrying  = "check.retrying"
+	ImagePulled    = "image.pulled"
+	ConfigReloaded = "config.reloaded"
+)
+
+const (
+	defaultReplaySize = 256
+	subscriberBuffer  = 64
+)
+
+type Event struct {
+	ID      uint64                 `json:"id"`
+	Type    string                 `json:"type"`
+	Time    time.Time              `json:"time"`
+	Check   string                 `json:"check,omitempty"`
+	Status  string                 `json:"status,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus fans events out to subscribers. Publish never blocks: a subscriber
+// that falls behind misses events, which it can detect from gaps in the IDs.
+// The most recent events are kept so reconnecting clients can catch up.
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	replay      []Event
+	replaySize  int
+	subscribers map[chan Event]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		replaySize:  defaultReplaySize,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event.ID = b.nextID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.replay = append(b.replay, event)
+	if len(b.replay) > b.replaySize {
+		b.replay = b.replay[len(b.replay)-b.replaySize:]
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of events published after lastID. Pass 0 to
+// receive only new events. The returned function unsubscribes and closes
+// the channel.
+func (b *Bus) Subscribe(lastID uint64) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	if lastID > 0 {
+		for _, event := range b.replay {
+			if event.ID > lastID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	ch := make(chan Event, subscriberBuffer+len(backlog))
+	for _, event := range backlog {
+		ch <- event
+	}
+	b.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("no event received")
+		return Event{}
+	}
+}
+
+func TestBusPublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	first, unsubscribeFirst := bus.Subscribe(0)
+	second, unsubscribeSecond := bus.Subscribe(0)
+	defer unsubscribeSecond()
+
+	bus.Publish(Event{Type: CheckStarted, Check: "api"})
+	for _, ch := range []<-chan Event{first, second} {
+		event := receive(t, ch)
+		if event.ID != 1 || event.Type != CheckStarted || event.Check != "api" || event.Time.IsZero() {
+			t.Fatalf("event = %+v", event)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Fatalf("channel should be closed after unsubscribe")
+	}
+	bus.Publish(Event{Type: CheckCompleted, Check: "api"})
+	if event := receive(t, second); event.ID != 2 {
+		t.Fatalf("event ID = %d, want 2", event.ID)
+	}
+}
+
+func TestBusReplaysAfterLastID(t *testing.T) {
+	bus := NewBus()
+	for _, check := range []string{"a", "b", "c"} {
+		bus.Publish(Event{Type: CheckQueued, Check: check})
+	}
+
+	ch, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+	if event := receive(t, ch); event.Check != "b" {
+		t.Fatalf("first replayed event = %+v, want check b", event)
+	}
+	if event := receive(t, ch); event.Check != "c" {
+		t.Fatalf("second replayed event = %+v, want check c", event)
+	}
+}
+
+func TestBusDropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	_, unsubscribe := bus.Subscribe(0)
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*4; i++ {
+			bus.Publish(Event{Type: CheckStarted})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Publish blocked on a slow subscriber")
+	}
+}
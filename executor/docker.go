@@ -17,8 +17,11 @@ import (
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/evaluator"
+	"github.com/pfarrer/foghorn/events"
 	"github.com/pfarrer/foghorn/imageresolver"
 	"github.com/pfarrer/foghorn/logger"
 	"github.com/pfarrer/foghorn/scheduler"
@@ -37,13 +40,25 @@ type DockerExecutor struct {
 	cli            *client.Client
 	defaultTimeout time.Duration
 	outputLocation string
-	resultCallback func(checkName string, status string, duration time.Duration)
+	resultCallback func(result scheduler.Result)
 	resolveMu      sync.Mutex
 	resolvedImages map[string]string
 	secretResolver SecretResolver
 	secretBaseDir  string
 	debugOutput    string
 	debugMaxChars  int
+	statsMu        sync.Mutex
+	stats          Stats
+	runningMu      sync.Mutex
+	running        map[string]runningContainer
+	eventPublisher events.Publisher
+}
+
+// Stats counts executor events since the daemon started.
+type Stats struct {
+	ImagePulls        uint64
+	ImagePullFailures uint64
+	ContainerErrors   map[string]uint64
 }
 
 const (
@@ -106,25 +121,23 @@ func (e *DockerExecutor) Execute(check scheduler.CheckConfig) error {
 		}
 	}
 
-	startTime := time.Now()
+	report := scheduler.Result{
+		CheckName: checkName,
+		StartedAt: time.Now(),
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	image, err := e.resolveImage(ctx, checkConfig.Image)
 	if err != nil {
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
+		e.reportError(report, err)
 		logger.Error("Check %s: Failed to resolve image: %v", checkName, err)
 		return err
 	}
+	report.Image = image
 	if err := e.ensureImageAvailable(ctx, image, checkName); err != nil {
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
+		e.reportError(report, err)
 		logger.Error("Check %s: Failed to prepare image: %v", checkName, err)
 		return err
 	}
@@ -133,10 +146,7 @@ func (e *DockerExecutor) Execute(check scheduler.CheckConfig) error {
 
 	env, secretDir, secretsToRedact, err := e.buildEnvVars(checkConfig)
 	if err != nil {
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
+		e.reportError(report, err)
 		logger.Error("Check %s: Failed to prepare environment: %v", checkName, err)
 		return err
 	}
@@ -157,29 +167,45 @@ func (e *DockerExecutor) Execute(check scheduler.CheckConfig) error {
 	hostConfig := &container.HostConfig{
 		AutoRemove: false,
 	}
+	if err := applyContainerSettings(containerConfig, hostConfig, checkConfig.Container); err != nil {
+		e.reportError(report, err)
+		logger.Error("Check %s: Invalid container settings: %v", checkName, err)
+		return err
+	}
+	networkingConfig, extraNetworks := applyNetworkSettings(hostConfig, checkConfig.Network)
 	if secretDir != "" {
 		hostConfig.Binds = append(hostConfig.Binds, fmt.Sprintf("%s:/run/foghorn/secrets:ro", secretDir))
 	}
 
-	resp, err := e.cli.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
+	resp, err := e.cli.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, "")
 	if err != nil {
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
-		logger.Error("Check %s: Failed to create container: %v", checkName, err)
-		return fmt.Errorf("failed to create container: %w", err)
+		err = fmt.Errorf("failed to create container: %w", err)
+		e.countContainerError("create")
+		e.reportError(report, err)
+		logger.Error("Check %s: %v", checkName, err)
+		return err
 	}
 	logger.Debug("Check %s: Container created (ID: %s)", checkName, resp.ID)
-	defer e.cli.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true})
+	defer e.cli.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true, RemoveVolumes: true})
+	e.trackContainer(checkName, resp.ID, secretsToRedact)
+	defer e.untrackContainer(checkName, resp.ID)
+
+	for _, networkName := range extraNetworks {
+		if err := e.cli.NetworkConnect(ctx, networkName, resp.ID, &network.EndpointSettings{}); err != nil {
+			err = fmt.Errorf("failed to connect container to network %s: %w", networkName, err)
+			e.countContainerError("network")
+			e.reportError(report, err)
+			logger.Error("Check %s: %v", checkName, err)
+			return err
+		}
+	}
 
 	if err := e.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
-		logger.Error("Check %s: Failed to start container: %v", checkName, err)
-		return fmt.Errorf("failed to start container: %w", err)
+		err = fmt.Errorf("failed to start container: %w", err)
+		e.countContainerError("start")
+		e.reportError(report, err)
+		logger.Error("Check %s: %v", checkName, err)
+		return err
 	}
 	logger.Debug("Check %s: Container started (ID: %s)", checkName, resp.ID)
 
@@ -187,6 +213,8 @@ func (e *DockerExecutor) Execute(check scheduler.CheckConfig) error {
 
 	select {
 	case statusResult := <-statusCh:
+		exitCode := int(statusResult.StatusCode)
+		report.ExitCode = &exitCode
 		if statusResult.StatusCode != 0 {
 			if shouldLogContainerDebugOutput(debugMode, true) {
 				if err := e.logContainerDebugOutput(checkName, resp.ID, "failure", secretsToRedact); err != nil {
@@ -194,49 +222,202 @@ func (e *DockerExecutor) Execute(check scheduler.CheckConfig) error {
 				}
 			}
 
-			duration := time.Since(startTime)
-			if e.resultCallback != nil {
-				e.resultCallback(checkName, "error", duration)
-			}
+			err := fmt.Errorf("check failed with exit code %d", statusResult.StatusCode)
+			e.countContainerError("exit_code")
+			e.reportError(report, err)
 			logger.Error("Check %s: Failed with exit code %d", checkName, statusResult.StatusCode)
-			return fmt.Errorf("check failed with exit code %d", statusResult.StatusCode)
+			return err
 		}
 		result, err := e.readResult(ctx, resp.ID)
 		if err != nil {
-			duration := time.Since(startTime)
-			if e.resultCallback != nil {
-				e.resultCallback(checkName, "error", duration)
-			}
-			logger.Error("Check %s: Failed to read result: %v", checkName, err)
-			return fmt.Errorf("failed to read check result: %w", err)
+			err = fmt.Errorf("failed to read check result: %w", err)
+			e.countContainerError("result")
+			e.reportError(report, err)
+			logger.Error("Check %s: %v", checkName, err)
+			return err
 		}
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, result.Status, duration)
+		outcome := evaluateResult(checkConfig, result)
+		if len(outcome.Failures) > 0 {
+			logger.Info("Check %s: Evaluation rules failed: %s", checkName, outcome.Summary())
 		}
+		report.Status = outcome.Status
+		report.Message = resultMessage(result, outcome)
+		report.Data = result.Data
+		e.report(report)
 		if shouldLogContainerDebugOutput(debugMode, false) {
 			if err := e.logContainerDebugOutput(checkName, resp.ID, "success", secretsToRedact); err != nil {
 				logger.Debug("Check %s: Failed to read container output after success: %v", checkName, err)
 			}
 		}
-		logger.Info("Check %s: Completed with status %s (duration: %dms) - %s", checkName, result.Status, result.DurationMs, result.Message)
+		logger.Info("Check %s: Completed with status %s (reported: %s, duration: %dms) - %s", checkName, outcome.Status, result.Status, result.DurationMs, result.Message)
 		return nil
 	case err := <-errCh:
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
-		logger.Error("Check %s: Error waiting for container: %v", checkName, err)
-		return fmt.Errorf("error waiting for container: %w", err)
+		err = fmt.Errorf("error waiting for container: %w", err)
+		e.countContainerError("wait")
+		e.reportError(report, err)
+		logger.Error("Check %s: %v", checkName, err)
+		return err
 	case <-ctx.Done():
-		duration := time.Since(startTime)
-		if e.resultCallback != nil {
-			e.resultCallback(checkName, "error", duration)
-		}
+		err := fmt.Errorf("check execution timed out after %v", timeout)
+		e.countContainerError("timeout")
+		e.reportError(report, err)
 		logger.Warn("Check %s: Execution timed out after %v", checkName, timeout)
 		e.cli.ContainerKill(ctx, resp.ID, "SIGKILL")
-		return fmt.Errorf("check execution timed out after %v", timeout)
+		return err
+	}
+}
+
+// applyContainerSettings maps the resource limits and hardening options of a
+// check onto the container create request.
+func applyContainerSettings(containerConfig *container.Config, hostConfig *container.HostConfig, settings config.ContainerConfig) error {
+	memory, err := settings.MemoryBytes()
+	if err != nil {
+		return fmt.Errorf("invalid memory %q: %w", settings.Memory, err)
+	}
+	if memory > 0 {
+		hostConfig.Memory = memory
+		hostConfig.MemorySwap = memory
+	}
+	if settings.CPUs != nil && *settings.CPUs > 0 {
+		hostConfig.NanoCPUs = int64(*settings.CPUs * 1e9)
+	}
+	if settings.PidsLimit != nil && *settings.PidsLimit > 0 {
+		pidsLimit := *settings.PidsLimit
+		hostConfig.PidsLimit = &pidsLimit
+	}
+	if settings.ReadOnlyRootfs != nil && *settings.ReadOnlyRootfs {
+		hostConfig.ReadonlyRootfs = true
+		// Checks may still write their result file to /output.
+		containerConfig.Volumes = map[string]struct{}{"/output": {}}
+	}
+	hostConfig.CapDrop = normalizeCapabilities(settings.CapDrop)
+	hostConfig.CapAdd = normalizeCapabilities(settings.CapAdd)
+	if settings.NoNewPrivileges != nil && *settings.NoNewPrivileges {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "no-new-privileges:true")
+	}
+	containerConfig.User = settings.User
+
+	names := make([]string, 0, len(settings.Ulimits))
+	for name := range settings.Ulimits {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		limit := settings.Ulimits[name]
+		hostConfig.Ulimits = append(hostConfig.Ulimits, &container.Ulimit{Name: name, Soft: limit.Soft, Hard: limit.Hard})
+	}
+	if len(settings.Tmpfs) > 0 {
+		hostConfig.Tmpfs = make(map[string]string, len(settings.Tmpfs))
+		for target, options := range settings.Tmpfs {
+			hostConfig.Tmpfs[target] = options
+		}
+	}
+	return nil
+}
+
+// applyNetworkSettings sets the network mode and DNS options of a check. The
+// first named network is joined on create; the remaining networks are
+// returned so they can be connected before the container starts.
+func applyNetworkSettings(hostConfig *container.HostConfig, settings config.NetworkConfig) (*network.NetworkingConfig, []string) {
+	hostConfig.DNS = settings.DNS
+	hostConfig.DNSSearch = settings.DNSSearch
+	hostConfig.DNSOptions = settings.DNSOptions
+	hostConfig.ExtraHosts = settings.ExtraHosts
+
+	if len(settings.Networks) == 0 {
+		if settings.Mode != "" {
+			hostConfig.NetworkMode = container.NetworkMode(settings.Mode)
+		}
+		return nil, nil
+	}
+
+	primary := settings.Networks[0]
+	hostConfig.NetworkMode = container.NetworkMode(primary)
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{primary: {}},
+	}
+	return networkingConfig, settings.Networks[1:]
+}
+
+func normalizeCapabilities(capabilities []string) []string {
+	if len(capabilities) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		capability = strings.ToUpper(capability)
+		if capability != "ALL" && !strings.HasPrefix(capability, "CAP_") {
+			capability = "CAP_" + capability
+		}
+		out = append(out, capability)
 	}
+	return out
+}
+
+func (e *DockerExecutor) report(result scheduler.Result) {
+	result.FinishedAt = time.Now()
+	if e.resultCallback != nil {
+		e.resultCallback(result)
+	}
+}
+
+func (e *DockerExecutor) reportError(result scheduler.Result, err error) {
+	result.Status = "error"
+	result.Error = err.Error()
+	e.report(result)
+}
+
+func (e *DockerExecutor) Stats() Stats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	stats := e.stats
+	stats.ContainerErrors = make(map[string]uint64, len(e.stats.ContainerErrors))
+	for reason, count := range e.stats.ContainerErrors {
+		stats.ContainerErrors[reason] = count
+	}
+	return stats
+}
+
+func (e *DockerExecutor) countContainerError(reason string) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	if e.stats.ContainerErrors == nil {
+		e.stats.ContainerErrors = make(map[string]uint64)
+	}
+	e.stats.ContainerErrors[reason]++
+}
+
+func (e *DockerExecutor) countImagePull(err error) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	if err != nil {
+		e.stats.ImagePullFailures++
+		return
+	}
+	e.stats.ImagePulls++
+}
+
+// resultMessage keeps the container's message and appends the failed
+// evaluation rules when they changed the outcome.
+func resultMessage(result *CheckResult, outcome evaluator.Outcome) string {
+	summary := outcome.Summary()
+	if summary == "" {
+		return result.Message
+	}
+	if result.Message == "" {
+		return "evaluation failed: " + summary
+	}
+	return result.Message + " (evaluation failed: " + summary + ")"
+}
+
+func evaluateResult(check *config.CheckConfig, result *CheckResult) evaluator.Outcome {
+	return check.Evaluate(evaluator.Result{
+		Status:     result.Status,
+		Message:    result.Message,
+		Data:       result.Data,
+		Timestamp:  result.Timestamp,
+		DurationMs: result.DurationMs,
+	})
 }
 
 func (e *DockerExecutor) buildEnvVars(check *config.CheckConfig) ([]string, string, []string, error) {
@@ -450,10 +631,14 @@ func (e *DockerExecutor) Close() error {
 	return nil
 }
 
-func (e *DockerExecutor) SetResultCallback(callback func(checkName string, status string, duration time.Duration)) {
+func (e *DockerExecutor) SetResultCallback(callback func(result scheduler.Result)) {
 	e.resultCallback = callback
 }
 
+func (e *DockerExecutor) SetEventPublisher(publisher events.Publisher) {
+	e.eventPublisher = publisher
+}
+
 func (e *DockerExecutor) SetSecretResolver(resolver SecretResolver) {
 	e.secretResolver = resolver
 }
@@ -581,13 +766,23 @@ func (e *DockerExecutor) ensureImageAvailable(ctx context.Context, imageRef stri
 
 	reader, err := e.cli.ImagePull(pullCtx, imageRef, image.PullOptions{})
 	if err != nil {
+		e.countImagePull(err)
 		return fmt.Errorf("failed to pull image %s: %w", imageRef, err)
 	}
 	defer reader.Close()
 
-	if _, err := io.Copy(io.Discard, reader); err != nil {
+	_, err = io.Copy(io.Discard, reader)
+	e.countImagePull(err)
+	if err != nil {
 		return fmt.Errorf("failed to complete pull for image %s: %w", imageRef, err)
 	}
+	if e.eventPublisher != nil {
+		e.eventPublisher.Publish(events.Event{
+			Type:  events.ImagePulled,
+			Check: checkName,
+			Data:  map[string]interface{}{"image": imageRef},
+		})
+	}
 
 	return nil
 }
@@ -8,7 +8,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/evaluator"
+	"github.com/pfarrer/foghorn/scheduler"
 )
 
 func TestCheckResultJSON(t *testing.T) {
@@ -499,3 +502,216 @@ func TestRedactContainerOutput(t *testing.T) {
 		t.Fatalf("redacted output should contain marker, got: %s", redacted)
 	}
 }
+
+func TestEvaluateResultAppliesCheckRules(t *testing.T) {
+	check := &config.CheckConfig{
+		Name: "latency",
+		Evaluation: []config.EvaluationRule{
+			{Type: "json", Condition: "status == 'pass'"},
+			{Type: "threshold", Condition: "data.latency_ms", Threshold: 500, Severity: "warn"},
+		},
+	}
+
+	result := &CheckResult{
+		Status: "pass",
+		Data:   map[string]interface{}{"latency_ms": float64(750)},
+	}
+	outcome := evaluateResult(check, result)
+	if outcome.Status != "warn" {
+		t.Fatalf("Status = %q, want warn", outcome.Status)
+	}
+
+	check.Evaluation = nil
+	result.Status = "fail"
+	if outcome := evaluateResult(check, result); outcome.Status != "fail" {
+		t.Fatalf("Status without rules = %q, want reported status fail", outcome.Status)
+	}
+}
+
+func TestReportErrorPassesStructuredResult(t *testing.T) {
+	var got scheduler.Result
+	executor := &DockerExecutor{}
+	executor.SetResultCallback(func(result scheduler.Result) {
+		got = result
+	})
+
+	exitCode := 3
+	started := time.Now().Add(-time.Second)
+	executor.reportError(scheduler.Result{
+		CheckName: "tls",
+		Image:     "example/openssl-check:1.0.0",
+		ExitCode:  &exitCode,
+		StartedAt: started,
+	}, fmt.Errorf("check failed with exit code 3"))
+
+	if got.Status != "error" || got.Error != "check failed with exit code 3" {
+		t.Fatalf("result = %+v, want error status with error text", got)
+	}
+	if got.Image != "example/openssl-check:1.0.0" || got.ExitCode == nil || *got.ExitCode != 3 {
+		t.Fatalf("result = %+v, want image and exit code", got)
+	}
+	if got.FinishedAt.Before(started) || got.Duration() <= 0 {
+		t.Fatalf("FinishedAt = %v, want after %v", got.FinishedAt, started)
+	}
+}
+
+func TestResultMessage(t *testing.T) {
+	result := &CheckResult{Status: "pass", Message: "certificate valid"}
+	if got := resultMessage(result, evaluator.Outcome{Status: "pass"}); got != "certificate valid" {
+		t.Fatalf("resultMessage() = %q, want container message", got)
+	}
+
+	outcome := evaluator.Outcome{
+		Status: "warn",
+		Failures: []evaluator.RuleFailure{{
+			Rule:   evaluator.Rule{Condition: "data.days_remaining >= threshold"},
+			Status: "warn",
+			Reason: "got false, expected true",
+		}},
+	}
+	want := "certificate valid (evaluation failed: data.days_remaining >= threshold (got false, expected true))"
+	if got := resultMessage(result, outcome); got != want {
+		t.Fatalf("resultMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestExecutorStats(t *testing.T) {
+	executor := &DockerExecutor{}
+	executor.countImagePull(nil)
+	executor.countImagePull(fmt.Errorf("pull failed"))
+	executor.countContainerError("timeout")
+	executor.countContainerError("timeout")
+
+	stats := executor.Stats()
+	if stats.ImagePulls != 1 || stats.ImagePullFailures != 1 {
+		t.Fatalf("image pulls = %d/%d, want 1/1", stats.ImagePulls, stats.ImagePullFailures)
+	}
+	if stats.ContainerErrors["timeout"] != 2 {
+		t.Fatalf("ContainerErrors = %v, want timeout=2", stats.ContainerErrors)
+	}
+
+	stats.ContainerErrors["timeout"] = 10
+	if executor.Stats().ContainerErrors["timeout"] != 2 {
+		t.Fatalf("Stats() should return a copy")
+	}
+}
+
+func TestApplyContainerSettings(t *testing.T) {
+	cpus := 0.5
+	pidsLimit := int64(64)
+	readOnly := true
+	noNewPrivileges := true
+	settings := config.ContainerConfig{
+		Memory:          "128m",
+		CPUs:            &cpus,
+		PidsLimit:       &pidsLimit,
+		ReadOnlyRootfs:  &readOnly,
+		CapDrop:         []string{"all"},
+		CapAdd:          []string{"net_raw", "CAP_CHOWN"},
+		NoNewPrivileges: &noNewPrivileges,
+		User:            "1000:1000",
+		Ulimits:         map[string]config.Ulimit{"nproc": {Soft: 32, Hard: 64}, "nofile": {Soft: 1024, Hard: 2048}},
+		Tmpfs:           map[string]string{"/tmp": "size=1m"},
+	}
+
+	containerConfig := &container.Config{}
+	hostConfig := &container.HostConfig{}
+	if err := applyContainerSettings(containerConfig, hostConfig, settings); err != nil {
+		t.Fatalf("applyContainerSettings() error = %v", err)
+	}
+
+	if hostConfig.Memory != 128*1024*1024 || hostConfig.MemorySwap != hostConfig.Memory {
+		t.Errorf("memory = %d/%d", hostConfig.Memory, hostConfig.MemorySwap)
+	}
+	if hostConfig.NanoCPUs != 500_000_000 {
+		t.Errorf("NanoCPUs = %d", hostConfig.NanoCPUs)
+	}
+	if hostConfig.PidsLimit == nil || *hostConfig.PidsLimit != 64 {
+		t.Errorf("PidsLimit = %v", hostConfig.PidsLimit)
+	}
+	if !hostConfig.ReadonlyRootfs {
+		t.Errorf("ReadonlyRootfs should be set")
+	}
+	if _, ok := containerConfig.Volumes["/output"]; !ok {
+		t.Errorf("read-only rootfs should keep /output writable")
+	}
+	if strings.Join(hostConfig.CapDrop, ",") != "ALL" || strings.Join(hostConfig.CapAdd, ",") != "CAP_NET_RAW,CAP_CHOWN" {
+		t.Errorf("capabilities = drop %v add %v", hostConfig.CapDrop, hostConfig.CapAdd)
+	}
+	if strings.Join(hostConfig.SecurityOpt, ",") != "no-new-privileges:true" {
+		t.Errorf("SecurityOpt = %v", hostConfig.SecurityOpt)
+	}
+	if containerConfig.User != "1000:1000" {
+		t.Errorf("User = %q", containerConfig.User)
+	}
+	if len(hostConfig.Ulimits) != 2 || hostConfig.Ulimits[0].Name != "nofile" || hostConfig.Ulimits[1].Hard != 64 {
+		t.Errorf("Ulimits = %v", hostConfig.Ulimits)
+	}
+	if hostConfig.Tmpfs["/tmp"] != "size=1m" {
+		t.Errorf("Tmpfs = %v", hostConfig.Tmpfs)
+	}
+}
+
+func TestApplyContainerSettingsZeroDisablesLimits(t *testing.T) {
+	cpus := 0.0
+	pidsLimit := int64(0)
+	readOnly := false
+	settings := config.ContainerConfig{Memory: "0", CPUs: &cpus, PidsLimit: &pidsLimit, ReadOnlyRootfs: &readOnly}
+
+	containerConfig := &container.Config{}
+	hostConfig := &container.HostConfig{}
+	if err := applyContainerSettings(containerConfig, hostConfig, settings); err != nil {
+		t.Fatalf("applyContainerSettings() error = %v", err)
+	}
+	if hostConfig.Memory != 0 || hostConfig.NanoCPUs != 0 || hostConfig.PidsLimit != nil || hostConfig.ReadonlyRootfs {
+		t.Errorf("limits should be disabled: %+v", hostConfig.Resources)
+	}
+	if len(containerConfig.Volumes) != 0 || len(hostConfig.SecurityOpt) != 0 {
+		t.Errorf("unexpected hardening options: volumes %v, security %v", containerConfig.Volumes, hostConfig.SecurityOpt)
+	}
+}
+
+func TestApplyNetworkSettings(t *testing.T) {
+	t.Run("named networks", func(t *testing.T) {
+		hostConfig := &container.HostConfig{}
+		settings := config.NetworkConfig{
+			Networks:   []string{"backend", "monitoring"},
+			DNS:        []string{"10.0.0.2"},
+			DNSSearch:  []string{"svc.internal"},
+			ExtraHosts: []string{"api.example.com:10.0.0.5"},
+		}
+
+		networkingConfig, extra := applyNetworkSettings(hostConfig, settings)
+		if hostConfig.NetworkMode != "backend" {
+			t.Errorf("NetworkMode = %q, want backend", hostConfig.NetworkMode)
+		}
+		if networkingConfig == nil || networkingConfig.EndpointsConfig["backend"] == nil || len(networkingConfig.EndpointsConfig) != 1 {
+			t.Errorf("EndpointsConfig = %+v", networkingConfig)
+		}
+		if strings.Join(extra, ",") != "monitoring" {
+			t.Errorf("extra networks = %v, want monitoring", extra)
+		}
+		if strings.Join(hostConfig.DNS, ",") != "10.0.0.2" || strings.Join(hostConfig.DNSSearch, ",") != "svc.internal" {
+			t.Errorf("DNS = %v, DNSSearch = %v", hostConfig.DNS, hostConfig.DNSSearch)
+		}
+		if strings.Join(hostConfig.ExtraHosts, ",") != "api.example.com:10.0.0.5" {
+			t.Errorf("ExtraHosts = %v", hostConfig.ExtraHosts)
+		}
+	})
+
+	t.Run("mode none", func(t *testing.T) {
+		hostConfig := &container.HostConfig{}
+		networkingConfig, extra := applyNetworkSettings(hostConfig, config.NetworkConfig{Mode: config.NetworkModeNone})
+		if hostConfig.NetworkMode != "none" || networkingConfig != nil || len(extra) != 0 {
+			t.Errorf("NetworkMode = %q, networking = %+v, extra = %v", hostConfig.NetworkMode, networkingConfig, extra)
+		}
+	})
+
+	t.Run("default bridge", func(t *testing.T) {
+		hostConfig := &container.HostConfig{}
+		networkingConfig, _ := applyNetworkSettings(hostConfig, config.NetworkConfig{})
+		if hostConfig.NetworkMode != "" || networkingConfig != nil {
+			t.Errorf("default network should be left to Docker, got %q", hostConfig.NetworkMode)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const maxLogFrameSize = 1 << 20
+
+type runningContainer struct {
+	id      string
+	secrets []string
+}
+
+func (e *DockerExecutor) trackContainer(checkName, containerID string, secrets []string) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if e.running == nil {
+		e.running = make(map[string]runningContainer)
+	}
+	e.running[checkName] = runningContainer{id: containerID, secrets: secrets}
+}
+
+func (e *DockerExecutor) untrackContainer(checkName, containerID string) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if e.running[checkName].id == containerID {
+		delete(e.running, checkName)
+	}
+}
+
+// StreamLogs passes the redacted output of the running container of a check
+// to fn line by line. With follow set it keeps streaming until the container
+// exits or ctx is cancelled.
+func (e *DockerExecutor) StreamLogs(ctx context.Context, checkName string, follow bool, fn func(line string) error) error {
+	e.runningMu.Lock()
+	running, ok := e.running[checkName]
+	e.runningMu.Unlock()
+	if !ok {
+		return scheduler.ErrCheckNotRunning
+	}
+
+	reader, err := e.cli.ContainerLogs(ctx, running.id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read container logs: %w", err)
+	}
+	defer reader.Close()
+
+	err = demultiplexLines(reader, func(line string) error {
+		return fn(redactContainerOutput(line, running.secrets))
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// demultiplexLines reads a multiplexed Docker log stream frame by frame and
+// calls fn for every complete line. A trailing partial line is flushed at
+// the end of the stream.
+func demultiplexLines(r io.Reader, fn func(line string) error) error {
+	reader := bufio.NewReader(r)
+	header := make([]byte, 8)
+	var pending strings.Builder
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+		size := binary.BigEndian.Uint32(header[4:])
+		if size > maxLogFrameSize {
+			return fmt.Errorf("log frame of %d bytes exceeds limit", size)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			return err
+		}
+
+		pending.Write(frame)
+		buffered := pending.String()
+		lines := strings.Split(buffered, "\n")
+		for _, line := range lines[:len(lines)-1] {
+			if err := fn(strings.TrimSuffix(line, "\r")); err != nil {
+				return err
+			}
+		}
+		pending.Reset()
+		pending.WriteString(lines[len(lines)-1])
+	}
+
+	if pending.Len() > 0 {
+		return fn(pending.String())
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func logFrame(stream byte, payload string) []byte {
+	frame := make([]byte, 8, 8+len(payload))
+	frame[0] = stream
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestDemultiplexLines(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(logFrame(1, "connecting to smtp\nsending"))
+	stream.Write(logFrame(2, " probe\r\nwarning: slow\n"))
+	stream.Write(logFrame(1, "partial"))
+
+	var lines []string
+	err := demultiplexLines(&stream, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("demultiplexLines() error = %v", err)
+	}
+	want := "connecting to smtp|sending probe|warning: slow|partial"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestStreamLogsRequiresRunningContainer(t *testing.T) {
+	e := &DockerExecutor{}
+	e.trackContainer("mail", "abc", nil)
+	e.untrackContainer("mail", "abc")
+
+	err := e.StreamLogs(t.Context(), "mail", false, func(string) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Fatalf("StreamLogs() error = %v, want not running", err)
+	}
+}
@@ -18,10 +18,13 @@ import (
 
 	"github.com/docker/docker/client"
 	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/events"
 	"github.com/pfarrer/foghorn/executor"
 	"github.com/pfarrer/foghorn/imageresolver"
 	"github.com/pfarrer/foghorn/internal/statusapi"
 	"github.com/pfarrer/foghorn/logger"
+	"github.com/pfarrer/foghorn/metrics"
+	"github.com/pfarrer/foghorn/notifier"
 	"github.com/pfarrer/foghorn/scheduler"
 	"github.com/pfarrer/foghorn/secretstore"
 	"github.com/pfarrer/foghorn/state"
@@ -32,6 +35,15 @@ func Run() {
 		exitCode := runSecretCLI(os.Args[2:])
 		os.Exit(exitCode)
 	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		os.Exit(runHistoryCLI(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runOnceCLI(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "schedule" {
+		os.Exit(runScheduleCLI(os.Args[2:], os.Stdout))
+	}
 
 	var (
 		help                    bool
@@ -43,6 +55,8 @@ func Run() {
 		statusListen            string
 		stateLogFile            string
 		secretStoreFile         string
+		watchConfig             bool
+		statusTLS               config.StatusTLSConfig
 	)
 
 	flag.BoolVar(&help, "h", false, "Show help message")
@@ -62,6 +76,10 @@ func Run() {
 	flag.StringVar(&stateLogFile, "state-log-file", "", "Path to state log file")
 	flag.StringVar(&stateLogFile, "state_log_file", "", "Path to state log file")
 	flag.StringVar(&secretStoreFile, "secret-store-file", "", "Path to encrypted secret store file")
+	flag.BoolVar(&watchConfig, "watch-config", false, "Reload configuration when the config file changes")
+	flag.StringVar(&statusTLS.CertFile, "status-tls-cert", "", "TLS certificate file for the status API")
+	flag.StringVar(&statusTLS.KeyFile, "status-tls-key", "", "TLS private key file for the status API")
+	flag.StringVar(&statusTLS.ClientCAFile, "status-client-ca", "", "CA file for verifying status API client certificates")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Foghorn Daemon - Service Monitoring Tool\n\n")
@@ -79,10 +97,16 @@ func Run() {
 		fmt.Fprintf(os.Stderr, "      Verify all Docker images in config are available locally\n")
 		fmt.Fprintf(os.Stderr, "  --status-listen <addr>\n")
 		fmt.Fprintf(os.Stderr, "      Status API listen address (default: %s)\n", statusapi.DefaultListenAddr)
+		fmt.Fprintf(os.Stderr, "  --status-tls-cert <path>, --status-tls-key <path>\n")
+		fmt.Fprintf(os.Stderr, "      Serve the status API over HTTPS\n")
+		fmt.Fprintf(os.Stderr, "  --status-client-ca <path>\n")
+		fmt.Fprintf(os.Stderr, "      Require status API clients to present a certificate signed by this CA\n")
 		fmt.Fprintf(os.Stderr, "  -s, --state-log-file <path>\n")
 		fmt.Fprintf(os.Stderr, "      Path to state log file\n")
 		fmt.Fprintf(os.Stderr, "  --secret-store-file <path>\n")
 		fmt.Fprintf(os.Stderr, "      Path to encrypted secret store file\n")
+		fmt.Fprintf(os.Stderr, "  --watch-config\n")
+		fmt.Fprintf(os.Stderr, "      Reload configuration when the config file changes\n")
 		fmt.Fprintf(os.Stderr, "  -h, --help\n")
 		fmt.Fprintf(os.Stderr, "      Show help message\n")
 	}
@@ -113,6 +137,10 @@ func Run() {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := scheduler.ValidateSchedules(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
 
 	logger.Info("Loaded configuration with %d checks", len(cfg.Checks))
 
@@ -122,7 +150,7 @@ func Run() {
 	}
 
 	var stateRecords map[string]scheduler.CheckState
-	var stateLog *state.StateLog
+	var stateLog state.Store
 	if stateLogPath != "" {
 		if cfg.StateLogPeriod == "" {
 			fmt.Fprintf(os.Stderr, "Error: state_log_period is required when state_log_file is set\n")
@@ -134,25 +162,30 @@ func Run() {
 			os.Exit(1)
 		}
 
-		stateLog, err = state.Open(stateLogPath, retention)
+		stateLog, err = openStateStore(cfg.StateBackend, stateLogPath, retention)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error opening state log: %v\n", err)
 			os.Exit(1)
 		}
 		defer stateLog.Close()
 
+		// Corruption is reported with the records that could still be read,
+		// so the state of the other checks is restored.
 		records, err := stateLog.Load()
 		if err != nil {
 			logger.Warn("Failed to load state log: %v", err)
-		} else {
+		}
+		if len(records) > 0 {
 			history := buildHistory(records, 10)
 			latest := state.LatestByCheck(records)
 			stateRecords = make(map[string]scheduler.CheckState, len(latest))
 			for name, record := range latest {
+				lastResult := record.Result()
 				stateRecords[name] = scheduler.CheckState{
 					LastStatus:   record.Status,
 					LastDuration: time.Duration(record.DurationMs) * time.Millisecond,
 					LastRun:      record.CompletedAt,
+					LastResult:   &lastResult,
 					History:      history[name],
 				}
 			}
@@ -164,6 +197,11 @@ func Run() {
 					History: entries,
 				}
 			}
+			for name := range state.PausedChecks(records) {
+				checkState := stateRecords[name]
+				checkState.Paused = true
+				stateRecords[name] = checkState
+			}
 		}
 	}
 
@@ -188,6 +226,7 @@ func Run() {
 	defer dockerExecutor.Close()
 	dockerExecutor.SetDebugOutput(cfg.CheckContainerDebugOutput, cfg.DebugOutputMaxChars)
 
+	var secretResolver notifier.SecretResolver
 	if configUsesSecrets(cfg) {
 		storePath := resolveSecretStorePath(secretStoreFile, cfg.SecretStoreFile)
 		store, err := loadSecretStore(storePath)
@@ -196,6 +235,7 @@ func Run() {
 			os.Exit(1)
 		}
 		dockerExecutor.SetSecretResolver(store)
+		secretResolver = store
 		logger.Info("Secret store enabled: %s", storePath)
 	}
 
@@ -203,10 +243,31 @@ func Run() {
 	if maxConcurrent > 0 {
 		logger.Info("Maximum concurrent checks: %d", maxConcurrent)
 	}
-	sched := scheduler.NewScheduler(dockerExecutor, time.UTC, maxConcurrent)
+	location, err := config.LoadLocation(cfg.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading timezone: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Timezone != "" {
+		logger.Info("Schedule time zone: %s", location)
+	}
+	sched := scheduler.NewScheduler(dockerExecutor, location, maxConcurrent)
 	if stateLog != nil {
 		sched.SetResultLogger(stateLog)
 	}
+	eventBus := events.NewBus()
+	sched.SetEventPublisher(eventBus)
+	dockerExecutor.SetEventPublisher(eventBus)
+
+	dispatcher, err := notifier.NewDispatcher(cfg.Notifiers, secretResolver)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring notifiers: %v\n", err)
+		os.Exit(1)
+	}
+	sched.SetTransitionHandler(dispatcher)
+	if len(cfg.Notifiers) > 0 {
+		logger.Info("Notifiers enabled: %d", len(cfg.Notifiers))
+	}
 
 	for i := range cfg.Checks {
 		check := &cfg.Checks[i]
@@ -217,27 +278,95 @@ func Run() {
 		}
 	}
 
-	if len(stateRecords) > 0 {
-		sched.ApplyState(stateRecords)
+	if err := applyMaintenanceWindows(sched, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring maintenance windows: %v\n", err)
+		os.Exit(1)
 	}
 
+	sched.ApplyState(stateRecords)
+
 	sched.Start(1 * time.Second)
-	statusSrv := statusapi.StartServer(statusListen, sched.Snapshot)
+	collector := metrics.NewCollector(sched.Snapshot,
+		metrics.WithExecutorStats(func() metrics.ExecutorStats {
+			stats := dockerExecutor.Stats()
+			return metrics.ExecutorStats{
+				ImagePulls:        stats.ImagePulls,
+				ImagePullFailures: stats.ImagePullFailures,
+				ContainerErrors:   stats.ContainerErrors,
+			}
+		}),
+		metrics.WithDataGauges(cfg.MetricsExportData),
+	)
+	reloader := &configReloader{
+		path:       configPath,
+		current:    cfg,
+		sched:      sched,
+		dispatcher: dispatcher,
+		secrets:    secretResolver,
+		events:     eventBus,
+	}
+	securityOpts, err := statusSecurityOptions(cfg.StatusAPI, statusTLS, secretResolver)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring status API: %v\n", err)
+		os.Exit(1)
+	}
+	statusOpts := append([]statusapi.Option{
+		statusapi.WithController(sched),
+		statusapi.WithMetrics(collector),
+		statusapi.WithEvents(eventBus),
+		statusapi.WithLogs(dockerExecutor),
+		statusapi.WithSilences(sched),
+		statusapi.WithReloader(statusapi.ReloaderFunc(func() (scheduler.ReconcileResult, error) {
+			return reloader.reloadAndLog("status API")
+		})),
+	}, securityOpts...)
+	if stateLog != nil {
+		statusOpts = append(statusOpts, statusapi.WithHistory(stateLog))
+	}
+	statusSrv := statusapi.StartServer(statusListen, sched.Snapshot, statusOpts...)
+	if len(cfg.StatusAPI.Tokens) > 0 {
+		logger.Info("Status API requires bearer tokens (%d configured)", len(cfg.StatusAPI.Tokens))
+	} else if !isLoopbackListen(statusListen) && (statusSrv.TLSConfig == nil || statusSrv.TLSConfig.ClientCAs == nil) {
+		logger.Warn("Status API on %s accepts unauthenticated requests, including check control; configure status_api tokens or a client CA", statusListen)
+	}
 	statusErr := make(chan error, 1)
 	go func() {
-		logger.Info("Status API listening on http://%s%s", statusListen, statusapi.StatusPath)
-		if err := statusSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		scheme := "http"
+		if statusSrv.TLSConfig != nil {
+			scheme = "https"
+		}
+		logger.Info("Status API listening on %s://%s%s", scheme, statusListen, statusapi.StatusPath)
+		if err := statusapi.ListenAndServe(statusSrv); err != nil && err != http.ErrServerClosed {
 			statusErr <- err
 		}
 	}()
 
+	configChanged := make(chan struct{}, 1)
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	if watchConfig {
+		logger.Info("Watching configuration file for changes: %s", configPath)
+		go watchConfigFile(configPath, configWatchInterval, stopWatch, configChanged)
+	}
+
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	select {
-	case <-sigChan:
-	case err := <-statusErr:
-		logger.Error("Status API server error: %v", err)
-		fmt.Fprintf(os.Stderr, "Status API server error: %v\n", err)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+wait:
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				reloader.reloadAndLog("SIGHUP")
+				continue
+			}
+			break wait
+		case <-configChanged:
+			reloader.reloadAndLog("file changed")
+		case err := <-statusErr:
+			logger.Error("Status API server error: %v", err)
+			fmt.Fprintf(os.Stderr, "Status API server error: %v\n", err)
+			break wait
+		}
 	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -246,6 +375,7 @@ func Run() {
 		logger.Warn("Status API shutdown error: %v", err)
 	}
 	sched.Stop()
+	dispatcher.Wait()
 }
 
 func runSecretCLI(args []string) int {
@@ -392,6 +522,23 @@ func configUsesSecrets(cfg *config.Config) bool {
 			}
 		}
 	}
+	if len(cfg.StatusAPI.Tokens) > 0 {
+		return true
+	}
+	for _, n := range cfg.Notifiers {
+		values := []string{n.URL, n.Username, n.Password}
+		for _, value := range n.Headers {
+			values = append(values, value)
+		}
+		for _, value := range n.Env {
+			values = append(values, value)
+		}
+		for _, value := range values {
+			if _, ok := secretstore.ParseRef(value); ok {
+				return true
+			}
+		}
+	}
 	return false
 }
 
@@ -504,6 +651,14 @@ func verifyImageAvailabilityFn(cfg *config.Config) error {
 	return nil
 }
 
+// openStateStore opens the state backend selected with state_backend at path.
+func openStateStore(backend string, path string, retention time.Duration) (state.Store, error) {
+	if backend == config.StateBackendSQLite {
+		return state.OpenSQLite(path, retention)
+	}
+	return state.Open(path, retention)
+}
+
 func buildHistory(records []state.Record, maxEntries int) map[string][]scheduler.CheckHistoryEntry {
 	if len(records) == 0 || maxEntries <= 0 {
 		return nil
@@ -511,12 +666,13 @@ func buildHistory(records []state.Record, maxEntries int) map[string][]scheduler
 
 	history := make(map[string][]scheduler.CheckHistoryEntry)
 	for _, record := range records {
-		if record.CheckName == "" {
+		if record.CheckName == "" || !record.IsResult() {
 			continue
 		}
 		history[record.CheckName] = append(history[record.CheckName], scheduler.CheckHistoryEntry{
 			Status:      record.Status,
 			CompletedAt: record.CompletedAt,
+			Duration:    time.Duration(record.DurationMs) * time.Millisecond,
 		})
 	}
 
@@ -0,0 +1,214 @@
+package daemon
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/internal/statusapi"
+	"github.com/pfarrer/foghorn/state"
+)
+
+// runHistoryCLI prints check history from the state log. It reads the file
+// without locking it, so it works next to a running daemon.
+func runHistoryCLI(args []string, stdout io.Writer) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var (
+		configPathArg string
+		stateLogArg   string
+		since         string
+		until         string
+		status        string
+		limit         int
+		cursor        string
+		jsonOutput    bool
+	)
+	fs.StringVar(&configPathArg, "c", "", "Path to configuration file")
+	fs.StringVar(&configPathArg, "config", "", "Path to configuration file")
+	fs.StringVar(&stateLogArg, "s", "", "Path to state log file")
+	fs.StringVar(&stateLogArg, "state-log-file", "", "Path to state log file")
+	fs.StringVar(&since, "since", "7d", "Start of the time range")
+	fs.StringVar(&until, "until", "", "End of the time range")
+	fs.StringVar(&status, "status", "", "Only list results with these statuses")
+	fs.IntVar(&limit, "limit", 20, "Maximum number of results to list")
+	fs.StringVar(&cursor, "cursor", "", "Continue from a previous page")
+	fs.BoolVar(&jsonOutput, "json", false, "Print JSON")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printHistoryUsage()
+		return 1
+	}
+	if fs.NArg() > 1 {
+		printHistoryUsage()
+		return 1
+	}
+
+	path := stateLogArg
+	if path == "" && configPathArg != "" {
+		cfg, err := config.Load(configPathArg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			return 1
+		}
+		path = cfg.StateLogFile
+	}
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: a state log file is required (--state-log-file or state_log_file in --config)\n")
+		printHistoryUsage()
+		return 1
+	}
+
+	values := url.Values{}
+	values.Set("since", since)
+	values.Set("until", until)
+	values.Set("status", status)
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("cursor", cursor)
+	query, err := statusapi.ParseHistoryQuery(fs.Arg(0), values, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	records, err := state.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading state log: %v\n", err)
+		return 1
+	}
+
+	if query.Check == "" {
+		summaries := summarizeChecks(records, query)
+		if jsonOutput {
+			return writeJSON(stdout, summaries)
+		}
+		printSummaryTable(stdout, summaries)
+		return 0
+	}
+
+	page, err := state.QueryHistory(records, query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if jsonOutput {
+		return writeJSON(stdout, page)
+	}
+	printHistoryPage(stdout, page)
+	return 0
+}
+
+func printHistoryUsage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  foghorn-daemon history [--config <path> | --state-log-file <path>] [options] [check]\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  --since <time>     Start of the range, RFC 3339 or a duration like 24h or 7d (default: 7d)\n")
+	fmt.Fprintf(os.Stderr, "  --until <time>     End of the range (default: now)\n")
+	fmt.Fprintf(os.Stderr, "  --status <list>    Only list results with these statuses, e.g. fail,error\n")
+	fmt.Fprintf(os.Stderr, "  --limit <n>        Results per page (default: 20)\n")
+	fmt.Fprintf(os.Stderr, "  --cursor <cursor>  Continue from a previous page\n")
+	fmt.Fprintf(os.Stderr, "  --json             Print JSON\n")
+	fmt.Fprintf(os.Stderr, "Without a check name, prints a summary of every check.\n")
+}
+
+type checkSummary struct {
+	Check string `json:"check"`
+	state.HistorySummary
+}
+
+func summarizeChecks(records []state.Record, query state.HistoryQuery) []checkSummary {
+	names := make(map[string]bool)
+	for _, record := range records {
+		if record.IsResult() && record.CheckName != "" {
+			names[record.CheckName] = true
+		}
+	}
+	summaries := make([]checkSummary, 0, len(names))
+	for name := range names {
+		query.Check = name
+		page, err := state.QueryHistory(records, query)
+		if err != nil || page.Summary.Runs == 0 {
+			continue
+		}
+		summaries = append(summaries, checkSummary{Check: name, HistorySummary: page.Summary})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Check < summaries[j].Check })
+	return summaries
+}
+
+func printSummaryTable(w io.Writer, summaries []checkSummary) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No results in the selected range.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tRUNS\tUPTIME\tFAILURES\tINCIDENTS\tMTTR\tMEAN\tP95")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%d\t%d\t%s\t%s\t%s\n", s.Check, s.Runs, s.UptimePercent, s.Failures, s.Incidents,
+			formatMTTR(s.HistorySummary), formatMillis(s.MeanDurationMs), formatMillis(s.P95DurationMs))
+	}
+	_ = tw.Flush()
+}
+
+func printHistoryPage(w io.Writer, page state.HistoryPage) {
+	s := page.Summary
+	fmt.Fprintf(w, "Check:      %s\n", page.Check)
+	fmt.Fprintf(w, "Runs:       %d (%d pass, %d warn, %d fail, %d error)\n", s.Runs, s.Pass, s.Warn, s.Fail, s.Error)
+	fmt.Fprintf(w, "Uptime:     %.2f%%\n", s.UptimePercent)
+	fmt.Fprintf(w, "Duration:   mean %s, p95 %s\n", formatMillis(s.MeanDurationMs), formatMillis(s.P95DurationMs))
+	fmt.Fprintf(w, "Incidents:  %d, MTTR %s\n", s.Incidents, formatMTTR(s))
+	if s.Failing {
+		fmt.Fprintf(w, "            currently failing\n")
+	}
+	fmt.Fprintln(w)
+
+	if len(page.Entries) == 0 {
+		fmt.Fprintln(w, "No results in the selected range.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPLETED\tSTATUS\tDURATION\tMESSAGE")
+	for _, entry := range page.Entries {
+		message := entry.Message
+		if entry.Error != "" {
+			message = strings.TrimSpace(message + " (" + entry.Error + ")")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.CompletedAt.Local().Format("2006-01-02 15:04:05"), entry.Status, formatMillis(entry.DurationMs), message)
+	}
+	_ = tw.Flush()
+	if page.NextCursor != "" {
+		fmt.Fprintf(w, "\nMore results: --cursor %s\n", page.NextCursor)
+	}
+}
+
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func formatMTTR(s state.HistorySummary) string {
+	if s.Incidents == 0 || s.MTTRSeconds == 0 {
+		return "-"
+	}
+	return (time.Duration(s.MTTRSeconds * float64(time.Second))).Round(time.Second).String()
+}
+
+func writeJSON(w io.Writer, value interface{}) int {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
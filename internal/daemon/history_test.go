@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/scheduler"
+	"github.com/pfarrer/foghorn/state"
+)
+
+func writeStateLog(t *testing.T, records ...state.Record) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := state.Open(path, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+	for _, record := range records {
+		if err := log.Append(record); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	return path
+}
+
+func TestHistoryCLI(t *testing.T) {
+	now := time.Now().UTC()
+	path := writeStateLog(t,
+		state.Record{CheckName: "api", Status: "pass", DurationMs: 120, CompletedAt: now.Add(-10 * 24 * time.Hour)},
+		state.Record{CheckName: "api", Status: "fail", DurationMs: 900, CompletedAt: now.Add(-3 * time.Hour), Message: "HTTP 503"},
+		state.Record{CheckName: "api", Status: "pass", DurationMs: 150, CompletedAt: now.Add(-2 * time.Hour)},
+		state.Record{CheckName: "disk", Status: "warn", DurationMs: 40, CompletedAt: now.Add(-time.Hour)},
+	)
+
+	var out bytes.Buffer
+	if code := runHistoryCLI([]string{"--state-log-file", path}, &out); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	summary := out.String()
+	for _, want := range []string{"CHECK", "api", "50.00%", "1h0m0s", "disk", "100.00%"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	out.Reset()
+	if code := runHistoryCLI([]string{"-s", path, "--status", "fail", "api"}, &out); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	if text := out.String(); !strings.Contains(text, "HTTP 503") || strings.Contains(text, "150ms") {
+		t.Fatalf("check history:\n%s", text)
+	}
+
+	out.Reset()
+	if code := runHistoryCLI([]string{"-s", path, "--since", "30d", "--json", "api"}, &out); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	var page state.HistoryPage
+	if err := json.Unmarshal(out.Bytes(), &page); err != nil {
+		t.Fatalf("json output: %v", err)
+	}
+	if page.Summary.Runs != 3 || len(page.Entries) != 3 {
+		t.Fatalf("page = %+v", page)
+	}
+}
+
+func TestHistoryCLIReadsSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	store, err := openStateStore(config.StateBackendSQLite, path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("open state database: %v", err)
+	}
+	if err := store.RecordResult(scheduler.Result{CheckName: "api", Status: "fail", Message: "HTTP 503", FinishedAt: time.Now()}); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+	store.Close()
+
+	var out bytes.Buffer
+	if code := runHistoryCLI([]string{"-s", path, "api"}, &out); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	if text := out.String(); !strings.Contains(text, "HTTP 503") {
+		t.Fatalf("check history:\n%s", text)
+	}
+}
+
+func TestHistoryCLIRequiresStateLog(t *testing.T) {
+	if code := runHistoryCLI([]string{"api"}, &bytes.Buffer{}); code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if code := runHistoryCLI([]string{"-s", writeStateLog(t), "--since", "lately"}, &bytes.Buffer{}); code != 1 {
+		t.Fatalf("exit code = %d, want 1 for an invalid range", code)
+	}
+}
@@ -0,0 +1,435 @@
+package daemon
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/executor"
+	"github.com/pfarrer/foghorn/logger"
+	"github.com/pfarrer/foghorn/scheduler"
+	"github.com/pfarrer/foghorn/state"
+)
+
+// Exit codes of run --once.
+const (
+	onceExitPassed = 0
+	onceExitFailed = 1
+	onceExitUsage  = 2
+)
+
+// listFlag collects a repeatable flag; each value may also be a comma
+// separated list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// runOnceCLI runs the selected checks once and exits with 0 when all of them
+// passed or warned, 1 when any failed or errored and 2 when it could not run.
+func runOnceCLI(args []string, stdout io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var (
+		configPath      string
+		once            bool
+		checks          listFlag
+		tags            listFlag
+		junitPath       string
+		jsonPath        string
+		logLevel        string
+		stateLogFile    string
+		secretStoreFile string
+	)
+	fs.StringVar(&configPath, "c", "", "Path to configuration file")
+	fs.StringVar(&configPath, "config", "", "Path to configuration file")
+	fs.BoolVar(&once, "once", false, "Run each selected check once and exit")
+	fs.Var(&checks, "check", "Only run these checks")
+	fs.Var(&tags, "tag", "Only run checks with these tags")
+	fs.StringVar(&junitPath, "junit", "", "Write a JUnit XML report to this file")
+	fs.StringVar(&jsonPath, "json", "", "Write a JSON report to this file")
+	fs.StringVar(&logLevel, "l", "warn", "Log level (debug, info, warn, error)")
+	fs.StringVar(&logLevel, "log-level", "warn", "Log level (debug, info, warn, error)")
+	fs.StringVar(&stateLogFile, "s", "", "Path to state log file")
+	fs.StringVar(&stateLogFile, "state-log-file", "", "Path to state log file")
+	fs.StringVar(&secretStoreFile, "secret-store-file", "", "Path to encrypted secret store file")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printOnceUsage()
+		return onceExitUsage
+	}
+	if !once || configPath == "" || fs.NArg() > 0 {
+		printOnceUsage()
+		return onceExitUsage
+	}
+
+	lvl, err := logger.ParseLevel(logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return onceExitUsage
+	}
+	logger.SetGlobal(logger.New(lvl, false))
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return onceExitUsage
+	}
+	selected, err := selectOnceChecks(cfg, checks, tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return onceExitUsage
+	}
+
+	dockerExecutor, err := executor.NewDockerExecutor()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker executor: %v\n", err)
+		return onceExitUsage
+	}
+	defer dockerExecutor.Close()
+	dockerExecutor.SetDebugOutput(cfg.CheckContainerDebugOutput, cfg.DebugOutputMaxChars)
+	if configUsesSecrets(cfg) {
+		store, err := loadSecretStore(resolveSecretStorePath(secretStoreFile, cfg.SecretStoreFile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading secret store: %v\n", err)
+			return onceExitUsage
+		}
+		dockerExecutor.SetSecretResolver(store)
+	}
+
+	// The state log is optional here: a daemon holding its lock must not
+	// make a smoke test fail.
+	var store state.Store
+	if path := firstNonEmpty(stateLogFile, cfg.StateLogFile); path != "" {
+		retention, err := time.ParseDuration(cfg.StateLogPeriod)
+		if err == nil {
+			store, err = openStateStore(cfg.StateBackend, path, retention)
+		}
+		if err != nil {
+			logger.Warn("Not recording results in the state log: %v", err)
+			store = nil
+		} else {
+			defer store.Close()
+		}
+	}
+
+	startedAt := time.Now()
+	results, err := executeOnce(dockerExecutor, cfg.MaxConcurrentChecks, selected, store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return onceExitUsage
+	}
+	report := newOnceReport(results, startedAt, time.Now())
+
+	printOnceSummary(stdout, report)
+	if junitPath != "" {
+		if err := writeReportFile(junitPath, func(w io.Writer) error { return writeJUnitReport(w, report) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JUnit report: %v\n", err)
+			return onceExitUsage
+		}
+	}
+	if jsonPath != "" {
+		if err := writeReportFile(jsonPath, func(w io.Writer) error { return writeJSONReport(w, report) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON report: %v\n", err)
+			return onceExitUsage
+		}
+	}
+	if !report.Passed {
+		return onceExitFailed
+	}
+	return onceExitPassed
+}
+
+func printOnceUsage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  foghorn-daemon run --once --config <path> [options]\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  --check <name>       Only run these checks (repeatable or comma separated)\n")
+	fmt.Fprintf(os.Stderr, "  --tag <tag>          Only run checks with one of these tags (repeatable or comma separated)\n")
+	fmt.Fprintf(os.Stderr, "  --junit <path>       Write a JUnit XML report\n")
+	fmt.Fprintf(os.Stderr, "  --json <path>        Write a JSON report\n")
+	fmt.Fprintf(os.Stderr, "  -l, --log-level      Log level (default: warn)\n")
+	fmt.Fprintf(os.Stderr, "  -s, --state-log-file Record results in this state log\n")
+	fmt.Fprintf(os.Stderr, "  --secret-store-file  Path to encrypted secret store file\n")
+	fmt.Fprintf(os.Stderr, "Exit codes: 0 all checks passed or warned, 1 a check failed or errored, 2 the run could not start.\n")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// selectOnceChecks returns the enabled checks named in names or tagged with
+// one of tags, in configuration order. Without either all enabled checks are
+// selected. Naming an unknown or disabled check is an error.
+func selectOnceChecks(cfg *config.Config, names, tags []string) ([]*config.CheckConfig, error) {
+	byName := make(map[string]*config.CheckConfig, len(cfg.Checks))
+	for i := range cfg.Checks {
+		byName[cfg.Checks[i].Name] = &cfg.Checks[i]
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		check, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", scheduler.ErrCheckNotFound, name)
+		}
+		if !check.Enabled {
+			return nil, fmt.Errorf("check %s is disabled", name)
+		}
+		wanted[name] = true
+	}
+
+	var selected []*config.CheckConfig
+	for i := range cfg.Checks {
+		check := &cfg.Checks[i]
+		if !check.Enabled {
+			continue
+		}
+		if len(names) > 0 || len(tags) > 0 {
+			if !wanted[check.Name] && !hasAnyTag(check.Tags, tags) {
+				continue
+			}
+		}
+		selected = append(selected, check)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no enabled checks selected")
+	}
+	return selected, nil
+}
+
+func hasAnyTag(checkTags, tags []string) bool {
+	for _, tag := range tags {
+		for _, checkTag := range checkTags {
+			if tag == checkTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// executeOnce runs checks once on a scheduler that is never started, so the
+// concurrency limit and result handling are the same as in the daemon.
+func executeOnce(exec scheduler.CheckExecutor, maxConcurrent int, checks []*config.CheckConfig, store state.Store) ([]scheduler.Result, error) {
+	sched := scheduler.NewScheduler(exec, time.UTC, maxConcurrent)
+	if store != nil {
+		sched.SetResultLogger(store)
+	}
+	names := make([]string, 0, len(checks))
+	for _, check := range checks {
+		if err := sched.AddCheck(scheduler.NewConfigAdapter(check)); err != nil {
+			return nil, err
+		}
+		names = append(names, check.Name)
+	}
+	return sched.RunOnce(names)
+}
+
+type onceReport struct {
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	Passed     bool         `json:"passed"`
+	Summary    onceSummary  `json:"summary"`
+	Results    []onceResult `json:"results"`
+	duration   time.Duration
+}
+
+type onceSummary struct {
+	Total      int `json:"total"`
+	Pass       int `json:"pass"`
+	Warn       int `json:"warn"`
+	Fail       int `json:"fail"`
+	Error      int `json:"error"`
+	Suppressed int `json:"suppressed"`
+}
+
+type onceResult struct {
+	scheduler.Result
+	DurationMs int64 `json:"duration_ms"`
+}
+
+func newOnceReport(results []scheduler.Result, startedAt, finishedAt time.Time) onceReport {
+	report := onceReport{
+		StartedAt:  startedAt.UTC(),
+		FinishedAt: finishedAt.UTC(),
+		Results:    make([]onceResult, 0, len(results)),
+		duration:   finishedAt.Sub(startedAt),
+	}
+	for _, result := range results {
+		report.Summary.Total++
+		switch result.Status {
+		case "pass":
+			report.Summary.Pass++
+		case "warn":
+			report.Summary.Warn++
+		case "fail":
+			report.Summary.Fail++
+		case scheduler.StatusSuppressed:
+			report.Summary.Suppressed++
+		default:
+			report.Summary.Error++
+		}
+		report.Results = append(report.Results, onceResult{Result: result, DurationMs: result.Duration().Milliseconds()})
+	}
+	report.Passed = report.Summary.Fail == 0 && report.Summary.Error == 0
+	return report
+}
+
+func printOnceSummary(w io.Writer, report onceReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDURATION\tMESSAGE")
+	for _, result := range report.Results {
+		message := result.Message
+		if result.Error != "" {
+			message = strings.TrimSpace(message + " (" + result.Error + ")")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.CheckName, result.Status, formatMillis(result.DurationMs), message)
+	}
+	_ = tw.Flush()
+	s := report.Summary
+	suppressed := ""
+	if s.Suppressed > 0 {
+		suppressed = fmt.Sprintf(", %d suppressed", s.Suppressed)
+	}
+	fmt.Fprintf(w, "\n%d checks: %d pass, %d warn, %d fail, %d error%s (%s)\n", s.Total, s.Pass, s.Warn, s.Fail, s.Error, suppressed, report.duration.Round(time.Millisecond))
+}
+
+func writeReportFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeJSONReport(w io.Writer, report onceReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes one test case per check. Failed checks are
+// failures, errored checks errors and suppressed checks skipped; warnings
+// pass with their message in system-out.
+func writeJUnitReport(w io.Writer, report onceReport) error {
+	seconds := func(d time.Duration) string { return fmt.Sprintf("%.3f", d.Seconds()) }
+	suite := junitTestSuite{
+		Name:      "foghorn",
+		Tests:     report.Summary.Total,
+		Failures:  report.Summary.Fail,
+		Errors:    report.Summary.Error,
+		Skipped:   report.Summary.Suppressed,
+		Time:      seconds(report.duration),
+		Timestamp: report.StartedAt.Format(time.RFC3339),
+	}
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.CheckName,
+			Classname: "foghorn",
+			Time:      seconds(result.Duration()),
+			SystemOut: result.Message,
+		}
+		if len(result.Data) > 0 {
+			if data, err := json.Marshal(result.Data); err == nil {
+				testCase.SystemOut = strings.TrimSpace(testCase.SystemOut + "\n" + string(data))
+			}
+		}
+		problem := &junitProblem{Message: result.Message, Type: result.Status, Text: result.Error}
+		if problem.Message == "" {
+			problem.Message = result.Error
+		}
+		switch result.Status {
+		case "pass", "warn":
+		case "fail":
+			testCase.Failure = problem
+		case scheduler.StatusSuppressed:
+			testCase.Skipped = problem
+		default:
+			testCase.Error = problem
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{
+		Name:     "foghorn",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
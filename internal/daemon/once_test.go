@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type statusExecutor struct {
+	statuses map[string]string
+	callback func(result scheduler.Result)
+}
+
+func (e *statusExecutor) Execute(check scheduler.CheckConfig) error {
+	status, ok := e.statuses[check.GetName()]
+	if !ok {
+		return errors.New("image not found")
+	}
+	now := time.Now()
+	e.callback(scheduler.Result{CheckName: check.GetName(), Status: status, Message: "HTTP " + status, StartedAt: now.Add(-250 * time.Millisecond), FinishedAt: now})
+	return nil
+}
+
+func (e *statusExecutor) SetResultCallback(callback func(result scheduler.Result)) {
+	e.callback = callback
+}
+
+func onceConfig() *config.Config {
+	check := func(name string, enabled bool, tags ...string) config.CheckConfig {
+		return config.CheckConfig{Name: name, Image: "test/image:1.0.0", Enabled: enabled, Tags: tags, Schedule: config.Schedule{Interval: "1m"}}
+	}
+	return &config.Config{Checks: []config.CheckConfig{
+		check("api", true, "web"),
+		check("web", true, "web", "smoke"),
+		check("db", true, "storage"),
+		check("legacy", false, "web"),
+	}}
+}
+
+func TestSelectOnceChecks(t *testing.T) {
+	cfg := onceConfig()
+	names := func(checks []*config.CheckConfig) string {
+		var out []string
+		for _, check := range checks {
+			out = append(out, check.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		names, tags []string
+		want        string
+	}{
+		{want: "api,web,db"},
+		{tags: []string{"web"}, want: "api,web"},
+		{names: []string{"db"}, tags: []string{"smoke"}, want: "web,db"},
+	}
+	for _, tt := range tests {
+		got, err := selectOnceChecks(cfg, tt.names, tt.tags)
+		if err != nil || names(got) != tt.want {
+			t.Errorf("selectOnceChecks(%v, %v) = %s, %v; want %s", tt.names, tt.tags, names(got), err, tt.want)
+		}
+	}
+
+	for _, name := range []string{"legacy", "missing"} {
+		if _, err := selectOnceChecks(cfg, []string{name}, nil); err == nil {
+			t.Errorf("selectOnceChecks(%s) should fail", name)
+		}
+	}
+	if _, err := selectOnceChecks(cfg, nil, []string{"none"}); err == nil {
+		t.Errorf("an empty selection should fail")
+	}
+}
+
+func TestOnceReports(t *testing.T) {
+	cfg := onceConfig()
+	selected, err := selectOnceChecks(cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("selectOnceChecks() error = %v", err)
+	}
+	executor := &statusExecutor{statuses: map[string]string{"api": "pass", "web": "warn"}}
+	results, err := executeOnce(executor, 1, selected, nil)
+	if err != nil {
+		t.Fatalf("executeOnce() error = %v", err)
+	}
+	start := time.Now()
+	report := newOnceReport(results, start, start.Add(time.Second))
+	if report.Passed || report.Summary != (onceSummary{Total: 3, Pass: 1, Warn: 1, Error: 1}) {
+		t.Fatalf("report = %+v", report)
+	}
+
+	var summary bytes.Buffer
+	printOnceSummary(&summary, report)
+	for _, want := range []string{"CHECK", "api", "pass", "250ms", "image not found", "3 checks: 1 pass, 1 warn, 0 fail, 1 error"} {
+		if !strings.Contains(summary.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, summary.String())
+		}
+	}
+
+	var junit bytes.Buffer
+	if err := writeJUnitReport(&junit, report); err != nil {
+		t.Fatalf("writeJUnitReport() error = %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(junit.Bytes(), &suites); err != nil {
+		t.Fatalf("JUnit report is not valid XML: %v\n%s", err, junit.String())
+	}
+	cases := suites.Suites[0].Cases
+	if suites.Tests != 3 || suites.Errors != 1 || len(cases) != 3 || cases[0].Time != "0.250" ||
+		cases[1].Failure != nil || cases[2].Error == nil || cases[2].Error.Message != "image not found" {
+		t.Fatalf("JUnit report:\n%s", junit.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeJSONReport(&out, report); err != nil {
+		t.Fatalf("writeJSONReport() error = %v", err)
+	}
+	var decoded struct {
+		Passed  bool
+		Results []struct {
+			CheckName  string `json:"check_name"`
+			Status     string
+			DurationMs int64 `json:"duration_ms"`
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON report: %v", err)
+	}
+	if decoded.Passed || len(decoded.Results) != 3 || decoded.Results[0].DurationMs != 250 || decoded.Results[2].Status != "error" {
+		t.Fatalf("JSON report:\n%s", out.String())
+	}
+
+	if passed := newOnceReport(results[:2], start, start); !passed.Passed {
+		t.Fatalf("pass and warn results should pass")
+	}
+}
+
+func TestRunOnceCLIUsage(t *testing.T) {
+	for _, args := range [][]string{{"-c", "config.yaml"}, {"--once"}, {"--once", "-c", "config.yaml", "extra"}} {
+		if code := runOnceCLI(args, &bytes.Buffer{}); code != onceExitUsage {
+			t.Errorf("runOnceCLI(%v) = %d, want %d", args, code, onceExitUsage)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/events"
+	"github.com/pfarrer/foghorn/logger"
+	"github.com/pfarrer/foghorn/notifier"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const configWatchInterval = 2 * time.Second
+
+type configReloader struct {
+	mu         sync.Mutex
+	path       string
+	current    *config.Config
+	sched      *scheduler.Scheduler
+	dispatcher *notifier.Dispatcher
+	secrets    notifier.SecretResolver
+	events     events.Publisher
+}
+
+// Reload loads the config file again and applies the checks and notifiers
+// to the running daemon. An invalid config leaves everything untouched.
+func (r *configReloader) Reload() (scheduler.ReconcileResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := config.Load(r.path)
+	if err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	if err := scheduler.ValidateSchedules(cfg); err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	if configUsesSecrets(cfg) && r.secrets == nil {
+		return scheduler.ReconcileResult{}, fmt.Errorf("config references secrets, but the secret store was not loaded at startup (restart required)")
+	}
+	if _, err := notifier.NewDispatcher(cfg.Notifiers, r.secrets); err != nil {
+		return scheduler.ReconcileResult{}, fmt.Errorf("invalid notifiers: %w", err)
+	}
+
+	windows, err := scheduler.NewMaintenanceWindows(cfg.MaintenanceWindows)
+	if err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+
+	result, err := r.sched.Reconcile(checkAdapters(cfg))
+	if err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	if err := r.sched.SetMaintenanceWindows(windows); err != nil {
+		return result, fmt.Errorf("failed to apply maintenance windows: %w", err)
+	}
+	if err := r.dispatcher.Reconfigure(cfg.Notifiers, r.secrets); err != nil {
+		return result, fmt.Errorf("failed to apply notifiers: %w", err)
+	}
+
+	if settings := restartRequiredChanges(r.current, cfg); len(settings) > 0 {
+		logger.Warn("Configuration changes to %s require a restart and were not applied", strings.Join(settings, ", "))
+	}
+	r.current = cfg
+	return result, nil
+}
+
+func (r *configReloader) reloadAndLog(reason string) (scheduler.ReconcileResult, error) {
+	logger.Info("Reloading configuration (%s)", reason)
+	result, err := r.Reload()
+	r.publishReload(reason, result, err)
+	if err != nil {
+		logger.Error("Configuration reload rejected: %v", err)
+		return result, err
+	}
+	if !result.Changed() {
+		logger.Info("Configuration reloaded: no check changes")
+		return result, nil
+	}
+	logger.Info("Configuration reloaded: %d added, %d updated, %d removed", len(result.Added), len(result.Updated), len(result.Removed))
+	return result, nil
+}
+
+func (r *configReloader) publishReload(reason string, result scheduler.ReconcileResult, err error) {
+	if r.events == nil {
+		return
+	}
+	event := events.Event{
+		Type:   events.ConfigReloaded,
+		Status: "ok",
+		Data: map[string]interface{}{
+			"reason":  reason,
+			"added":   result.Added,
+			"updated": result.Updated,
+			"removed": result.Removed,
+		},
+	}
+	if err != nil {
+		event.Status = "error"
+		event.Message = err.Error()
+	}
+	r.events.Publish(event)
+}
+
+func applyMaintenanceWindows(sched *scheduler.Scheduler, cfg *config.Config) error {
+	windows, err := scheduler.NewMaintenanceWindows(cfg.MaintenanceWindows)
+	if err != nil {
+		return err
+	}
+	if len(windows) > 0 {
+		logger.Info("Maintenance windows configured: %d", len(windows))
+	}
+	return sched.SetMaintenanceWindows(windows)
+}
+
+func checkAdapters(cfg *config.Config) []scheduler.CheckConfig {
+	adapters := make([]scheduler.CheckConfig, 0, len(cfg.Checks))
+	for i := range cfg.Checks {
+		adapters = append(adapters, scheduler.NewConfigAdapter(&cfg.Checks[i]))
+	}
+	return adapters
+}
+
+func restartRequiredChanges(previous, next *config.Config) []string {
+	if previous == nil || next == nil {
+		return nil
+	}
+	var settings []string
+	if previous.MaxConcurrentChecks != next.MaxConcurrentChecks {
+		settings = append(settings, "max_concurrent_checks")
+	}
+	if previous.StateLogFile != next.StateLogFile {
+		settings = append(settings, "state_log_file")
+	}
+	if previous.StateLogPeriod != next.StateLogPeriod {
+		settings = append(settings, "state_log_period")
+	}
+	if previous.StateBackend != next.StateBackend {
+		settings = append(settings, "state_backend")
+	}
+	if previous.Timezone != next.Timezone {
+		settings = append(settings, "timezone")
+	}
+	if previous.SecretStoreFile != next.SecretStoreFile {
+		settings = append(settings, "secret_store_file")
+	}
+	if previous.CheckContainerDebugOutput != next.CheckContainerDebugOutput {
+		settings = append(settings, "check_container_debug_output")
+	}
+	if previous.DebugOutputMaxChars != next.DebugOutputMaxChars {
+		settings = append(settings, "debug_output_max_chars")
+	}
+	if previous.MetricsExportData != next.MetricsExportData {
+		settings = append(settings, "metrics_export_data")
+	}
+	if !reflect.DeepEqual(previous.StatusAPI, next.StatusAPI) {
+		settings = append(settings, "status_api")
+	}
+	return settings
+}
+
+// watchConfigFile polls path and signals changed whenever its content
+// differs from the previous poll. Polling also follows editors that replace
+// the file instead of writing it in place.
+func watchConfigFile(path string, interval time.Duration, stop <-chan struct{}, changed chan<- struct{}) {
+	last, _ := fileDigest(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			digest, err := fileDigest(path)
+			if err != nil {
+				logger.Debug("Config watch: failed to read %s: %v", path, err)
+				continue
+			}
+			if bytes.Equal(digest, last) {
+				continue
+			}
+			last = digest
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func fileDigest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
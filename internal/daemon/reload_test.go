@@ -0,0 +1,213 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/events"
+	"github.com/pfarrer/foghorn/notifier"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type noopExecutor struct{}
+
+func (noopExecutor) Execute(scheduler.CheckConfig) error      { return nil }
+func (noopExecutor) SetResultCallback(func(scheduler.Result)) {}
+
+const reloadBaseConfig = `version: "1.0"
+---
+name: "api"
+enabled: true
+image: "example/http-check:1.0.0"
+schedule:
+  cron: "*/5 * * * *"
+---
+name: "disk"
+enabled: true
+image: "example/disk-check:1.0.0"
+schedule:
+  interval: "1m"
+`
+
+func newTestReloader(t *testing.T, content string) (*configReloader, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, content)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	sched := scheduler.NewScheduler(noopExecutor{}, time.UTC, 0)
+	if _, err := sched.Reconcile(checkAdapters(cfg)); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	dispatcher, err := notifier.NewDispatcher(cfg.Notifiers, nil)
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	return &configReloader{path: path, current: cfg, sched: sched, dispatcher: dispatcher}, path
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestConfigReloaderAppliesChanges(t *testing.T) {
+	reloader, path := newTestReloader(t, reloadBaseConfig)
+
+	writeFile(t, path, `version: "1.0"
+---
+name: "api"
+enabled: false
+image: "example/http-check:1.0.0"
+schedule:
+  cron: "*/5 * * * *"
+---
+name: "tls"
+enabled: true
+image: "example/openssl-check:1.0.0"
+schedule:
+  cron: "0 * * * *"
+`)
+
+	result, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if strings.Join(result.Added, ",") != "tls" || strings.Join(result.Updated, ",") != "api" || strings.Join(result.Removed, ",") != "disk" {
+		t.Fatalf("Reload() = %+v, want added tls, updated api, removed disk", result)
+	}
+
+	checks := reloader.sched.GetAllChecks()
+	if checks["api"].Config.IsEnabled() {
+		t.Fatalf("api should be disabled after reload")
+	}
+	if _, ok := checks["disk"]; ok {
+		t.Fatalf("disk should be removed after reload")
+	}
+}
+
+func TestConfigReloaderRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid yaml", content: "name: [unterminated"},
+		{name: "invalid cron", content: strings.Replace(reloadBaseConfig, "*/5 * * * *", "not a cron", 1)},
+		{name: "cron that never fires", content: strings.Replace(reloadBaseConfig, "*/5 * * * *", "0 0 31 2 *", 1)},
+		{name: "windows that never run", content: strings.Replace(reloadBaseConfig, `  cron: "*/5 * * * *"`, `  windows:
+    - start: "08:00"
+      end: "09:00"
+      cron: "30 10 * * *"`, 1)},
+		{name: "invalid notifier", content: "notifiers:\n  - name: hook\n    type: pager\n---\n" + reloadBaseConfig},
+		{name: "secret without store", content: strings.Replace(reloadBaseConfig, `schedule:
+  interval: "1m"`, `schedule:
+  interval: "1m"
+env:
+  TOKEN: "secret://api/token"`, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloader, path := newTestReloader(t, reloadBaseConfig)
+			writeFile(t, path, tt.content)
+
+			if _, err := reloader.Reload(); err == nil {
+				t.Fatalf("Reload() should reject config")
+			}
+			checks := reloader.sched.GetAllChecks()
+			if len(checks) != 2 || checks["api"].Config.GetSchedule() != "*/5 * * * *" {
+				t.Fatalf("running checks changed after rejected reload")
+			}
+		})
+	}
+}
+
+func TestConfigReloaderPublishesEvents(t *testing.T) {
+	reloader, path := newTestReloader(t, reloadBaseConfig)
+	bus := events.NewBus()
+	reloader.events = bus
+	stream, unsubscribe := bus.Subscribe(0)
+	defer unsubscribe()
+
+	writeFile(t, path, strings.Replace(reloadBaseConfig, `interval: "1m"`, `interval: "5m"`, 1))
+	reloader.reloadAndLog("SIGHUP")
+	writeFile(t, path, "name: [unterminated")
+	reloader.reloadAndLog("file changed")
+
+	applied := <-stream
+	if applied.Type != events.ConfigReloaded || applied.Status != "ok" || applied.Data["reason"] != "SIGHUP" {
+		t.Fatalf("applied event = %+v", applied)
+	}
+	if updated, _ := applied.Data["updated"].([]string); strings.Join(updated, ",") != "disk" {
+		t.Fatalf("updated = %v, want disk", applied.Data["updated"])
+	}
+	rejected := <-stream
+	if rejected.Status != "error" || rejected.Message == "" {
+		t.Fatalf("rejected event = %+v", rejected)
+	}
+}
+
+func TestRestartRequiredChanges(t *testing.T) {
+	previous := &config.Config{MaxConcurrentChecks: 2, StateLogPeriod: "24h"}
+	next := &config.Config{MaxConcurrentChecks: 4, StateLogPeriod: "24h", DebugOutputMaxChars: 100}
+
+	got := restartRequiredChanges(previous, next)
+	if strings.Join(got, ",") != "max_concurrent_checks,debug_output_max_chars" {
+		t.Fatalf("restartRequiredChanges() = %v", got)
+	}
+}
+
+func TestWatchConfigFileSignalsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, reloadBaseConfig)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	changed := make(chan struct{}, 1)
+	go watchConfigFile(path, 10*time.Millisecond, stop, changed)
+
+	select {
+	case <-changed:
+		t.Fatalf("watcher signaled before the file changed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeFile(t, path, reloadBaseConfig+"\n# edited\n")
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatalf("watcher did not signal the change")
+	}
+}
+
+func TestConfigReloaderIgnoresStartPolicy(t *testing.T) {
+	withPolicy := strings.Replace(reloadBaseConfig, `  interval: "1m"`, `  interval: "1h"
+  on_start: run`, 1)
+	reloader, path := newTestReloader(t, withPolicy)
+	reloader.sched.ApplyState(map[string]scheduler.CheckState{"disk": {LastStatus: "pass", LastRun: time.Now().Add(-10 * time.Minute)}})
+	if disk := reloader.sched.GetAllChecks()["disk"]; disk.NextRun.After(time.Now()) {
+		t.Fatalf("on_start run should run the check at startup, next run %v", disk.NextRun)
+	}
+
+	writeFile(t, path, strings.Replace(withPolicy, `interval: "1h"`, `interval: "5m"`, 1))
+	result, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if strings.Join(result.Updated, ",") != "disk" {
+		t.Fatalf("Reload() = %+v, want updated disk", result)
+	}
+	disk := reloader.sched.GetAllChecks()["disk"]
+	if !disk.NextRun.After(time.Now()) || disk.NextRun.After(time.Now().Add(5*time.Minute)) {
+		t.Fatalf("next run after reload = %v, want the next 5m slot after the last run", disk.NextRun)
+	}
+}
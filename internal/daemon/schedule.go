@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const maxPreviewCount = 1000
+
+// runScheduleCLI handles the schedule subcommands. `schedule preview` prints
+// the next fire times of a cron expression.
+func runScheduleCLI(args []string, stdout io.Writer) int {
+	if len(args) == 0 || args[0] != "preview" {
+		printScheduleUsage()
+		return 1
+	}
+
+	fs := flag.NewFlagSet("schedule preview", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var (
+		count         int
+		timezone      string
+		from          string
+		configPathArg string
+	)
+	fs.IntVar(&count, "n", 10, "Number of fire times to print")
+	fs.IntVar(&count, "count", 10, "Number of fire times to print")
+	fs.StringVar(&timezone, "timezone", "", "IANA time zone of the schedule")
+	fs.StringVar(&from, "from", "", "Start time, RFC 3339")
+	fs.StringVar(&configPathArg, "c", "", "Path to configuration file")
+	fs.StringVar(&configPathArg, "config", "", "Path to configuration file")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printScheduleUsage()
+		return 1
+	}
+	expr := strings.Join(fs.Args(), " ")
+	if expr == "" {
+		printScheduleUsage()
+		return 1
+	}
+	if count < 1 || count > maxPreviewCount {
+		fmt.Fprintf(os.Stderr, "Error: --count must be between 1 and %d\n", maxPreviewCount)
+		return 1
+	}
+
+	if timezone == "" && configPathArg != "" {
+		cfg, err := config.Load(configPathArg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			return 1
+		}
+		timezone = cfg.Timezone
+	}
+	location, err := config.LoadLocation(timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	start := time.Now()
+	if from != "" {
+		if start, err = time.Parse(time.RFC3339, from); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --from time %q, expected RFC 3339\n", from)
+			return 1
+		}
+	}
+
+	cron, err := scheduler.ParseCronExpression(expr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Expression: %s\n", cron)
+	fmt.Fprintf(stdout, "Time zone:  %s\n\n", location)
+	next := start.In(location)
+	for range count {
+		next = cron.Next(next)
+		if next.IsZero() {
+			fmt.Fprintf(stdout, "(no further runs)\n")
+			break
+		}
+		fmt.Fprintf(stdout, "%s\n", next.Format("Mon 2006-01-02 15:04:05 MST"))
+	}
+	return 0
+}
+
+func printScheduleUsage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  foghorn-daemon schedule preview [options] <expression>\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  -n, --count <n>        Number of fire times to print (default: 10)\n")
+	fmt.Fprintf(os.Stderr, "  --timezone <zone>      IANA time zone, such as Europe/Berlin (default: UTC)\n")
+	fmt.Fprintf(os.Stderr, "  -c, --config <path>    Use the timezone of this configuration file\n")
+	fmt.Fprintf(os.Stderr, "  --from <time>          Start time, RFC 3339 (default: now)\n")
+}
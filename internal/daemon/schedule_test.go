@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSchedulePreviewCLI(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"preview", "-n", "3", "--timezone", "Europe/Berlin", "--from", "2025-03-28T12:00:00Z", "30", "2", "*", "*", "*"}
+	if code := runScheduleCLI(args, &out); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	want := []string{
+		"Expression: 30 2 * * *",
+		"Time zone:  Europe/Berlin",
+		"Sat 2025-03-29 02:30:00 CET",
+		"Sun 2025-03-30 03:00:00 CEST",
+		"Mon 2025-03-31 02:30:00 CEST",
+	}
+	for _, line := range want {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("preview missing %q:\n%s", line, out.String())
+		}
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"show", "@daily"},
+		{"preview"},
+		{"preview", "-n", "0", "@daily"},
+		{"preview", "--timezone", "Mars/Olympus", "@daily"},
+		{"preview", "0 9 * * MON-FRY"},
+	} {
+		if code := runScheduleCLI(args, &bytes.Buffer{}); code != 1 {
+			t.Errorf("runScheduleCLI(%v) = %d, want 1", args, code)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/internal/statusapi"
+	"github.com/pfarrer/foghorn/notifier"
+)
+
+// statusSecurityOptions builds the TLS and token options of the status API.
+// Command line TLS flags take precedence over the status_api config section.
+func statusSecurityOptions(cfg config.StatusAPIConfig, flags config.StatusTLSConfig, secrets notifier.SecretResolver) ([]statusapi.Option, error) {
+	tlsSettings := cfg.TLS
+	if flags.CertFile != "" || flags.KeyFile != "" || flags.ClientCAFile != "" {
+		tlsSettings = flags
+	}
+	if (tlsSettings.CertFile == "") != (tlsSettings.KeyFile == "") {
+		return nil, fmt.Errorf("status API TLS certificate and key must be set together")
+	}
+	if tlsSettings.ClientCAFile != "" && tlsSettings.CertFile == "" {
+		return nil, fmt.Errorf("status API client CA requires a TLS certificate and key")
+	}
+
+	var opts []statusapi.Option
+	if tlsSettings.CertFile != "" {
+		tlsConfig, err := statusapi.ServerTLSConfig(tlsSettings.CertFile, tlsSettings.KeyFile, tlsSettings.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, statusapi.WithTLS(tlsConfig))
+	}
+
+	if len(cfg.Tokens) > 0 {
+		if secrets == nil {
+			return nil, fmt.Errorf("status API tokens require the secret store")
+		}
+		tokens := make([]statusapi.Token, 0, len(cfg.Tokens))
+		for _, token := range cfg.Tokens {
+			value, err := secrets.Resolve(token.Token)
+			if err != nil {
+				return nil, fmt.Errorf("status API token %s: %w", token.Name, err)
+			}
+			if value == "" {
+				return nil, fmt.Errorf("status API token %s is empty", token.Name)
+			}
+			tokens = append(tokens, statusapi.Token{
+				Name:  token.Name,
+				Value: value,
+				Scope: statusapi.Scope(token.EffectiveScope()),
+			})
+		}
+		opts = append(opts, statusapi.WithTokens(tokens))
+	}
+	return opts, nil
+}
+
+// isLoopbackListen reports whether addr only accepts local connections.
+func isLoopbackListen(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/internal/statusapi"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type mapSecrets map[string]string
+
+func (m mapSecrets) Resolve(ref string) (string, error) {
+	value, ok := m[ref]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", ref)
+	}
+	return value, nil
+}
+
+func TestStatusSecurityOptionsResolvesTokens(t *testing.T) {
+	cfg := config.StatusAPIConfig{Tokens: []config.StatusTokenConfig{
+		{Name: "dashboard", Token: "secret://status/dashboard"},
+		{Name: "ops", Token: "secret://status/ops", Scope: config.StatusScopeOperator},
+	}}
+	secrets := mapSecrets{"secret://status/dashboard": "read-token", "secret://status/ops": "operator-token"}
+
+	opts, err := statusSecurityOptions(cfg, config.StatusTLSConfig{}, secrets)
+	if err != nil {
+		t.Fatalf("statusSecurityOptions() error = %v", err)
+	}
+	handler := statusapi.NewHandler(func() scheduler.Snapshot { return scheduler.Snapshot{} }, opts...)
+
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "read-token": http.StatusOK, "operator-token": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, statusapi.StatusPath, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("token %q: status = %d, want %d", token, rec.Code, want)
+		}
+	}
+}
+
+func TestStatusSecurityOptionsErrors(t *testing.T) {
+	tokens := config.StatusAPIConfig{Tokens: []config.StatusTokenConfig{{Name: "dashboard", Token: "secret://status/dashboard"}}}
+	tests := []struct {
+		name    string
+		cfg     config.StatusAPIConfig
+		flags   config.StatusTLSConfig
+		secrets mapSecrets
+		want    string
+	}{
+		{name: "tokens without secret store", cfg: tokens, want: "require the secret store"},
+		{name: "missing secret", cfg: tokens, secrets: mapSecrets{}, want: "status API token dashboard"},
+		{name: "certificate without key", flags: config.StatusTLSConfig{CertFile: "tls.crt"}, want: "must be set together"},
+		{name: "unreadable certificate", flags: config.StatusTLSConfig{CertFile: "missing.crt", KeyFile: "missing.key"}, want: "failed to load TLS certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.secrets == nil {
+				_, err = statusSecurityOptions(tt.cfg, tt.flags, nil)
+			} else {
+				_, err = statusSecurityOptions(tt.cfg, tt.flags, tt.secrets)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackListen(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:7676": true,
+		"[::1]:7676":     true,
+		"localhost:7676": true,
+		"0.0.0.0:7676":   false,
+		":7676":          false,
+		"10.0.0.5:7676":  false,
+	} {
+		if got := isLoopbackListen(addr); got != want {
+			t.Errorf("isLoopbackListen(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package statusapi
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type Scope string
+
+const (
+	ScopeRead     Scope = "read"
+	ScopeOperator Scope = "operator"
+)
+
+// Token is a bearer token accepted by the server. Operator tokens may also
+// read.
+type Token struct {
+	Name  string
+	Value string
+	Scope Scope
+}
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// WithTokens requires a bearer token on every endpoint except /healthz.
+// Requests that change state need an operator token.
+func WithTokens(tokens []Token) Option {
+	return func(o *handlerOptions) {
+		o.tokens = tokens
+	}
+}
+
+func requireTokens(tokens []Token, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := matchToken(tokens, bearerToken(r))
+		if token == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="foghorn"`)
+			http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+			return
+		}
+		if requiredScope(r) == ScopeOperator && token.Scope != ScopeOperator {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="foghorn", error="insufficient_scope", scope="operator"`)
+			http.Error(w, ErrForbidden.Error()+": operator scope required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requiredScope(r *http.Request) Scope {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeOperator
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// matchToken compares against every token in constant time, so the response
+// time does not reveal how much of a token matched.
+func matchToken(tokens []Token, value string) *Token {
+	if value == "" {
+		return nil
+	}
+	var match *Token
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(tokens[i].Value), []byte(value)) == 1 && match == nil {
+			match = &tokens[i]
+		}
+	}
+	return match
+}
+
+// ServerTLSConfig loads the server certificate. With a client CA file,
+// clients must present a certificate signed by that CA.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientTLSConfig builds the TLS settings of a client. caFile replaces the
+// system roots, and certFile/keyFile present a client certificate for mutual
+// TLS.
+func ClientTLSConfig(caFile, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
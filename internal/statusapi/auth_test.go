@@ -0,0 +1,181 @@
+package statusapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+func TestTokenScopes(t *testing.T) {
+	controller := &stubController{}
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithController(controller), WithTokens([]Token{
+		{Name: "dashboard", Value: "read-token", Scope: ScopeRead},
+		{Name: "ops", Value: "operator-token", Scope: ScopeOperator},
+	})))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{name: "healthz without token", method: http.MethodGet, path: "/healthz", want: http.StatusOK},
+		{name: "status without token", method: http.MethodGet, path: StatusPath, want: http.StatusUnauthorized},
+		{name: "status with wrong token", method: http.MethodGet, path: StatusPath, token: "nope", want: http.StatusUnauthorized},
+		{name: "status with read token", method: http.MethodGet, path: StatusPath, token: "read-token", want: http.StatusOK},
+		{name: "status with operator token", method: http.MethodGet, path: StatusPath, token: "operator-token", want: http.StatusOK},
+		{name: "run with read token", method: http.MethodPost, path: "/v1/checks/api/run", token: "read-token", want: http.StatusForbidden},
+		{name: "run with operator token", method: http.MethodPost, path: "/v1/checks/api/run", token: "operator-token", want: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Fatalf("401 response should carry WWW-Authenticate")
+			}
+		})
+	}
+	if len(controller.actions) != 1 {
+		t.Fatalf("actions = %v, want only the operator run", controller.actions)
+	}
+}
+
+func TestClientToken(t *testing.T) {
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithController(&stubController{}), WithTokens([]Token{{Name: "dashboard", Value: "read-token", Scope: ScopeRead}})))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := NewClient(server.URL).GetStatus(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("GetStatus() without token error = %v, want ErrUnauthorized", err)
+	}
+	client := NewClient(server.URL, WithToken("read-token"))
+	if _, err := client.GetStatus(ctx); err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if err := client.TriggerCheck(ctx, "api"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("TriggerCheck() error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeCertificate(t, dir, "ca", nil, nil)
+	writeCertificate(t, dir, "server", ca, caKey)
+	writeCertificate(t, dir, "client", ca, caKey)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	serverTLS, err := ServerTLSConfig(path("server.crt"), path("server.key"), path("ca.crt"))
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+	server := httptest.NewUnstartedServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{Counts: scheduler.SnapshotCounts{Total: 3}}
+	}))
+	server.TLS = serverTLS
+	server.StartTLS()
+	defer server.Close()
+
+	clientTLS, err := ClientTLSConfig(path("ca.crt"), path("client.crt"), path("client.key"), false)
+	if err != nil {
+		t.Fatalf("ClientTLSConfig() error = %v", err)
+	}
+	snapshot, err := NewClient(server.URL, WithClientTLS(clientTLS)).GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if snapshot.Counts.Total != 3 {
+		t.Fatalf("Counts.Total = %d, want 3", snapshot.Counts.Total)
+	}
+
+	withoutCert, err := ClientTLSConfig(path("ca.crt"), "", "", false)
+	if err != nil {
+		t.Fatalf("ClientTLSConfig() error = %v", err)
+	}
+	if _, err := NewClient(server.URL, WithClientTLS(withoutCert)).GetStatus(context.Background()); err == nil {
+		t.Fatalf("GetStatus() without client certificate should fail")
+	}
+}
+
+// writeCertificate writes name.crt and name.key to dir. Without a parent the
+// certificate is a self-signed CA.
+func writeCertificate(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+	writePEM(t, filepath.Join(dir, name+".crt"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return cert, key
+}
+
+func writePEM(t *testing.T, path, blockType string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
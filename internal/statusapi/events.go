@@ -0,0 +1,161 @@
+package statusapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pfarrer/foghorn/events"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const (
+	EventsPath        = "/v1/events"
+	keepAliveInterval = 15 * time.Second
+)
+
+type EventSource interface {
+	Subscribe(lastID uint64) (<-chan events.Event, func())
+}
+
+type LogStreamer interface {
+	StreamLogs(ctx context.Context, checkName string, follow bool, fn func(line string) error) error
+}
+
+// WithEvents enables the /v1/events Server-Sent Events stream.
+func WithEvents(source EventSource) Option {
+	return func(o *handlerOptions) {
+		o.events = source
+	}
+}
+
+// WithLogs enables /v1/checks/{name}/logs for running checks.
+func WithLogs(streamer LogStreamer) Option {
+	return func(o *handlerOptions) {
+		o.logs = streamer
+	}
+}
+
+// eventsHandler streams scheduler events. Clients can resume after a
+// reconnect with the Last-Event-ID header and filter with ?check= and ?type=.
+func eventsHandler(source EventSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		var lastID uint64
+		if header := r.Header.Get("Last-Event-ID"); header != "" {
+			parsed, err := strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+				return
+			}
+			lastID = parsed
+		}
+		checkFilter := r.URL.Query().Get("check")
+		typeFilter := r.URL.Query().Get("type")
+
+		stream, unsubscribe := source.Subscribe(lastID)
+		defer unsubscribe()
+
+		writeStreamHeaders(w)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+				if (checkFilter != "" && event.Check != checkFilter) || (typeFilter != "" && event.Type != typeFilter) {
+					continue
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// logsHandler returns the output of a running check as plain text, or
+// streams it as Server-Sent Events with ?follow=1 until the check finishes.
+func logsHandler(streamer LogStreamer, snapshotFn func() scheduler.Snapshot) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		check, ok := snapshotFn().Checks[name]
+		if !ok {
+			http.Error(w, scheduler.ErrCheckNotFound.Error(), http.StatusNotFound)
+			return
+		}
+		if !check.Running {
+			http.Error(w, scheduler.ErrCheckNotRunning.Error(), http.StatusConflict)
+			return
+		}
+
+		follow := r.URL.Query().Get("follow")
+		if follow != "1" && follow != "true" {
+			var lines []byte
+			err := streamer.StreamLogs(r.Context(), name, false, func(line string) error {
+				lines = append(lines, line...)
+				lines = append(lines, '\n')
+				return nil
+			})
+			if err != nil {
+				http.Error(w, err.Error(), errorStatus(err))
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write(lines)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+		writeStreamHeaders(w)
+		flusher.Flush()
+		err := streamer.StreamLogs(r.Context(), name, true, func(line string) error {
+			if _, err := fmt.Fprintf(w, "event: log\ndata: %s\n\n", line); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		if err != nil {
+			errorData, _ := json.Marshal(map[string]string{"error": err.Error()})
+			_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", errorData)
+		}
+		_, _ = fmt.Fprint(w, "event: end\ndata: {}\n\n")
+		flusher.Flush()
+	}
+}
+
+func writeStreamHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+}
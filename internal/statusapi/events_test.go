@@ -0,0 +1,143 @@
+package statusapi
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/events"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+// readEvents reads n SSE frames and returns their event and data lines.
+func readEvents(t *testing.T, body io.Reader, n int) []string {
+	t.Helper()
+	scanner := bufio.NewScanner(body)
+	var frames []string
+	var current []string
+	for len(frames) < n && scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(current) > 0 {
+				frames = append(frames, strings.Join(current, "|"))
+				current = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "event: ") || strings.HasPrefix(line, "data: ") {
+			current = append(current, line)
+		}
+	}
+	if len(frames) < n {
+		t.Fatalf("read %d events, want %d (err: %v)", len(frames), n, scanner.Err())
+	}
+	return frames
+}
+
+func TestEventsEndpoint(t *testing.T) {
+	bus := events.NewBus()
+	bus.Publish(events.Event{Type: events.CheckQueued, Check: "api"})
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithEvents(bus)))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+EventsPath+"?check=api", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", EventsPath, err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	bus.Publish(events.Event{Type: events.CheckStarted, Check: "other"})
+	bus.Publish(events.Event{Type: events.CheckCompleted, Check: "api", Status: "pass"})
+
+	frames := readEvents(t, resp.Body, 1)
+	if !strings.HasPrefix(frames[0], "event: check.completed|data: {\"id\":3,") || !strings.Contains(frames[0], `"status":"pass"`) {
+		t.Fatalf("frame = %s", frames[0])
+	}
+}
+
+func TestEventsEndpointResumesFromLastEventID(t *testing.T) {
+	bus := events.NewBus()
+	bus.Publish(events.Event{Type: events.CheckQueued, Check: "a"})
+	bus.Publish(events.Event{Type: events.CheckQueued, Check: "b"})
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithEvents(bus)))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+EventsPath, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", EventsPath, err)
+	}
+	defer resp.Body.Close()
+
+	frames := readEvents(t, resp.Body, 1)
+	if !strings.Contains(frames[0], `"check":"b"`) {
+		t.Fatalf("frame = %s, want replayed event for b", frames[0])
+	}
+}
+
+type stubLogStreamer struct {
+	lines []string
+}
+
+func (s *stubLogStreamer) StreamLogs(ctx context.Context, checkName string, follow bool, fn func(line string) error) error {
+	for _, line := range s.lines {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestLogsEndpoint(t *testing.T) {
+	streamer := &stubLogStreamer{lines: []string{"connecting", "password=[REDACTED]"}}
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{Checks: map[string]scheduler.CheckStatus{
+			"mail": {Running: true},
+			"idle": {},
+		}}
+	}, WithLogs(streamer)))
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "mail/logs", wantStatus: http.StatusOK, wantBody: "connecting\npassword=[REDACTED]\n"},
+		{path: "mail/logs?follow=1", wantStatus: http.StatusOK, wantBody: "event: log\ndata: connecting\n\nevent: log\ndata: password=[REDACTED]\n\nevent: end\ndata: {}\n\n"},
+		{path: "idle/logs", wantStatus: http.StatusConflict, wantBody: "check is not running\n"},
+		{path: "missing/logs", wantStatus: http.StatusNotFound, wantBody: "check not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(server.URL + ChecksPath + tt.path)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+				t.Fatalf("GET %s = %d %q, want %d %q", tt.path, resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package statusapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+	"github.com/pfarrer/foghorn/state"
+)
+
+type HistorySource interface {
+	History(q state.HistoryQuery) (state.HistoryPage, error)
+}
+
+// WithHistory enables /v1/checks/{name}/history.
+func WithHistory(source HistorySource) Option {
+	return func(o *handlerOptions) {
+		o.history = source
+	}
+}
+
+// historyHandler pages through the results of a check, newest first. Checks
+// removed from the configuration stay queryable while they have results.
+func historyHandler(source HistorySource, snapshotFn func() scheduler.Snapshot) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		query, err := ParseHistoryQuery(name, r.URL.Query(), time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		page, err := source.History(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := snapshotFn().Checks[name]; !ok && page.Summary.Runs == 0 {
+			http.Error(w, scheduler.ErrCheckNotFound.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}
+}
+
+// ParseHistoryQuery reads since, until, status, limit and cursor parameters.
+// Times are RFC 3339 or durations before now like 24h or 7d, and status takes
+// a comma separated list.
+func ParseHistoryQuery(check string, values url.Values, now time.Time) (state.HistoryQuery, error) {
+	query := state.HistoryQuery{Check: check, Cursor: values.Get("cursor")}
+	var err error
+	if query.Since, err = state.ParseTimeBound(values.Get("since"), now); err != nil {
+		return state.HistoryQuery{}, fmt.Errorf("since: %w", err)
+	}
+	if query.Until, err = state.ParseTimeBound(values.Get("until"), now); err != nil {
+		return state.HistoryQuery{}, fmt.Errorf("until: %w", err)
+	}
+	for _, status := range strings.Split(values.Get("status"), ",") {
+		switch status = strings.TrimSpace(status); status {
+		case "":
+		case "pass", "warn", "fail", "error", scheduler.StatusSuppressed, scheduler.StatusMaintenance:
+			query.Statuses = append(query.Statuses, status)
+		default:
+			return state.HistoryQuery{}, fmt.Errorf("status: unknown status %q", status)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 || query.Limit > state.MaxHistoryLimit {
+			return state.HistoryQuery{}, fmt.Errorf("limit must be between 1 and %d", state.MaxHistoryLimit)
+		}
+	}
+	return query, nil
+}
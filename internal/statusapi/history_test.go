@@ -0,0 +1,79 @@
+package statusapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+	"github.com/pfarrer/foghorn/state"
+)
+
+type recordHistory []state.Record
+
+func (r recordHistory) History(q state.HistoryQuery) (state.HistoryPage, error) {
+	return state.QueryHistory(r, q)
+}
+
+func TestHistoryEndpoint(t *testing.T) {
+	now := time.Now().UTC()
+	records := recordHistory{
+		{CheckName: "api", Status: "pass", DurationMs: 100, CompletedAt: now.Add(-3 * time.Hour)},
+		{CheckName: "api", Status: "fail", DurationMs: 300, CompletedAt: now.Add(-2 * time.Hour)},
+		{CheckName: "api", Status: "pass", DurationMs: 200, CompletedAt: now.Add(-time.Hour)},
+		{CheckName: "removed", Status: "pass", CompletedAt: now.Add(-time.Hour)},
+	}
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{Checks: map[string]scheduler.CheckStatus{"api": {Name: "api"}, "new": {Name: "new"}}}
+	}, WithHistory(records)))
+	defer server.Close()
+
+	get := func(path string) (*http.Response, state.HistoryPage) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		defer resp.Body.Close()
+		var page state.HistoryPage
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+		}
+		return resp, page
+	}
+
+	resp, page := get("/v1/checks/api/history?since=150m&limit=1")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].DurationMs != 200 || page.NextCursor == "" {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Summary.Runs != 2 || page.Summary.UptimePercent != 50 || page.Summary.MTTRSeconds != 3600 {
+		t.Fatalf("summary = %+v", page.Summary)
+	}
+
+	_, page = get("/v1/checks/api/history?status=fail")
+	if len(page.Entries) != 1 || page.Entries[0].Status != "fail" {
+		t.Fatalf("status filter entries = %+v", page.Entries)
+	}
+
+	tests := map[string]int{
+		"/v1/checks/removed/history":        http.StatusOK,
+		"/v1/checks/new/history":            http.StatusOK,
+		"/v1/checks/missing/history":        http.StatusNotFound,
+		"/v1/checks/api/history?limit=0":    http.StatusBadRequest,
+		"/v1/checks/api/history?status=ok":  http.StatusBadRequest,
+		"/v1/checks/api/history?since=week": http.StatusBadRequest,
+		"/v1/checks/api/history?cursor=x":   http.StatusBadRequest,
+	}
+	for path, want := range tests {
+		if resp, _ := get(path); resp.StatusCode != want {
+			t.Errorf("GET %s status = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
@@ -2,9 +2,15 @@ package statusapi
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pfarrer/foghorn/scheduler"
@@ -12,12 +18,84 @@ import (
 
 const (
 	StatusPath         = "/v1/status"
+	ChecksPath         = "/v1/checks/"
+	MetricsPath        = "/metrics"
+	ReloadPath         = "/v1/reload"
 	DefaultListenAddr  = "127.0.0.1:7676"
 	DefaultBaseURL     = "http://127.0.0.1:7676"
 	defaultReadTimeout = 2 * time.Second
 )
 
-func NewHandler(snapshotFn func() scheduler.Snapshot) http.Handler {
+type Controller interface {
+	TriggerCheck(name string) error
+	PauseCheck(name string) error
+	ResumeCheck(name string) error
+}
+
+// Reloader reloads the daemon configuration.
+type Reloader interface {
+	Reload() (scheduler.ReconcileResult, error)
+}
+
+type ReloaderFunc func() (scheduler.ReconcileResult, error)
+
+func (f ReloaderFunc) Reload() (scheduler.ReconcileResult, error) {
+	return f()
+}
+
+type Option func(*handlerOptions)
+
+type handlerOptions struct {
+	controller Controller
+	metrics    http.Handler
+	events     EventSource
+	logs       LogStreamer
+	reloader   Reloader
+	tokens     []Token
+	history    HistorySource
+	silences   SilenceManager
+	tls        *tls.Config
+}
+
+// WithController enables the check control endpoints.
+func WithController(controller Controller) Option {
+	return func(o *handlerOptions) {
+		o.controller = controller
+	}
+}
+
+// WithReloader enables POST /v1/reload.
+func WithReloader(reloader Reloader) Option {
+	return func(o *handlerOptions) {
+		o.reloader = reloader
+	}
+}
+
+// WithTLS serves the API over HTTPS with the given settings.
+func WithTLS(config *tls.Config) Option {
+	return func(o *handlerOptions) {
+		o.tls = config
+	}
+}
+
+// WithMetrics serves the given handler on /metrics.
+func WithMetrics(handler http.Handler) Option {
+	return func(o *handlerOptions) {
+		o.metrics = handler
+	}
+}
+
+type ActionResponse struct {
+	Check  string `json:"check"`
+	Action string `json:"action"`
+}
+
+func NewHandler(snapshotFn func() scheduler.Snapshot, opts ...Option) http.Handler {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc(StatusPath, func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -34,29 +112,147 @@ func NewHandler(snapshotFn func() scheduler.Snapshot) http.Handler {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
+	if options.metrics != nil {
+		mux.Handle(MetricsPath, options.metrics)
+	}
+	if options.controller != nil {
+		mux.HandleFunc("POST "+ChecksPath+"{name}/run", actionHandler("run", http.StatusAccepted, options.controller.TriggerCheck))
+		mux.HandleFunc("POST "+ChecksPath+"{name}/pause", actionHandler("pause", http.StatusOK, options.controller.PauseCheck))
+		mux.HandleFunc("POST "+ChecksPath+"{name}/resume", actionHandler("resume", http.StatusOK, options.controller.ResumeCheck))
+	}
+	if options.reloader != nil {
+		mux.HandleFunc("POST "+ReloadPath, reloadHandler(options.reloader))
+	}
+	if options.events != nil {
+		mux.HandleFunc("GET "+EventsPath, eventsHandler(options.events))
+	}
+	if options.logs != nil {
+		mux.HandleFunc("GET "+ChecksPath+"{name}/logs", logsHandler(options.logs, snapshotFn))
+	}
+	if options.history != nil {
+		mux.HandleFunc("GET "+ChecksPath+"{name}/history", historyHandler(options.history, snapshotFn))
+	}
+	if options.silences != nil {
+		mux.HandleFunc("GET "+SilencesPath, silencesHandler(options.silences))
+		mux.HandleFunc("POST "+SilencesPath, createSilenceHandler(options.silences))
+		mux.HandleFunc("DELETE "+SilencesPath+"/{name}", deleteSilenceHandler(options.silences))
+	}
+	if len(options.tokens) > 0 {
+		return requireTokens(options.tokens, mux)
+	}
 	return mux
 }
 
-func StartServer(addr string, snapshotFn func() scheduler.Snapshot) *http.Server {
-	return &http.Server{
+func actionHandler(action string, successStatus int, fn func(name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if err := fn(name); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(successStatus)
+		_ = json.NewEncoder(w).Encode(ActionResponse{Check: name, Action: action})
+	}
+}
+
+// reloadHandler answers with the applied changes, or 422 when the new
+// configuration was rejected and the running one was kept.
+func reloadHandler(reloader Reloader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := reloader.Reload()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, scheduler.ErrCheckNotFound), errors.Is(err, scheduler.ErrSilenceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scheduler.ErrInvalidSilence):
+		return http.StatusBadRequest
+	case errors.Is(err, scheduler.ErrCheckRunning), errors.Is(err, scheduler.ErrCheckNotRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func StartServer(addr string, snapshotFn func() scheduler.Snapshot, opts ...Option) *http.Server {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	// Streaming responses never go idle, so cancel them when shutdown starts.
+	streams, cancelStreams := context.WithCancel(context.Background())
+	srv := &http.Server{
 		Addr:              addr,
-		Handler:           NewHandler(snapshotFn),
+		Handler:           NewHandler(snapshotFn, opts...),
 		ReadHeaderTimeout: defaultReadTimeout,
+		BaseContext:       func(net.Listener) context.Context { return streams },
+		TLSConfig:         options.tls,
+	}
+	srv.RegisterOnShutdown(cancelStreams)
+	return srv
+}
+
+// ListenAndServe serves srv over HTTPS when it was started with WithTLS and
+// over plain HTTP otherwise.
+func ListenAndServe(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
 	}
+	return srv.ListenAndServe()
 }
 
 type Client struct {
 	baseURL string
+	token   string
 	client  *http.Client
 }
 
-func NewClient(baseURL string) *Client {
-	return &Client{
+type ClientOption func(*Client)
+
+// WithToken sends the bearer token with every request.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithClientTLS sets the TLS settings used for https:// base URLs.
+func WithClientTLS(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: config,
+		}
+	}
+}
+
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
 		baseURL: baseURL,
 		client: &http.Client{
 			Timeout: 2 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.client.Do(req)
 }
 
 func (c *Client) GetStatus(ctx context.Context) (scheduler.Snapshot, error) {
@@ -64,11 +260,14 @@ func (c *Client) GetStatus(ctx context.Context) (scheduler.Snapshot, error) {
 	if err != nil {
 		return scheduler.Snapshot{}, err
 	}
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return scheduler.Snapshot{}, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return scheduler.Snapshot{}, responseError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return scheduler.Snapshot{}, fmt.Errorf("status endpoint returned %s", resp.Status)
 	}
@@ -79,3 +278,79 @@ func (c *Client) GetStatus(ctx context.Context) (scheduler.Snapshot, error) {
 	}
 	return snapshot, nil
 }
+
+func (c *Client) TriggerCheck(ctx context.Context, name string) error {
+	return c.checkAction(ctx, name, "run")
+}
+
+func (c *Client) PauseCheck(ctx context.Context, name string) error {
+	return c.checkAction(ctx, name, "pause")
+}
+
+func (c *Client) ResumeCheck(ctx context.Context, name string) error {
+	return c.checkAction(ctx, name, "resume")
+}
+
+func (c *Client) checkAction(ctx context.Context, name, action string) error {
+	resp, err := c.post(ctx, ChecksPath+url.PathEscape(name)+"/"+action)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Reload asks the daemon to reload its configuration and returns the
+// applied check changes.
+func (c *Client) Reload(ctx context.Context) (scheduler.ReconcileResult, error) {
+	resp, err := c.post(ctx, ReloadPath)
+	if err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return scheduler.ReconcileResult{}, responseError(resp)
+	}
+
+	var result scheduler.ReconcileResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) post(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// responseError turns an error response into an error carrying the message
+// of the daemon, mapping scheduler errors back to their sentinel values.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	message := strings.TrimSpace(string(body))
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: missing or invalid status API token", ErrUnauthorized)
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: %s", ErrForbidden, strings.TrimPrefix(message, ErrForbidden.Error()+": "))
+	}
+	for _, sentinel := range []error{scheduler.ErrCheckNotFound, scheduler.ErrCheckRunning} {
+		if message == sentinel.Error() {
+			return sentinel
+		}
+		if rest, ok := strings.CutPrefix(message, sentinel.Error()+": "); ok {
+			return fmt.Errorf("%w: %s", sentinel, rest)
+		}
+	}
+	if message == "" {
+		return fmt.Errorf("status API returned %s", resp.Status)
+	}
+	return fmt.Errorf("status API returned %s: %s", resp.Status, message)
+}
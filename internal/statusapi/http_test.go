@@ -2,8 +2,11 @@ package statusapi
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,3 +67,209 @@ func TestStatusPathMethodNotAllowed(t *testing.T) {
 		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
 	}
 }
+
+func TestClientGetStatusIncludesLastResult(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	exitCode := 0
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{
+			Checks: map[string]scheduler.CheckStatus{
+				"tls": {
+					Name:       "tls",
+					LastStatus: "warn",
+					LastResult: &scheduler.Result{
+						CheckName:  "tls",
+						Status:     "warn",
+						Message:    "certificate expires in 7 days",
+						Data:       map[string]interface{}{"days_remaining": float64(7)},
+						ExitCode:   &exitCode,
+						Image:      "example/openssl-check:1.0.0",
+						StartedAt:  now.Add(-time.Second),
+						FinishedAt: now,
+					},
+				},
+			},
+		}
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL).GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	result := got.Checks["tls"].LastResult
+	if result == nil {
+		t.Fatalf("LastResult missing from status response")
+	}
+	if result.Message != "certificate expires in 7 days" || result.Data["days_remaining"] != float64(7) {
+		t.Fatalf("LastResult = %+v, want message and data", result)
+	}
+	if result.Duration() != time.Second {
+		t.Fatalf("Duration() = %v, want 1s", result.Duration())
+	}
+}
+
+type stubController struct {
+	actions []string
+}
+
+func (c *stubController) TriggerCheck(name string) error {
+	return c.record("run", name)
+}
+
+func (c *stubController) PauseCheck(name string) error {
+	return c.record("pause", name)
+}
+
+func (c *stubController) ResumeCheck(name string) error {
+	return c.record("resume", name)
+}
+
+func (c *stubController) record(action, name string) error {
+	switch name {
+	case "missing":
+		return fmt.Errorf("%w: %s", scheduler.ErrCheckNotFound, name)
+	case "busy":
+		return fmt.Errorf("%w: %s", scheduler.ErrCheckRunning, name)
+	}
+	c.actions = append(c.actions, action+":"+name)
+	return nil
+}
+
+func TestControlEndpoints(t *testing.T) {
+	controller := &stubController{}
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithController(controller)))
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{method: http.MethodPost, path: "/v1/checks/api/run", want: http.StatusAccepted},
+		{method: http.MethodPost, path: "/v1/checks/api/pause", want: http.StatusOK},
+		{method: http.MethodPost, path: "/v1/checks/api/resume", want: http.StatusOK},
+		{method: http.MethodPost, path: "/v1/checks/missing/run", want: http.StatusNotFound},
+		{method: http.MethodPost, path: "/v1/checks/busy/run", want: http.StatusConflict},
+		{method: http.MethodGet, path: "/v1/checks/api/run", want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	want := "run:api pause:api resume:api"
+	if got := strings.Join(controller.actions, " "); got != want {
+		t.Fatalf("actions = %q, want %q", got, want)
+	}
+}
+
+func TestControlEndpointsDisabledWithoutController(t *testing.T) {
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/checks/api/run", "", nil)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestClientCheckControl(t *testing.T) {
+	controller := &stubController{}
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithController(controller)))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx := context.Background()
+	if err := client.TriggerCheck(ctx, "api"); err != nil {
+		t.Fatalf("TriggerCheck() error = %v", err)
+	}
+	if err := client.PauseCheck(ctx, "api"); err != nil {
+		t.Fatalf("PauseCheck() error = %v", err)
+	}
+	if err := client.ResumeCheck(ctx, "api"); err != nil {
+		t.Fatalf("ResumeCheck() error = %v", err)
+	}
+	if err := client.TriggerCheck(ctx, "missing"); !errors.Is(err, scheduler.ErrCheckNotFound) {
+		t.Fatalf("TriggerCheck(missing) error = %v, want ErrCheckNotFound", err)
+	}
+	if err := client.TriggerCheck(ctx, "busy"); !errors.Is(err, scheduler.ErrCheckRunning) {
+		t.Fatalf("TriggerCheck(busy) error = %v, want ErrCheckRunning", err)
+	}
+
+	want := "run:api pause:api resume:api"
+	if got := strings.Join(controller.actions, " "); got != want {
+		t.Fatalf("actions = %q, want %q", got, want)
+	}
+}
+
+func TestClientReload(t *testing.T) {
+	var fail bool
+	reloader := ReloaderFunc(func() (scheduler.ReconcileResult, error) {
+		if fail {
+			return scheduler.ReconcileResult{}, errors.New("checks.yaml: invalid schedule")
+		}
+		return scheduler.ReconcileResult{Added: []string{"new"}, Removed: []string{"old"}}, nil
+	})
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithReloader(reloader)))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "new" || len(result.Removed) != 1 {
+		t.Fatalf("Reload() = %+v, want added [new] and removed [old]", result)
+	}
+
+	fail = true
+	_, err = client.Reload(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid schedule") {
+		t.Fatalf("Reload() error = %v, want invalid schedule", err)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("foghorn_scheduler_checks 1\n"))
+	})
+	server := httptest.NewServer(NewHandler(func() scheduler.Snapshot {
+		return scheduler.Snapshot{}
+	}, WithMetrics(metrics)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + MetricsPath)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+}
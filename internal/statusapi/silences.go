@@ -0,0 +1,102 @@
+package statusapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const SilencesPath = "/v1/silences"
+
+// SilenceManager creates and removes ad-hoc silences.
+type SilenceManager interface {
+	AddSilence(silence scheduler.MaintenanceWindow) (scheduler.MaintenanceStatus, error)
+	RemoveSilence(name string) error
+	Silences() []scheduler.MaintenanceStatus
+}
+
+// WithSilences enables the /v1/silences endpoints.
+func WithSilences(manager SilenceManager) Option {
+	return func(o *handlerOptions) {
+		o.silences = manager
+	}
+}
+
+// SilenceRequest is the body of POST /v1/silences. The silence lasts for
+// Duration, or until ExpiresAt.
+type SilenceRequest struct {
+	Checks    []string  `json:"checks,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Mode      string    `json:"mode,omitempty"`
+	Comment   string    `json:"comment"`
+	Duration  string    `json:"duration,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+func (req SilenceRequest) window(now time.Time) (scheduler.MaintenanceWindow, error) {
+	window := scheduler.MaintenanceWindow{
+		Checks:  req.Checks,
+		Tags:    req.Tags,
+		Mode:    req.Mode,
+		Comment: req.Comment,
+		End:     req.ExpiresAt,
+	}
+	switch {
+	case req.Duration != "" && !req.ExpiresAt.IsZero():
+		return window, fmt.Errorf("use either duration or expires_at")
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			return window, fmt.Errorf("duration must be a positive duration")
+		}
+		window.End = now.Add(duration)
+	case req.ExpiresAt.IsZero():
+		return window, fmt.Errorf("duration or expires_at is required")
+	}
+	return window, nil
+}
+
+func silencesHandler(manager SilenceManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(manager.Silences())
+	}
+}
+
+func createSilenceHandler(manager SilenceManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req SilenceRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			http.Error(w, "invalid silence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		window, err := req.window(time.Now())
+		if err != nil {
+			http.Error(w, "invalid silence: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		silence, err := manager.AddSilence(window)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(silence)
+	}
+}
+
+func deleteSilenceHandler(manager SilenceManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := manager.RemoveSilence(r.PathValue("name")); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
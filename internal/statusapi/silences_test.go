@@ -0,0 +1,91 @@
+package statusapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type stubCheckConfig struct{ name string }
+
+func (c stubCheckConfig) GetName() string     { return c.name }
+func (c stubCheckConfig) GetSchedule() string { return "*/5 * * * *" }
+func (c stubCheckConfig) IsEnabled() bool     { return true }
+
+type idleExecutor struct{}
+
+func (idleExecutor) Execute(scheduler.CheckConfig) error             { return nil }
+func (idleExecutor) SetResultCallback(func(result scheduler.Result)) {}
+
+func TestSilenceEndpoints(t *testing.T) {
+	sched := scheduler.NewScheduler(idleExecutor{}, time.UTC, 0)
+	if err := sched.AddCheck(stubCheckConfig{name: "api"}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	server := httptest.NewServer(NewHandler(sched.Snapshot, WithSilences(sched)))
+	defer server.Close()
+
+	post := func(body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(server.URL+SilencesPath, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		return resp
+	}
+
+	for _, body := range []string{
+		`{"checks":["api"],"comment":"deploy"}`,
+		`{"checks":["api"],"duration":"1h"}`,
+		`{"checks":["missing"],"comment":"deploy","duration":"1h"}`,
+		`{"checks":["api"],"comment":"deploy","duration":"1h","expires_at":"2030-01-01T00:00:00Z"}`,
+		`{"checks":["api"],"comment":"deploy","duration":"1h","owner":"ops"}`,
+	} {
+		resp := post(body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("POST %s = %d, want 400", body, resp.StatusCode)
+		}
+	}
+
+	resp := post(`{"checks":["api"],"comment":"deploy 1.4","duration":"2h"}`)
+	var silence scheduler.MaintenanceStatus
+	if err := json.NewDecoder(resp.Body).Decode(&silence); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || silence.Name != "silence-1" || !silence.Active {
+		t.Fatalf("POST = %d %+v", resp.StatusCode, silence)
+	}
+	if sched.Snapshot().Checks["api"].Maintenance != "silence-1" {
+		t.Errorf("snapshot does not show the silence")
+	}
+
+	resp, err := http.Get(server.URL + SilencesPath)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	var silences []scheduler.MaintenanceStatus
+	_ = json.NewDecoder(resp.Body).Decode(&silences)
+	resp.Body.Close()
+	if len(silences) != 1 || silences[0].Comment != "deploy 1.4" {
+		t.Fatalf("GET = %+v", silences)
+	}
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req, _ := http.NewRequest(http.MethodDelete, server.URL+SilencesPath+"/silence-1", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("DELETE = %d, want %d", resp.StatusCode, want)
+		}
+	}
+}
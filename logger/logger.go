@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -39,7 +40,8 @@ type Logger struct {
 	output  io.Writer
 }
 
-var global *Logger
+// global is read by every goroutine that logs, so it is swapped atomically.
+var global atomic.Pointer[Logger]
 
 func New(level LogLevel, verbose bool) *Logger {
 	return &Logger{
@@ -50,14 +52,15 @@ func New(level LogLevel, verbose bool) *Logger {
 }
 
 func SetGlobal(l *Logger) {
-	global = l
+	global.Store(l)
 }
 
 func GetGlobal() *Logger {
-	if global == nil {
-		global = New(LevelInfo, false)
+	if l := global.Load(); l != nil {
+		return l
 	}
-	return global
+	global.CompareAndSwap(nil, New(LevelInfo, false))
+	return global.Load()
 }
 
 func (l *Logger) GetLevel() LogLevel {
@@ -73,26 +76,26 @@ func (l *Logger) IsVerbose() bool {
 }
 
 func SetLevel(level LogLevel) {
-	if global != nil {
-		global.mu.Lock()
-		global.level = level
-		global.mu.Unlock()
+	if l := global.Load(); l != nil {
+		l.mu.Lock()
+		l.level = level
+		l.mu.Unlock()
 	}
 }
 
 func SetVerbose(verbose bool) {
-	if global != nil {
-		global.mu.Lock()
-		global.verbose = verbose
-		global.mu.Unlock()
+	if l := global.Load(); l != nil {
+		l.mu.Lock()
+		l.verbose = verbose
+		l.mu.Unlock()
 	}
 }
 
 func SetOutput(w io.Writer) {
-	if global != nil {
-		global.mu.Lock()
-		global.output = w
-		global.mu.Unlock()
+	if l := global.Load(); l != nil {
+		l.mu.Lock()
+		l.output = w
+		l.mu.Unlock()
 	}
 }
 
@@ -121,13 +124,12 @@ func SetLevelString(levelStr string) error {
 }
 
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if level > l.level {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
 	timestamp := ""
 	if l.verbose {
 		timestamp = time.Now().UTC().Format("2006-01-02T15:04:05Z ")[:20] + " "
@@ -0,0 +1,258 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var checkStatuses = []string{"pass", "warn", "fail", "error", "suppressed", "maintenance", "unknown"}
+
+type ExecutorStats struct {
+	ImagePulls        uint64
+	ImagePullFailures uint64
+	ContainerErrors   map[string]uint64
+}
+
+type Option func(*Collector)
+
+func WithExecutorStats(statsFn func() ExecutorStats) Option {
+	return func(c *Collector) {
+		c.executorStats = statsFn
+	}
+}
+
+// WithDataGauges exports numeric fields of the last check result data as
+// foghorn_check_data gauges.
+func WithDataGauges(enabled bool) Option {
+	return func(c *Collector) {
+		c.exportData = enabled
+	}
+}
+
+// Collector renders the scheduler state in the Prometheus text exposition
+// format on every scrape.
+type Collector struct {
+	snapshot      func() scheduler.Snapshot
+	executorStats func() ExecutorStats
+	exportData    bool
+	now           func() time.Time
+}
+
+func NewCollector(snapshotFn func() scheduler.Snapshot, opts ...Option) *Collector {
+	c := &Collector{
+		snapshot: snapshotFn,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(c.Render())
+}
+
+func (c *Collector) Render() []byte {
+	snapshot := c.snapshot()
+	now := c.now()
+	out := &writer{}
+
+	names := make([]string, 0, len(snapshot.Checks))
+	for name := range snapshot.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out.family("foghorn_check_status", "gauge", "Last status of the check (1 for the current status).")
+	for _, name := range names {
+		current := snapshot.Checks[name].LastStatus
+		for _, status := range checkStatuses {
+			out.sample("foghorn_check_status", boolValue(current == status), "check", name, "status", status)
+		}
+	}
+
+	out.family("foghorn_check_last_duration_seconds", "gauge", "Duration of the last check run.")
+	for _, name := range names {
+		out.sample("foghorn_check_last_duration_seconds", float64(snapshot.Checks[name].LastDurationMs)/1000, "check", name)
+	}
+
+	out.family("foghorn_check_runs_total", "counter", "Check runs since the daemon started by resulting status.")
+	for _, name := range names {
+		counts := snapshot.Checks[name].RunCounts
+		statuses := make([]string, 0, len(counts))
+		for status := range counts {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			out.sample("foghorn_check_runs_total", float64(counts[status]), "check", name, "status", status)
+		}
+	}
+
+	out.family("foghorn_check_consecutive_failures", "gauge", "Number of consecutive fail or error results.")
+	for _, name := range names {
+		out.sample("foghorn_check_consecutive_failures", float64(snapshot.Checks[name].ConsecutiveFailures), "check", name)
+	}
+
+	out.family("foghorn_check_last_success_timestamp_seconds", "gauge", "Unix time of the last passing run.")
+	for _, name := range names {
+		if lastSuccess := snapshot.Checks[name].LastSuccess; lastSuccess != nil {
+			out.sample("foghorn_check_last_success_timestamp_seconds", float64(lastSuccess.UnixMilli())/1000, "check", name)
+		}
+	}
+
+	out.family("foghorn_check_seconds_since_last_success", "gauge", "Seconds since the last passing run.")
+	for _, name := range names {
+		if lastSuccess := snapshot.Checks[name].LastSuccess; lastSuccess != nil {
+			out.sample("foghorn_check_seconds_since_last_success", math.Max(0, now.Sub(*lastSuccess).Seconds()), "check", name)
+		}
+	}
+
+	out.family("foghorn_check_paused", "gauge", "Whether scheduling of the check is paused.")
+	for _, name := range names {
+		out.sample("foghorn_check_paused", boolValue(snapshot.Checks[name].Paused), "check", name)
+	}
+
+	if c.exportData {
+		out.family("foghorn_check_data", "gauge", "Numeric fields reported in the data of the last check result.")
+		for _, name := range names {
+			result := snapshot.Checks[name].LastResult
+			if result == nil {
+				continue
+			}
+			fields := numericFields("", result.Data, nil)
+			sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+			for _, field := range fields {
+				out.sample("foghorn_check_data", field.value, "check", name, "field", field.name)
+			}
+		}
+	}
+
+	counts := snapshot.Counts
+	out.family("foghorn_scheduler_checks", "gauge", "Number of configured checks.")
+	out.sample("foghorn_scheduler_checks", float64(counts.Total))
+	out.family("foghorn_scheduler_running_checks", "gauge", "Number of checks currently running.")
+	out.sample("foghorn_scheduler_running_checks", float64(counts.Running))
+	out.family("foghorn_scheduler_queued_checks", "gauge", "Number of checks waiting for a concurrency slot.")
+	out.sample("foghorn_scheduler_queued_checks", float64(counts.Queued))
+	out.family("foghorn_scheduler_max_concurrent_checks", "gauge", "Configured max_concurrent_checks (0 means unlimited).")
+	out.sample("foghorn_scheduler_max_concurrent_checks", float64(counts.MaxConcurrent))
+	if counts.MaxConcurrent > 0 {
+		out.family("foghorn_scheduler_saturation_ratio", "gauge", "Running checks divided by max_concurrent_checks.")
+		out.sample("foghorn_scheduler_saturation_ratio", float64(counts.Running)/float64(counts.MaxConcurrent))
+	}
+
+	if c.executorStats != nil {
+		stats := c.executorStats()
+		out.family("foghorn_executor_image_pulls_total", "counter", "Successful image pulls.")
+		out.sample("foghorn_executor_image_pulls_total", float64(stats.ImagePulls))
+		out.family("foghorn_executor_image_pull_failures_total", "counter", "Failed image pulls.")
+		out.sample("foghorn_executor_image_pull_failures_total", float64(stats.ImagePullFailures))
+		out.family("foghorn_executor_container_errors_total", "counter", "Container errors by reason.")
+		reasons := make([]string, 0, len(stats.ContainerErrors))
+		for reason := range stats.ContainerErrors {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			out.sample("foghorn_executor_container_errors_total", float64(stats.ContainerErrors[reason]), "reason", reason)
+		}
+	}
+
+	return out.buf.Bytes()
+}
+
+type numericField struct {
+	name  string
+	value float64
+}
+
+// numericFields flattens nested data maps into dotted field names and keeps
+// the numeric values.
+func numericFields(prefix string, data map[string]interface{}, out []numericField) []numericField {
+	for key, value := range data {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case float64:
+			out = append(out, numericField{name: name, value: v})
+		case int:
+			out = append(out, numericField{name: name, value: float64(v)})
+		case int64:
+			out = append(out, numericField{name: name, value: float64(v)})
+		case map[string]interface{}:
+			out = numericFields(name, v, out)
+		}
+	}
+	return out
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) family(name, typ, help string) {
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (w *writer) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(formatValue(value))
+	w.buf.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+func testSnapshot(now time.Time) scheduler.Snapshot {
+	lastSuccess := now.Add(-90 * time.Second)
+	return scheduler.Snapshot{
+		Counts: scheduler.SnapshotCounts{Total: 2, Running: 1, Queued: 3, MaxConcurrent: 4},
+		Checks: map[string]scheduler.CheckStatus{
+			"api": {
+				Name:                "api",
+				LastStatus:          "fail",
+				LastDurationMs:      1500,
+				LastSuccess:         &lastSuccess,
+				ConsecutiveFailures: 2,
+				RunCounts:           map[string]int64{"pass": 5, "fail": 2},
+				LastResult: &scheduler.Result{
+					Data: map[string]interface{}{
+						"latency_ms": float64(120),
+						"code":       "200",
+						"tls":        map[string]interface{}{"days_left": float64(9)},
+					},
+				},
+			},
+			"disk \"root\"": {
+				Name:       "disk \"root\"",
+				LastStatus: "unknown",
+				Paused:     true,
+			},
+		},
+	}
+}
+
+func TestCollectorRender(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	collector := NewCollector(func() scheduler.Snapshot { return testSnapshot(now) },
+		WithExecutorStats(func() ExecutorStats {
+			return ExecutorStats{ImagePulls: 3, ImagePullFailures: 1, ContainerErrors: map[string]uint64{"timeout": 2}}
+		}),
+	)
+	collector.now = func() time.Time { return now }
+
+	output := string(collector.Render())
+	for _, want := range []string{
+		"# TYPE foghorn_check_status gauge",
+		`foghorn_check_status{check="api",status="fail"} 1`,
+		`foghorn_check_status{check="api",status="pass"} 0`,
+		`foghorn_check_last_duration_seconds{check="api"} 1.5`,
+		`foghorn_check_runs_total{check="api",status="fail"} 2`,
+		`foghorn_check_runs_total{check="api",status="pass"} 5`,
+		`foghorn_check_consecutive_failures{check="api"} 2`,
+		`foghorn_check_seconds_since_last_success{check="api"} 90`,
+		`foghorn_check_paused{check="disk \"root\""} 1`,
+		"foghorn_scheduler_running_checks 1",
+		"foghorn_scheduler_queued_checks 3",
+		"foghorn_scheduler_max_concurrent_checks 4",
+		"foghorn_scheduler_saturation_ratio 0.25",
+		"foghorn_executor_image_pulls_total 3",
+		"foghorn_executor_image_pull_failures_total 1",
+		`foghorn_executor_container_errors_total{reason="timeout"} 2`,
+	} {
+		if !strings.Contains(output, want+"\n") {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(output, "foghorn_check_data") {
+		t.Errorf("data gauges should be disabled by default")
+	}
+	if strings.Contains(output, `foghorn_check_seconds_since_last_success{check="disk`) {
+		t.Errorf("checks without a success should not export seconds since last success")
+	}
+}
+
+func TestCollectorDataGauges(t *testing.T) {
+	now := time.Now()
+	collector := NewCollector(func() scheduler.Snapshot { return testSnapshot(now) }, WithDataGauges(true))
+
+	output := string(collector.Render())
+	for _, want := range []string{
+		`foghorn_check_data{check="api",field="latency_ms"} 120`,
+		`foghorn_check_data{check="api",field="tls.days_left"} 9`,
+	} {
+		if !strings.Contains(output, want+"\n") {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(output, `field="code"`) {
+		t.Errorf("non-numeric data fields should not be exported")
+	}
+}
+
+func TestCollectorServeHTTP(t *testing.T) {
+	collector := NewCollector(func() scheduler.Snapshot { return scheduler.Snapshot{} })
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if strings.Contains(rec.Body.String(), "saturation_ratio") {
+		t.Fatalf("saturation should not be exported without a concurrency limit")
+	}
+
+	rec = httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+}
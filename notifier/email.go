@@ -0,0 +1,198 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+)
+
+const (
+	defaultSMTPPort     = 587
+	implicitTLSSMTPPort = 465
+	defaultSubject      = "[foghorn] {{.Check}} is {{.Status}}"
+	defaultEmailBody    = "Check {{.Check}} changed from {{.PreviousStatus}} to {{.Status}} at {{.CompletedAt.Format \"2006-01-02 15:04:05 MST\"}}.\n"
+)
+
+type emailNotifier struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+	subject  *template.Template
+	body     *template.Template
+	secrets  secretValues
+}
+
+func newEmailNotifier(cfg config.NotifierConfig, secrets secretValues) (*emailNotifier, error) {
+	if cfg.SMTPHost == "" {
+		return nil, fmt.Errorf("smtp_host is required")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("from and to are required")
+	}
+	subjectText := cfg.Subject
+	if subjectText == "" {
+		subjectText = defaultSubject
+	}
+	subject, err := parseTemplate("subject", subjectText)
+	if err != nil {
+		return nil, err
+	}
+	bodyText := cfg.Template
+	if bodyText == "" {
+		bodyText = defaultEmailBody
+	}
+	body, err := parseTemplate("template", bodyText)
+	if err != nil {
+		return nil, err
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	return &emailNotifier{
+		host:     cfg.SMTPHost,
+		port:     port,
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     cfg.From,
+		to:       cfg.To,
+		subject:  subject,
+		body:     body,
+		secrets:  secrets,
+	}, nil
+}
+
+func (e *emailNotifier) Notify(ctx context.Context, event Event) error {
+	msg, err := e.buildMessage(event, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.username != "" {
+		username, err := e.secrets.resolve(e.username)
+		if err != nil {
+			return fmt.Errorf("username: %w", err)
+		}
+		password, err := e.secrets.resolve(e.password)
+		if err != nil {
+			return fmt.Errorf("password: %w", err)
+		}
+		auth = smtp.PlainAuth("", username, password, e.host)
+	}
+
+	if err := e.send(ctx, auth, msg); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// send delivers a message over a connection bound to ctx. The connection
+// takes the deadline of ctx and is closed when ctx is cancelled, so a hung
+// server cannot hold the notification past its timeout.
+func (e *emailNotifier) send(ctx context.Context, auth smtp.Auth, msg []byte) error {
+	addr := net.JoinHostPort(e.host, strconv.Itoa(e.port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+	}
+	if e.port == implicitTLSSMTPPort {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: e.host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	client, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+	if e.port != implicitTLSSMTPPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: e.host}); err != nil {
+				return fmt.Errorf("failed to start TLS: %w", err)
+			}
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("server does not support authentication")
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(e.from); err != nil {
+		return err
+	}
+	for _, rcpt := range e.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (e *emailNotifier) buildMessage(event Event, now time.Time) ([]byte, error) {
+	subject, err := render(e.subject, event, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render(e.body, event, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", e.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", sanitizeHeader(string(subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+func sanitizeHeader(value string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", " ", "\n", " ").Replace(value))
+}
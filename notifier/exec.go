@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/pfarrer/foghorn/config"
+)
+
+type execNotifier struct {
+	command  []string
+	env      map[string]string
+	template *template.Template
+	secrets  secretValues
+}
+
+func newExecNotifier(cfg config.NotifierConfig, secrets secretValues) (*execNotifier, error) {
+	if len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("command is required")
+	}
+	tmpl, err := parseTemplate("template", cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+	return &execNotifier{
+		command:  cfg.Command,
+		env:      cfg.Env,
+		template: tmpl,
+		secrets:  secrets,
+	}, nil
+}
+
+func (e *execNotifier) Notify(ctx context.Context, event Event) error {
+	input, err := render(e.template, event, eventJSON)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, e.command[0], e.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"FOGHORN_ALERT_CHECK="+event.Check,
+		"FOGHORN_ALERT_STATUS="+event.Status,
+		"FOGHORN_ALERT_PREVIOUS_STATUS="+event.PreviousStatus,
+		"FOGHORN_ALERT_TAGS="+strings.Join(event.Tags, ","),
+	)
+	for name, value := range e.env {
+		resolved, err := e.secrets.resolve(value)
+		if err != nil {
+			return fmt.Errorf("env %s: %w", name, err)
+		}
+		cmd.Env = append(cmd.Env, name+"="+resolved)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("command failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
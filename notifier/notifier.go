@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/logger"
+	"github.com/pfarrer/foghorn/scheduler"
+	"github.com/pfarrer/foghorn/secretstore"
+)
+
+const defaultTimeout = 10 * time.Second
+
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+type Event struct {
+	Check          string    `json:"check"`
+	Tags           []string  `json:"tags,omitempty"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Message        string    `json:"message,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	CompletedAt    time.Time `json:"completed_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+type route struct {
+	name     string
+	notifier Notifier
+	tags     []string
+	statuses []string
+	timeout  time.Duration
+}
+
+type Dispatcher struct {
+	mu     sync.RWMutex
+	routes []route
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(configs []config.NotifierConfig, resolver SecretResolver) (*Dispatcher, error) {
+	d := &Dispatcher{}
+	if err := d.Reconfigure(configs, resolver); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reconfigure replaces the notifier routes. Notifications already in flight
+// are not affected. On error the previous routes stay in place.
+func (d *Dispatcher) Reconfigure(configs []config.NotifierConfig, resolver SecretResolver) error {
+	routes, err := buildRoutes(configs, resolver)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.routes = routes
+	d.mu.Unlock()
+	return nil
+}
+
+func buildRoutes(configs []config.NotifierConfig, resolver SecretResolver) ([]route, error) {
+	routes := make([]route, 0, len(configs))
+	for _, cfg := range configs {
+		n, err := newNotifier(cfg, resolver)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %s: %w", cfg.Name, err)
+		}
+		timeout := defaultTimeout
+		if cfg.Timeout != "" {
+			parsed, err := time.ParseDuration(cfg.Timeout)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("notifier %s: timeout must be a positive duration", cfg.Name)
+			}
+			timeout = parsed
+		}
+		routes = append(routes, route{
+			name:     cfg.Name,
+			notifier: n,
+			tags:     cfg.Tags,
+			statuses: cfg.Statuses,
+			timeout:  timeout,
+		})
+	}
+	return routes, nil
+}
+
+func newNotifier(cfg config.NotifierConfig, resolver SecretResolver) (Notifier, error) {
+	secrets := secretValues{resolver: resolver}
+	switch cfg.Type {
+	case "webhook":
+		return newWebhookNotifier(cfg, secrets)
+	case "email":
+		return newEmailNotifier(cfg, secrets)
+	case "exec":
+		return newExecNotifier(cfg, secrets)
+	default:
+		return nil, fmt.Errorf("unsupported notifier type %q", cfg.Type)
+	}
+}
+
+func (d *Dispatcher) HandleTransition(transition scheduler.StatusTransition) {
+	event := Event{
+		Check:          transition.CheckName,
+		Tags:           transition.Tags,
+		Status:         transition.Status,
+		PreviousStatus: transition.PreviousStatus,
+		Message:        transition.Message,
+		DurationMs:     transition.Duration.Milliseconds(),
+		CompletedAt:    transition.CompletedAt,
+	}
+	d.Dispatch(event)
+}
+
+func (d *Dispatcher) Dispatch(event Event) {
+	d.mu.RLock()
+	routes := d.routes
+	d.mu.RUnlock()
+
+	for _, r := range routes {
+		if !r.matches(event) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(r route) {
+			defer d.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+			defer cancel()
+			if err := r.notifier.Notify(ctx, event); err != nil {
+				logger.Error("Notifier %s: failed to send notification for %s: %v", r.name, event.Check, err)
+				return
+			}
+			logger.Debug("Notifier %s: sent notification for %s (%s -> %s)", r.name, event.Check, event.PreviousStatus, event.Status)
+		}(r)
+	}
+}
+
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (r route) matches(event Event) bool {
+	if len(r.statuses) > 0 && !slices.Contains(r.statuses, event.Status) {
+		return false
+	}
+	if len(r.tags) == 0 {
+		return true
+	}
+	for _, tag := range event.Tags {
+		if slices.Contains(r.tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+type secretValues struct {
+	resolver SecretResolver
+}
+
+func (s secretValues) resolve(value string) (string, error) {
+	refKey, ok := secretstore.ParseRef(value)
+	if !ok {
+		return value, nil
+	}
+	if s.resolver == nil {
+		return "", fmt.Errorf("secret %q is referenced, but secret store is not configured", refKey)
+	}
+	return s.resolver.Resolve(value)
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func render(tmpl *template.Template, event Event, fallback func(Event) ([]byte, error)) ([]byte, error) {
+	if tmpl == nil {
+		return fallback(event)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return buf.Bytes(), nil
+}
+
+func eventJSON(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
@@ -0,0 +1,319 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type mapResolver map[string]string
+
+func (m mapResolver) Resolve(ref string) (string, error) {
+	value, ok := m[ref]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", ref)
+	}
+	return value, nil
+}
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingNotifier) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestDispatcherRoutesByTagsAndStatuses(t *testing.T) {
+	all := &recordingNotifier{}
+	prod := &recordingNotifier{}
+	failures := &recordingNotifier{}
+	d := &Dispatcher{routes: []route{
+		{name: "all", notifier: all, timeout: time.Second},
+		{name: "prod", notifier: prod, tags: []string{"prod"}, timeout: time.Second},
+		{name: "failures", notifier: failures, statuses: []string{"fail", "error"}, timeout: time.Second},
+	}}
+
+	d.HandleTransition(scheduler.StatusTransition{CheckName: "api", Tags: []string{"prod", "http"}, PreviousStatus: "pass", Status: "fail"})
+	d.HandleTransition(scheduler.StatusTransition{CheckName: "disk", Tags: []string{"staging"}, PreviousStatus: "fail", Status: "pass"})
+	d.Wait()
+
+	if all.count() != 2 {
+		t.Fatalf("untagged notifier received %d events, want 2", all.count())
+	}
+	if prod.count() != 1 || prod.events[0].Check != "api" {
+		t.Fatalf("prod notifier events = %+v, want only api", prod.events)
+	}
+	if failures.count() != 1 || failures.events[0].Status != "fail" {
+		t.Fatalf("failure notifier events = %+v, want only the fail transition", failures.events)
+	}
+}
+
+func TestWebhookNotifierPostsTemplateWithSecretHeader(t *testing.T) {
+	var (
+		gotBody   string
+		gotAuth   string
+		gotMethod string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotAuth = r.Header.Get("Authorization")
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher([]config.NotifierConfig{{
+		Name:     "hook",
+		Type:     "webhook",
+		URL:      server.URL,
+		Headers:  map[string]string{"Authorization": "secret://alerts/token"},
+		Template: `{"text":"{{.Check}} {{.PreviousStatus}} -> {{.Status}}"}`,
+	}}, mapResolver{"secret://alerts/token": "Bearer abc"})
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+
+	d.Dispatch(Event{Check: "api", PreviousStatus: "pass", Status: "fail"})
+	d.Wait()
+
+	if gotMethod != http.MethodPost {
+		t.Fatalf("method = %s, want POST", gotMethod)
+	}
+	if gotBody != `{"text":"api pass -> fail"}` {
+		t.Fatalf("body = %q", gotBody)
+	}
+	if gotAuth != "Bearer abc" {
+		t.Fatalf("Authorization = %q, want resolved secret", gotAuth)
+	}
+}
+
+func TestWebhookNotifierDefaultPayload(t *testing.T) {
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	n, err := newWebhookNotifier(config.NotifierConfig{Name: "hook", Type: "webhook", URL: server.URL}, secretValues{})
+	if err != nil {
+		t.Fatalf("newWebhookNotifier() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Event{Check: "api", Status: "warn", PreviousStatus: "pass"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got.Check != "api" || got.Status != "warn" {
+		t.Fatalf("payload = %+v", got)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	n, err := newWebhookNotifier(config.NotifierConfig{Name: "hook", Type: "webhook", URL: server.URL}, secretValues{})
+	if err != nil {
+		t.Fatalf("newWebhookNotifier() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Event{Check: "api"}); err == nil {
+		t.Fatalf("Notify() should fail on non-2xx response")
+	}
+}
+
+func TestWebhookNotifierMissingSecretStore(t *testing.T) {
+	n, err := newWebhookNotifier(config.NotifierConfig{
+		Name:    "hook",
+		Type:    "webhook",
+		URL:     "http://127.0.0.1:1",
+		Headers: map[string]string{"Authorization": "secret://alerts/token"},
+	}, secretValues{})
+	if err != nil {
+		t.Fatalf("newWebhookNotifier() error = %v", err)
+	}
+	err = n.Notify(context.Background(), Event{Check: "api"})
+	if err == nil || !strings.Contains(err.Error(), "secret store is not configured") {
+		t.Fatalf("Notify() error = %v, want missing secret store error", err)
+	}
+}
+
+func TestExecNotifierPassesEventOnStdinAndEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alert.txt")
+	n, err := newExecNotifier(config.NotifierConfig{
+		Name:     "script",
+		Type:     "exec",
+		Command:  []string{"sh", "-c", `printf '%s|%s|' "$FOGHORN_ALERT_CHECK" "$TOKEN" > "$OUT"; cat >> "$OUT"`},
+		Env:      map[string]string{"OUT": out, "TOKEN": "secret://alerts/token"},
+		Template: "{{.Check}} is {{.Status}}",
+	}, secretValues{resolver: mapResolver{"secret://alerts/token": "s3cret"}})
+	if err != nil {
+		t.Fatalf("newExecNotifier() error = %v", err)
+	}
+
+	if err := n.Notify(context.Background(), Event{Check: "disk", Status: "fail"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if string(data) != "disk|s3cret|disk is fail" {
+		t.Fatalf("output = %q", string(data))
+	}
+}
+
+func TestExecNotifierReportsFailure(t *testing.T) {
+	n, err := newExecNotifier(config.NotifierConfig{
+		Name:    "script",
+		Type:    "exec",
+		Command: []string{"sh", "-c", "echo boom >&2; exit 3"},
+	}, secretValues{})
+	if err != nil {
+		t.Fatalf("newExecNotifier() error = %v", err)
+	}
+	err = n.Notify(context.Background(), Event{Check: "disk"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Notify() error = %v, want stderr in error", err)
+	}
+}
+
+func TestEmailNotifierBuildMessage(t *testing.T) {
+	n, err := newEmailNotifier(config.NotifierConfig{
+		Name:     "mail",
+		Type:     "email",
+		SMTPHost: "smtp.example.com",
+		From:     "foghorn@example.com",
+		To:       []string{"ops@example.com", "oncall@example.com"},
+	}, secretValues{})
+	if err != nil {
+		t.Fatalf("newEmailNotifier() error = %v", err)
+	}
+	if n.port != defaultSMTPPort {
+		t.Fatalf("port = %d, want %d", n.port, defaultSMTPPort)
+	}
+
+	completed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg, err := n.buildMessage(Event{Check: "tls", PreviousStatus: "pass", Status: "warn", CompletedAt: completed}, completed)
+	if err != nil {
+		t.Fatalf("buildMessage() error = %v", err)
+	}
+	text := string(msg)
+	for _, want := range []string{
+		"To: ops@example.com, oncall@example.com\r\n",
+		"Subject: [foghorn] tls is warn\r\n",
+		"Check tls changed from pass to warn at 2025-01-02 03:04:05 UTC.\r\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("message missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestEmailNotifierHungServerRespectsContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	closed := make(chan struct{})
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Never send the greeting; the read only returns once the client
+		// closes its end.
+		_, _ = io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	n, err := newEmailNotifier(config.NotifierConfig{
+		Name:     "mail",
+		Type:     "email",
+		SMTPHost: "127.0.0.1",
+		SMTPPort: addr.Port,
+		From:     "foghorn@example.com",
+		To:       []string{"ops@example.com"},
+	}, secretValues{})
+	if err != nil {
+		t.Fatalf("newEmailNotifier() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = n.Notify(ctx, Event{Check: "api", Status: "fail", CompletedAt: start})
+	var netErr net.Error
+	if !errors.Is(err, context.DeadlineExceeded) && !(errors.As(err, &netErr) && netErr.Timeout()) {
+		t.Fatalf("Notify() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Notify() took %v", elapsed)
+	}
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("connection left open after the deadline")
+	}
+}
+
+func TestNewDispatcherRejectsInvalidTemplate(t *testing.T) {
+	_, err := NewDispatcher([]config.NotifierConfig{{
+		Name:     "hook",
+		Type:     "webhook",
+		URL:      "http://example.com",
+		Template: "{{.Check",
+	}}, nil)
+	if err == nil {
+		t.Fatalf("NewDispatcher() should reject invalid template")
+	}
+}
+
+func TestDispatcherReconfigureKeepsRoutesOnError(t *testing.T) {
+	d, err := NewDispatcher([]config.NotifierConfig{{Name: "hook", Type: "webhook", URL: "http://example.com"}}, nil)
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+
+	if err := d.Reconfigure([]config.NotifierConfig{{Name: "bad", Type: "webhook", URL: "http://example.com", Template: "{{.Check"}}, nil); err == nil {
+		t.Fatalf("Reconfigure() should reject invalid template")
+	}
+	if len(d.routes) != 1 || d.routes[0].name != "hook" {
+		t.Fatalf("routes = %+v, want previous hook route", d.routes)
+	}
+
+	if err := d.Reconfigure(nil, nil); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if len(d.routes) != 0 {
+		t.Fatalf("routes = %d, want 0", len(d.routes))
+	}
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/pfarrer/foghorn/config"
+)
+
+type webhookNotifier struct {
+	url      string
+	method   string
+	headers  map[string]string
+	template *template.Template
+	secrets  secretValues
+	client   *http.Client
+}
+
+func newWebhookNotifier(cfg config.NotifierConfig, secrets secretValues) (*webhookNotifier, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	tmpl, err := parseTemplate("template", cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+	method := strings.ToUpper(strings.TrimSpace(cfg.Method))
+	if method == "" {
+		method = http.MethodPost
+	}
+	return &webhookNotifier{
+		url:      cfg.URL,
+		method:   method,
+		headers:  cfg.Headers,
+		template: tmpl,
+		secrets:  secrets,
+		client:   &http.Client{},
+	}, nil
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := render(w.template, event, eventJSON)
+	if err != nil {
+		return err
+	}
+	url, err := w.secrets.resolve(w.url)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, w.method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "foghorn")
+	for name, value := range w.headers {
+		resolved, err := w.secrets.resolve(value)
+		if err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+		req.Header.Set(name, resolved)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package scheduler
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/pfarrer/foghorn/config"
 )
 
@@ -9,6 +12,8 @@ type ScheduleType string
 const (
 	ScheduleTypeCron     ScheduleType = "cron"
 	ScheduleTypeInterval ScheduleType = "interval"
+	// ScheduleTypeWindowed checks only run in their schedule windows.
+	ScheduleTypeWindowed ScheduleType = "windowed"
 )
 
 type ConfigAdapter struct {
@@ -34,6 +39,9 @@ func (a *ConfigAdapter) GetScheduleType() ScheduleType {
 	if a.Config.Schedule.Cron != "" {
 		return ScheduleTypeCron
 	}
+	if a.Config.Schedule.Interval == "" && len(a.Config.Schedule.Windows) > 0 {
+		return ScheduleTypeWindowed
+	}
 	return ScheduleTypeInterval
 }
 
@@ -44,3 +52,132 @@ func (a *ConfigAdapter) GetInterval() string {
 func (a *ConfigAdapter) IsEnabled() bool {
 	return a.Config.Enabled
 }
+
+func (a *ConfigAdapter) GetTags() []string {
+	return a.Config.Tags
+}
+
+func (a *ConfigAdapter) GetDescription() string {
+	return a.Config.Description
+}
+
+func (a *ConfigAdapter) GetImage() string {
+	return a.Config.Image
+}
+
+func (a *ConfigAdapter) GetRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Retries:           a.Config.Retries,
+		Delay:             a.Config.RetryDelayDuration(),
+		MaxDelay:          a.Config.RetryMaxDelayDuration(),
+		Exponential:       a.Config.Backoff == config.BackoffExponential,
+		FailureThreshold:  a.Config.FailureThreshold,
+		RecoveryThreshold: a.Config.RecoveryThreshold,
+	}
+}
+
+func (a *ConfigAdapter) GetDependencies() []string {
+	return a.Config.DependsOn
+}
+
+// NewMaintenanceWindows converts the configured maintenance windows. Fixed
+// windows must already be valid, as config.Load ensures.
+func NewMaintenanceWindows(cfgs []config.MaintenanceWindowConfig) ([]MaintenanceWindow, error) {
+	windows := make([]MaintenanceWindow, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		window := MaintenanceWindow{
+			Name:    cfg.Name,
+			Checks:  cfg.Checks,
+			Tags:    cfg.Tags,
+			Mode:    cfg.Mode,
+			Comment: cfg.Comment,
+			Cron:    cfg.Cron,
+		}
+		if cfg.Cron != "" {
+			duration, err := time.ParseDuration(cfg.Duration)
+			if err != nil {
+				return nil, fmt.Errorf("maintenance window %s: invalid duration: %w", cfg.Name, err)
+			}
+			window.Duration = duration
+		} else {
+			start, end, err := cfg.Times()
+			if err != nil {
+				return nil, fmt.Errorf("maintenance window %s: %w", cfg.Name, err)
+			}
+			window.Start, window.End = start, end
+		}
+		if _, err := normalizeWindow(window); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func (a *ConfigAdapter) GetTimezone() string {
+	return a.Config.Schedule.Timezone
+}
+
+func (a *ConfigAdapter) GetSplay() time.Duration {
+	return a.Config.Schedule.SplayDuration()
+}
+
+func (a *ConfigAdapter) GetJitter() time.Duration {
+	return a.Config.Schedule.JitterDuration()
+}
+
+// GetScheduleWindows converts the schedule windows, which config.Load has
+// validated.
+func (a *ConfigAdapter) GetScheduleWindows() []ScheduleWindow {
+	windows := make([]ScheduleWindow, 0, len(a.Config.Schedule.Windows))
+	for _, w := range a.Config.Schedule.Windows {
+		days, _ := w.Weekdays()
+		start, end, _ := w.Hours()
+		windows = append(windows, ScheduleWindow{
+			Name:     w.Name,
+			Days:     days,
+			Start:    start,
+			End:      end,
+			Timezone: w.Timezone,
+			Interval: w.Interval,
+			Cron:     w.Cron,
+		})
+	}
+	return windows
+}
+
+func (a *ConfigAdapter) GetStartPolicy() StartPolicy {
+	return StartPolicy{OnStart: a.Config.Schedule.OnStart, CatchUp: a.Config.Schedule.CatchUp}
+}
+
+// ValidateSchedules parses the cron schedules of all checks and maintenance
+// windows, so a configuration with an invalid or never firing expression is
+// rejected before the daemon starts. Checks with schedule windows must run
+// at some point as well.
+func ValidateSchedules(cfg *config.Config) error {
+	location, err := config.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return err
+	}
+	validator := &Scheduler{location: location}
+	for i := range cfg.Checks {
+		check := &cfg.Checks[i]
+		if check.Schedule.Cron != "" {
+			if _, err := ParseCronExpression(check.Schedule.Cron); err != nil {
+				return fmt.Errorf("check %s: invalid cron: %w", check.Name, err)
+			}
+		}
+		if len(check.Schedule.Windows) == 0 {
+			continue
+		}
+		scheduled, err := validator.newScheduledCheck(NewConfigAdapter(check))
+		if err != nil {
+			return err
+		}
+		if next, _ := scheduled.nextWindowedRun(time.Time{}, time.Now()); next.IsZero() {
+			return fmt.Errorf("check %s: schedule never runs, its cron schedules do not fire within their windows", check.Name)
+		}
+	}
+	_, err = NewMaintenanceWindows(cfg.MaintenanceWindows)
+	return err
+}
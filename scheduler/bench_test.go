@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/logger"
+)
+
+var benchmarkExpressions = []string{
+	"* * * * *",
+	"*/5 9-17 * * MON-FRI",
+	"30 2 * * *",
+	"0 0 L * *",
+	"0 0 * * FRI#3",
+	"*/20 * * * * *",
+	"0 0 29 2 *",
+}
+
+func BenchmarkCronNext(b *testing.B) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		b.Fatalf("LoadLocation() error = %v", err)
+	}
+	for _, expr := range benchmarkExpressions {
+		b.Run(expr, func(b *testing.B) {
+			cron, err := ParseCronExpression(expr)
+			if err != nil {
+				b.Fatalf("ParseCronExpression() error = %v", err)
+			}
+			start := time.Date(2025, 3, 29, 12, 0, 0, 0, berlin)
+			for b.Loop() {
+				cron.Next(start)
+			}
+		})
+	}
+}
+
+// benchmarkScheduler returns a scheduler with n cron checks that are all
+// queued behind a concurrency limit of one.
+func benchmarkScheduler(b *testing.B, n int) *Scheduler {
+	b.Helper()
+	previous := logger.GetGlobal()
+	logger.SetGlobal(logger.New(logger.LevelError, false))
+	b.Cleanup(func() { logger.SetGlobal(previous) })
+
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 1)
+	for i := range n {
+		check := &MockCheckConfig{
+			name:     fmt.Sprintf("check-%05d", i),
+			schedule: benchmarkExpressions[i%len(benchmarkExpressions)],
+			enabled:  true,
+		}
+		if err := scheduler.AddCheck(check); err != nil {
+			b.Fatalf("AddCheck() error = %v", err)
+		}
+		scheduler.queue = append(scheduler.queue, check)
+	}
+	return scheduler
+}
+
+func BenchmarkSortQueue(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("checks=%d", n), func(b *testing.B) {
+			scheduler := benchmarkScheduler(b, n)
+			now := time.Now()
+			for b.Loop() {
+				scheduler.sortQueueLocked(now)
+			}
+		})
+	}
+}
+
+func BenchmarkScheduleNextRun(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("checks=%d", n), func(b *testing.B) {
+			scheduler := benchmarkScheduler(b, n)
+			now := time.Now()
+			for b.Loop() {
+				for _, check := range scheduler.checks {
+					scheduler.scheduleNextRunLocked(check, now)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pfarrer/foghorn/logger"
+)
+
+var (
+	ErrCheckNotFound   = errors.New("check not found")
+	ErrCheckRunning    = errors.New("check is already running or queued")
+	ErrCheckNotRunning = errors.New("check is not running")
+)
+
+// PauseRecorder is implemented by result loggers that also persist the
+// paused state of checks.
+type PauseRecorder interface {
+	RecordPause(checkName string, paused bool, at time.Time) error
+}
+
+// TriggerCheck runs a check now. The run goes through the normal concurrency
+// queue and also works for paused or disabled checks.
+func (s *Scheduler) TriggerCheck(name string) error {
+	check, exists := s.GetCheckStatus(name)
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+	}
+	if err := s.executeCheck(name, check); err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+	logger.Info("Manually triggered check: %s", name)
+	return nil
+}
+
+func (s *Scheduler) PauseCheck(name string) error {
+	return s.setPaused(name, true)
+}
+
+func (s *Scheduler) ResumeCheck(name string) error {
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	check, exists := s.checks[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+	}
+	if check.Paused == paused {
+		return nil
+	}
+	check.Paused = paused
+
+	if recorder, ok := s.resultLogger.(PauseRecorder); ok {
+		if err := recorder.RecordPause(name, paused, time.Now().In(s.location)); err != nil {
+			logger.Error("Failed to persist paused state for %s: %v", name, err)
+		}
+	}
+	if paused {
+		logger.Info("Paused check %s", name)
+	} else {
+		logger.Info("Resumed check %s", name)
+	}
+	return nil
+}
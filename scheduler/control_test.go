@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/events"
+)
+
+type recordingPauseLogger struct {
+	recordingResultLogger
+	pauses []bool
+}
+
+func (r *recordingPauseLogger) RecordPause(checkName string, paused bool, at time.Time) error {
+	r.pauses = append(r.pauses, paused)
+	return nil
+}
+
+func TestTriggerCheckRunsThroughQueue(t *testing.T) {
+	executor := &BlockingExecutor{
+		started: make(chan string, 2),
+		blocker: make(chan struct{}),
+	}
+	scheduler := NewScheduler(executor, time.UTC, 1)
+	defer close(executor.blocker)
+
+	for _, name := range []string{"first", "second"} {
+		if err := scheduler.AddCheck(&MockCheckConfig{name: name, schedule: "0 0 1 1 *", enabled: true}); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+
+	if err := scheduler.TriggerCheck("first"); err != nil {
+		t.Fatalf("TriggerCheck(first) error = %v", err)
+	}
+	select {
+	case name := <-executor.started:
+		if name != "first" {
+			t.Fatalf("started %s, want first", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("triggered check did not start")
+	}
+
+	if err := scheduler.TriggerCheck("first"); !errors.Is(err, ErrCheckRunning) {
+		t.Fatalf("TriggerCheck(first) while running error = %v, want ErrCheckRunning", err)
+	}
+
+	if err := scheduler.TriggerCheck("second"); err != nil {
+		t.Fatalf("TriggerCheck(second) error = %v", err)
+	}
+	check, _ := scheduler.GetCheckStatus("second")
+	scheduler.mu.RLock()
+	queued := check.IsQueued
+	scheduler.mu.RUnlock()
+	if !queued {
+		t.Fatalf("second should be queued behind the concurrency limit")
+	}
+
+	if err := scheduler.TriggerCheck("missing"); !errors.Is(err, ErrCheckNotFound) {
+		t.Fatalf("TriggerCheck(missing) error = %v, want ErrCheckNotFound", err)
+	}
+}
+
+func TestConcurrentTriggersStartOneRun(t *testing.T) {
+	executor := &BlockingExecutor{
+		started: make(chan string, 10),
+		blocker: make(chan struct{}),
+	}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	defer close(executor.blocker)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "0 0 1 1 *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- scheduler.TriggerCheck("api")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	started := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			started++
+		case !errors.Is(err, ErrCheckRunning):
+			t.Fatalf("TriggerCheck() error = %v", err)
+		}
+	}
+	if started != 1 || len(executor.started) > 1 {
+		t.Fatalf("%d triggers succeeded and %d runs started, want one", started, len(executor.started))
+	}
+}
+
+func TestPauseAndResumeCheck(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	resultLogger := &recordingPauseLogger{}
+	scheduler.SetResultLogger(resultLogger)
+
+	check := &IntervalMockCheckConfig{name: "api", enabled: true, interval: "1m"}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	if err := scheduler.PauseCheck("api"); err != nil {
+		t.Fatalf("PauseCheck() error = %v", err)
+	}
+	if err := scheduler.PauseCheck("api"); err != nil {
+		t.Fatalf("PauseCheck() twice error = %v", err)
+	}
+	if !scheduler.Snapshot().Checks["api"].Paused {
+		t.Fatalf("snapshot should report api as paused")
+	}
+
+	scheduler.tick()
+	time.Sleep(20 * time.Millisecond)
+	if executed := executor.Executed(); len(executed) != 0 {
+		t.Fatalf("paused check executed: %v", executed)
+	}
+
+	if err := scheduler.ResumeCheck("api"); err != nil {
+		t.Fatalf("ResumeCheck() error = %v", err)
+	}
+	scheduler.tick()
+	time.Sleep(20 * time.Millisecond)
+	if executed := executor.Executed(); len(executed) != 1 {
+		t.Fatalf("resumed check executed %d times, want 1", len(executed))
+	}
+
+	if len(resultLogger.pauses) != 2 || !resultLogger.pauses[0] || resultLogger.pauses[1] {
+		t.Fatalf("recorded pauses = %v, want [true false]", resultLogger.pauses)
+	}
+
+	if err := scheduler.PauseCheck("missing"); !errors.Is(err, ErrCheckNotFound) {
+		t.Fatalf("PauseCheck(missing) error = %v, want ErrCheckNotFound", err)
+	}
+}
+
+func TestApplyStateRestoresPause(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	scheduler.ApplyState(map[string]CheckState{"api": {Paused: true}})
+
+	check, _ := scheduler.GetCheckStatus("api")
+	if !check.Paused {
+		t.Fatalf("check should be paused after ApplyState")
+	}
+}
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (r *recordingPublisher) Publish(event events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingPublisher) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		types = append(types, event.Type+":"+event.Check)
+	}
+	return types
+}
+
+func TestSchedulerPublishesEvents(t *testing.T) {
+	executor := &BlockingExecutor{
+		started: make(chan string, 2),
+		blocker: make(chan struct{}),
+	}
+	scheduler := NewScheduler(executor, time.UTC, 1)
+	publisher := &recordingPublisher{}
+	scheduler.SetEventPublisher(publisher)
+
+	for _, name := range []string{"first", "second"} {
+		if err := scheduler.AddCheck(&MockCheckConfig{name: name, schedule: "0 0 1 1 *", enabled: true}); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+	if err := scheduler.TriggerCheck("first"); err != nil {
+		t.Fatalf("TriggerCheck(first) error = %v", err)
+	}
+	<-executor.started
+	if err := scheduler.TriggerCheck("second"); err != nil {
+		t.Fatalf("TriggerCheck(second) error = %v", err)
+	}
+	close(executor.blocker)
+
+	result := testResult("first", "fail", 2*time.Second)
+	result.Message = "timeout"
+	scheduler.handleCheckResult(result)
+
+	got := strings.Join(publisher.types(), ",")
+	if got != "check.started:first,check.queued:second,check.completed:first" {
+		t.Fatalf("events = %s", got)
+	}
+	completed := publisher.events[2]
+	if completed.Status != "fail" || completed.Message != "timeout" || completed.Data["duration_ms"] != int64(2000) {
+		t.Fatalf("completed event = %+v", completed)
+	}
+}
@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"fmt"
+	"math/bits"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -9,139 +11,248 @@ import (
 
 type CronField struct {
 	min, max int
-	values   map[int]bool
+	// values is a bit set of the matching values.
+	values uint64
+	// star is set when the field starts with * or ?. It decides how the day
+	// of month and day of week fields are combined.
+	star      bool
+	modifiers []dayModifier
 }
 
+// CronExpression is a parsed cron schedule. It is either a set of fields,
+// with seconds defaulting to 0, or a fixed @every interval.
 type CronExpression struct {
+	second     CronField
 	minute     CronField
 	hour       CronField
 	dayOfMonth CronField
 	month      CronField
 	dayOfWeek  CronField
+	every      time.Duration
 }
 
+type dayField int
+
+const (
+	notDayField dayField = iota
+	dayOfMonthField
+	dayOfWeekField
+)
+
+type fieldSpec struct {
+	name     string
+	min, max int
+	names    []string
+	day      dayField
+}
+
+var cronFields = []fieldSpec{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31, day: dayOfMonthField},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	// 7 is Sunday as well and is folded into 0.
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}, day: dayOfWeekField},
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronExpression parses a Vixie cron expression: five fields, or six
+// with a leading seconds field, or one of the @ macros. Fields take numbers,
+// month and weekday names, lists, ranges and steps. The day of month field
+// also takes L, L-n, nW and LW, the day of week field nL and n#k.
 func ParseCronExpression(expr string) (*CronExpression, error) {
-	parts := strings.Fields(expr)
-	if len(parts) != 5 {
-		return nil, fmt.Errorf("invalid cron expression: expected 5 fields, got %d", len(parts))
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		return parseCronMacro(expr)
 	}
 
-	minute, err := parseField(parts[0], 0, 59)
-	if err != nil {
-		return nil, fmt.Errorf("invalid minute field: %w", err)
+	parts := strings.Fields(expr)
+	switch len(parts) {
+	case 5:
+		parts = append([]string{"0"}, parts...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression: expected 5 fields, or 6 with seconds, got %d", len(parts))
 	}
 
-	hour, err := parseField(parts[1], 0, 23)
-	if err != nil {
-		return nil, fmt.Errorf("invalid hour field: %w", err)
+	fields := make([]CronField, len(cronFields))
+	for i, spec := range cronFields {
+		field, err := parseField(parts[i], spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field: %w", spec.name, err)
+		}
+		fields[i] = field
 	}
 
-	dayOfMonth, err := parseField(parts[2], 1, 31)
-	if err != nil {
-		return nil, fmt.Errorf("invalid day of month field: %w", err)
+	cron := &CronExpression{
+		second:     fields[0],
+		minute:     fields[1],
+		hour:       fields[2],
+		dayOfMonth: fields[3],
+		month:      fields[4],
+		dayOfWeek:  fields[5],
 	}
-
-	month, err := parseField(parts[3], 1, 12)
-	if err != nil {
-		return nil, fmt.Errorf("invalid month field: %w", err)
+	// The calendar repeats every 28 years (from 1901 to 2099), so a schedule
+	// that does not fire from 2000 to 2027 never fires.
+	if cron.nextWall(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), 2027).IsZero() {
+		return nil, fmt.Errorf("invalid cron expression: %q never fires, no date matches its day and month fields", expr)
 	}
+	return cron, nil
+}
 
-	dayOfWeek, err := parseField(parts[4], 0, 6)
-	if err != nil {
-		return nil, fmt.Errorf("invalid day of week field: %w", err)
+func parseCronMacro(expr string) (*CronExpression, error) {
+	name, arg, _ := strings.Cut(expr, " ")
+	name = strings.ToLower(name)
+	arg = strings.TrimSpace(arg)
+
+	if name == "@every" {
+		every, err := time.ParseDuration(arg)
+		if err != nil {
+			every, err = parseInterval(arg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression: invalid @every duration %q", arg)
+		}
+		if every < time.Second {
+			return nil, fmt.Errorf("invalid cron expression: @every duration must be at least 1s, got %s", every)
+		}
+		return &CronExpression{every: every}, nil
 	}
 
-	return &CronExpression{
-		minute:     minute,
-		hour:       hour,
-		dayOfMonth: dayOfMonth,
-		month:      month,
-		dayOfWeek:  dayOfWeek,
-	}, nil
+	fields, ok := cronMacros[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid cron expression: unknown macro %s", name)
+	}
+	if arg != "" {
+		return nil, fmt.Errorf("invalid cron expression: %s takes no arguments", name)
+	}
+	return ParseCronExpression(fields)
 }
 
-func parseField(field string, min, max int) (CronField, error) {
+func parseField(field string, spec fieldSpec) (CronField, error) {
 	cf := CronField{
-		min:    min,
-		max:    max,
-		values: make(map[int]bool),
+		min:  spec.min,
+		max:  spec.max,
+		star: strings.HasPrefix(field, "*") || field == "?",
 	}
 
-	if field == "*" {
-		for i := min; i <= max; i++ {
-			cf.values[i] = true
+	if field == "?" && spec.day == notDayField {
+		return CronField{}, fmt.Errorf("? is only allowed in the day of month and day of week fields")
+	}
+	if field == "*" || field == "?" {
+		for i := spec.min; i <= spec.max; i++ {
+			cf.values |= 1 << i
+		}
+	} else {
+		for _, part := range strings.Split(strings.ToUpper(field), ",") {
+			isModifier, err := parseModifier(part, spec, &cf)
+			if err != nil {
+				return CronField{}, err
+			}
+			if isModifier {
+				continue
+			}
+			if err := parsePart(part, spec, &cf.values); err != nil {
+				return CronField{}, err
+			}
 		}
-		return cf, nil
 	}
 
-	parts := strings.Split(field, ",")
-	for _, part := range parts {
-		if err := parsePart(part, min, max, cf.values); err != nil {
-			return CronField{}, err
+	if spec.day == dayOfWeekField {
+		if cf.values&(1<<7) != 0 {
+			cf.values = cf.values&^(1<<7) | 1
 		}
+		cf.max = 6
 	}
-
 	return cf, nil
 }
 
-func parsePart(part string, min, max int, values map[int]bool) error {
+func parsePart(part string, spec fieldSpec, values *uint64) error {
 	if strings.Contains(part, "/") {
-		return parseStep(part, min, max, values)
+		return parseStep(part, spec, values)
 	}
 
 	if strings.Contains(part, "-") {
-		return parseRange(part, min, max, values)
+		return parseRange(part, spec, values)
 	}
 
-	val, err := strconv.Atoi(part)
+	val, err := parseValue(part, spec)
 	if err != nil {
-		return fmt.Errorf("invalid value: %s", part)
+		return err
 	}
 
-	if val < min || val > max {
-		return fmt.Errorf("value %d out of range [%d, %d]", val, min, max)
-	}
-
-	values[val] = true
+	*values |= 1 << val
 	return nil
 }
 
-func parseRange(part string, min, max int, values map[int]bool) error {
-	rangeParts := strings.Split(part, "-")
-	if len(rangeParts) != 2 {
-		return fmt.Errorf("invalid range: %s", part)
+func parseValue(part string, spec fieldSpec) (int, error) {
+	if i := slices.Index(spec.names, part); i >= 0 {
+		return spec.min + i, nil
 	}
 
-	start, err := strconv.Atoi(rangeParts[0])
+	val, err := strconv.Atoi(part)
 	if err != nil {
-		return fmt.Errorf("invalid range start: %s", rangeParts[0])
+		if len(spec.names) > 0 {
+			return 0, fmt.Errorf("invalid value: %s (use %d-%d or %s-%s)", part, spec.min, spec.max, spec.names[0], spec.names[len(spec.names)-1])
+		}
+		if strings.ContainsAny(part, "LW#") {
+			return 0, fmt.Errorf("invalid value: %s (L, W and # are only allowed in the day fields)", part)
+		}
+		return 0, fmt.Errorf("invalid value: %s", part)
 	}
 
-	end, err := strconv.Atoi(rangeParts[1])
+	if val < spec.min || val > spec.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", val, spec.min, spec.max)
+	}
+	return val, nil
+}
+
+func parseRange(part string, spec fieldSpec, values *uint64) error {
+	start, end, err := parseBounds(part, spec)
 	if err != nil {
-		return fmt.Errorf("invalid range end: %s", rangeParts[1])
+		return err
 	}
 
-	if start < min || start > max {
-		return fmt.Errorf("range start %d out of range [%d, %d]", start, min, max)
+	for i := start; i <= end; i++ {
+		*values |= 1 << i
 	}
 
-	if end < min || end > max {
-		return fmt.Errorf("range end %d out of range [%d, %d]", end, min, max)
+	return nil
+}
+
+func parseBounds(part string, spec fieldSpec) (int, int, error) {
+	rangeParts := strings.Split(part, "-")
+	if len(rangeParts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range: %s", part)
 	}
 
-	if start > end {
-		return fmt.Errorf("range start %d greater than end %d", start, end)
+	start, err := parseValue(rangeParts[0], spec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range start: %w", err)
 	}
 
-	for i := start; i <= end; i++ {
-		values[i] = true
+	end, err := parseValue(rangeParts[1], spec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range end: %w", err)
 	}
 
-	return nil
+	if start > end {
+		return 0, 0, fmt.Errorf("range start %d greater than end %d", start, end)
+	}
+	return start, end, nil
 }
 
-func parseStep(part string, min, max int, values map[int]bool) error {
+func parseStep(part string, spec fieldSpec, values *uint64) error {
 	stepParts := strings.Split(part, "/")
 	if len(stepParts) != 2 {
 		return fmt.Errorf("invalid step: %s", part)
@@ -156,125 +267,392 @@ func parseStep(part string, min, max int, values map[int]bool) error {
 		return fmt.Errorf("step must be positive: %d", step)
 	}
 
-	base := "*"
-	if stepParts[0] != "*" {
-		base = stepParts[0]
+	base := stepParts[0]
+	start, end := spec.min, spec.max
+	switch {
+	case base == "*":
+	case strings.Contains(base, "-"):
+		if start, end, err = parseBounds(base, spec); err != nil {
+			return err
+		}
+	default:
+		// A single value, such as 5/15, steps up to the maximum.
+		if start, err = parseValue(base, spec); err != nil {
+			return err
+		}
+	}
+
+	for i := start; i <= end; i += step {
+		*values |= 1 << i
 	}
 
-	var rangeValues []int
-	if base == "*" {
-		for i := min; i <= max; i++ {
-			rangeValues = append(rangeValues, i)
+	return nil
+}
+
+type modifierKind int
+
+const (
+	lastDayOfMonth modifierKind = iota
+	nearestWeekday
+	lastWeekdayOfMonth
+	nthWeekdayOfMonth
+)
+
+// dayModifier is an L, W or # entry of a day field. Its days depend on the
+// month, so it is matched against the full date.
+type dayModifier struct {
+	kind modifierKind
+	// value is the day of month for W (0 for LW) and the weekday for L and #
+	// in the day of week field.
+	value int
+	// n is the number of days before the last day for L-n and the week for #.
+	n int
+}
+
+// parseModifier parses the L, W and # entries of the day fields. It reports
+// false when part is a plain value, range or step.
+func parseModifier(part string, spec fieldSpec, cf *CronField) (bool, error) {
+	var m dayModifier
+	switch {
+	case spec.day == dayOfMonthField && part == "L":
+		m = dayModifier{kind: lastDayOfMonth}
+	case spec.day == dayOfMonthField && strings.HasPrefix(part, "L-"):
+		n, err := strconv.Atoi(part[2:])
+		if err != nil || n < 0 || n > 30 {
+			return true, fmt.Errorf("invalid value: %s (L-n takes 0 to 30 days)", part)
 		}
-	} else {
-		cf, err := parseField(base, min, max)
+		m = dayModifier{kind: lastDayOfMonth, n: n}
+	case spec.day == dayOfMonthField && part == "LW":
+		m = dayModifier{kind: nearestWeekday}
+	case spec.day == dayOfMonthField && strings.HasSuffix(part, "W"):
+		day, err := parseValue(strings.TrimSuffix(part, "W"), spec)
 		if err != nil {
-			return err
+			return true, fmt.Errorf("invalid value: %s (W takes a single day, such as 15W)", part)
+		}
+		m = dayModifier{kind: nearestWeekday, value: day}
+	case spec.day == dayOfWeekField && strings.HasSuffix(part, "L"):
+		weekday, err := parseValue(strings.TrimSuffix(part, "L"), spec)
+		if err != nil {
+			return true, fmt.Errorf("invalid value: %s (L takes a single weekday, such as 5L or FRIL)", part)
+		}
+		m = dayModifier{kind: lastWeekdayOfMonth, value: weekday % 7}
+	case spec.day == dayOfWeekField && strings.Contains(part, "#"):
+		day, week, _ := strings.Cut(part, "#")
+		weekday, err := parseValue(day, spec)
+		if err != nil {
+			return true, fmt.Errorf("invalid value: %s (# takes a single weekday, such as 5#3 or FRI#3)", part)
 		}
+		n, err := strconv.Atoi(week)
+		if err != nil || n < 1 || n > 5 {
+			return true, fmt.Errorf("invalid value: %s (the week after # must be 1 to 5)", part)
+		}
+		m = dayModifier{kind: nthWeekdayOfMonth, value: weekday % 7, n: n}
+	default:
+		return false, nil
+	}
+	cf.modifiers = append(cf.modifiers, m)
+	return true, nil
+}
 
-		for i := min; i <= max; i++ {
-			if cf.values[i] {
-				rangeValues = append(rangeValues, i)
-			}
+func (m dayModifier) matches(t time.Time) bool {
+	last := daysInMonth(t)
+	switch m.kind {
+	case lastDayOfMonth:
+		return t.Day() == last-m.n
+	case nearestWeekday:
+		day := m.value
+		if day == 0 {
+			day = last
 		}
+		return day <= last && t.Day() == nearestWeekdayOf(t, day, last)
+	case lastWeekdayOfMonth:
+		return int(t.Weekday()) == m.value && t.Day()+7 > last
+	case nthWeekdayOfMonth:
+		return int(t.Weekday()) == m.value && (t.Day()-1)/7+1 == m.n
 	}
+	return false
+}
 
-	for i, val := range rangeValues {
-		if i%step == 0 {
-			values[val] = true
+func (m dayModifier) String() string {
+	switch m.kind {
+	case lastDayOfMonth:
+		if m.n > 0 {
+			return fmt.Sprintf("L-%d", m.n)
 		}
+		return "L"
+	case nearestWeekday:
+		if m.value == 0 {
+			return "LW"
+		}
+		return fmt.Sprintf("%dW", m.value)
+	case lastWeekdayOfMonth:
+		return fmt.Sprintf("%dL", m.value)
+	default:
+		return fmt.Sprintf("%d#%d", m.value, m.n)
 	}
+}
 
-	return nil
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
 
+// nearestWeekdayOf returns the weekday closest to day in the month of t,
+// without leaving the month.
+func nearestWeekdayOf(t time.Time, day, last int) int {
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// Next returns the first activation after t, matching the wall clock of t's
+// location. Across DST changes, a schedule with fixed hours runs once right
+// after the clocks skip over its time, and only once when the clocks repeat
+// it. Schedules that match every hour follow the elapsed time instead.
+//
+// The wall clock is searched field by field within each period of constant
+// zone offset, so a search takes a few steps per field instead of one per
+// minute.
 func (c *CronExpression) Next(t time.Time) time.Time {
-	next := t.Add(time.Minute).Truncate(time.Minute)
+	if c.every > 0 {
+		return t.Add(c.every).Truncate(time.Second)
+	}
 
+	fixedHours := !c.hour.all()
+	// Some valid schedules, such as the fifth Monday of February, only fire
+	// once in a 28 year calendar cycle.
+	maxYear := t.Year() + 28
+	from := wallClock(t).Add(time.Second)
+	if end := repeatedWallClockEnd(t); fixedHours && !end.IsZero() && from.Before(wallClock(end)) {
+		from = wallClock(end)
+	}
+
+	current := t
 	for {
-		if c.matches(next) {
+		wall := c.nextWall(from, maxYear)
+		if wall.IsZero() {
+			return time.Time{}
+		}
+		_, offset := current.Zone()
+		_, end := current.ZoneBounds()
+		next := time.Unix(wall.Unix()-int64(offset), 0).In(t.Location())
+		if end.IsZero() || next.Before(end) {
 			return next
 		}
 
-		next = next.Add(time.Minute)
+		// The match lies beyond the next offset change. Continue the search
+		// on the wall clock after the change.
+		_, endOffset := end.Zone()
+		gap := time.Duration(endOffset-offset) * time.Second
+		endWall := wallClock(end)
+		switch {
+		case gap > 0 && fixedHours && wall.Before(endWall):
+			return end
+		case gap < 0 && fixedHours:
+			from = endWall.Add(-gap)
+		default:
+			from = endWall
+		}
+		current = end
+	}
+}
 
-		if next.Year() > t.Year()+10 {
-			return time.Time{}
+// nextWall returns the first wall clock time at or after t, both given as
+// UTC, that matches all fields. It jumps to the next matching value of each
+// field instead of stepping through the minutes.
+func (c *CronExpression) nextWall(t time.Time, maxYear int) time.Time {
+	for t.Year() <= maxYear {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+
+		m, ok := c.month.next(int(month))
+		if !ok {
+			t = time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if m != int(month) {
+			t = time.Date(year, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = c.nextDay(t)
+			continue
+		}
+
+		h, ok := c.hour.next(hour)
+		if !ok {
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if h != hour {
+			t = time.Date(year, month, day, h, 0, 0, 0, time.UTC)
+			continue
+		}
+
+		mi, ok := c.minute.next(minute)
+		if !ok {
+			t = time.Date(year, month, day, hour+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if mi != minute {
+			t = time.Date(year, month, day, hour, mi, 0, 0, time.UTC)
+			continue
+		}
+
+		sec, ok := c.second.next(second)
+		if !ok {
+			t = time.Date(year, month, day, hour, minute+1, 0, 0, time.UTC)
+			continue
 		}
+		return time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
 	}
+	return time.Time{}
 }
 
-func (c *CronExpression) matches(t time.Time) bool {
-	return c.minute.matches(t.Minute()) &&
-		c.hour.matches(t.Hour()) &&
-		c.dayOfMonth.matches(t.Day()) &&
-		c.month.matches(int(t.Month())) &&
-		c.dayOfWeek.matches(int(t.Weekday()))
+// nextDay returns the start of the next day after t that may match. Plain day
+// of month values are jumped to directly, other day rules are tried day by
+// day.
+func (c *CronExpression) nextDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	if c.dayOfWeek.all() && len(c.dayOfMonth.modifiers) == 0 {
+		if next, ok := c.dayOfMonth.next(day + 1); ok && next <= daysInMonth(t) {
+			return time.Date(year, month, next, 0, 0, 0, 0, time.UTC)
+		}
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// wallClock returns the wall clock time of t as UTC, to the second.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// repeatedWallClockEnd returns the end of the repeated wall clock period
+// when t falls into the second pass after the clocks went back.
+func repeatedWallClockEnd(t time.Time) time.Time {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return time.Time{}
+	}
+	_, before := start.Add(-time.Second).Zone()
+	_, offset := t.Zone()
+	if before <= offset {
+		return time.Time{}
+	}
+	return start.Add(time.Duration(before-offset) * time.Second)
+}
+
+// matchesDay combines the day fields like Vixie cron: when both are
+// restricted a day matching either runs, otherwise both must match.
+func (c *CronExpression) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth.matchesDate(t, t.Day())
+	dayOfWeek := c.dayOfWeek.matchesDate(t, int(t.Weekday()))
+	if c.dayOfMonth.star || c.dayOfWeek.star {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
 }
 
 func (cf *CronField) matches(value int) bool {
-	return cf.values[value]
+	return cf.values&(1<<value) != 0
+}
+
+func (cf *CronField) matchesDate(t time.Time, value int) bool {
+	if cf.matches(value) {
+		return true
+	}
+	for _, m := range cf.modifiers {
+		if m.matches(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the smallest value of the field that is at least value.
+func (cf *CronField) next(value int) (int, bool) {
+	if value > cf.max {
+		return 0, false
+	}
+	next := bits.TrailingZeros64(cf.values >> max(value, 0) << max(value, 0))
+	if next > cf.max {
+		return 0, false
+	}
+	return next, true
+}
+
+func (cf *CronField) all() bool {
+	return bits.OnesCount64(cf.values) == cf.max-cf.min+1
 }
 
 func (c *CronExpression) String() string {
+	if c.every > 0 {
+		return "@every " + c.every.String()
+	}
+
 	var parts []string
 
-	parts = append(parts, fieldToString(c.minute, 0, 59))
-	parts = append(parts, fieldToString(c.hour, 0, 23))
-	parts = append(parts, fieldToString(c.dayOfMonth, 1, 31))
-	parts = append(parts, fieldToString(c.month, 1, 12))
-	parts = append(parts, fieldToString(c.dayOfWeek, 0, 6))
+	if second := fieldToString(c.second); second != "0" {
+		parts = append(parts, second)
+	}
+	parts = append(parts, fieldToString(c.minute))
+	parts = append(parts, fieldToString(c.hour))
+	parts = append(parts, fieldToString(c.dayOfMonth))
+	parts = append(parts, fieldToString(c.month))
+	parts = append(parts, fieldToString(c.dayOfWeek))
 
 	return strings.Join(parts, " ")
 }
 
-func fieldToString(cf CronField, min, max int) string {
-	all := true
-	for i := min; i <= max; i++ {
-		if !cf.values[i] {
-			all = false
-			break
-		}
-	}
-
-	if all {
+func fieldToString(cf CronField) string {
+	if cf.all() {
 		return "*"
 	}
 
-	values := make([]int, 0, len(cf.values))
-	for i := min; i <= max; i++ {
-		if cf.values[i] {
+	values := make([]int, 0, bits.OnesCount64(cf.values))
+	for i := cf.min; i <= cf.max; i++ {
+		if cf.matches(i) {
 			values = append(values, i)
 		}
 	}
 
-	if len(values) == 1 {
-		return strconv.Itoa(values[0])
-	}
-
 	ranges := make([]string, 0)
-	start := values[0]
-	end := values[0]
-
-	for i := 1; i < len(values); i++ {
-		if values[i] == end+1 {
-			end = values[i]
-		} else {
-			if start == end {
-				ranges = append(ranges, strconv.Itoa(start))
+	if len(values) > 0 {
+		start := values[0]
+		end := values[0]
+
+		for i := 1; i < len(values); i++ {
+			if values[i] == end+1 {
+				end = values[i]
 			} else {
-				ranges = append(ranges, fmt.Sprintf("%d-%d", start, end))
+				ranges = append(ranges, formatRange(start, end))
+				start = values[i]
+				end = values[i]
 			}
-			start = values[i]
-			end = values[i]
 		}
+		ranges = append(ranges, formatRange(start, end))
 	}
 
-	if start == end {
-		ranges = append(ranges, strconv.Itoa(start))
-	} else {
-		ranges = append(ranges, fmt.Sprintf("%d-%d", start, end))
+	for _, m := range cf.modifiers {
+		ranges = append(ranges, m.String())
 	}
 
 	return strings.Join(ranges, ",")
 }
+
+func formatRange(start, end int) string {
+	if start == end {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%d", start, end)
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+)
+
+func TestCronDialect(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		expr string
+		want []time.Time
+	}{
+		{"@hourly", []time.Time{time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)}},
+		{"@daily", []time.Time{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		{"@weekly", []time.Time{time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)}},
+		{"@monthly", []time.Time{time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		{"@yearly", []time.Time{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"@every 90s", []time.Time{time.Date(2025, 1, 1, 12, 1, 30, 0, time.UTC), time.Date(2025, 1, 1, 12, 3, 0, 0, time.UTC)}},
+		{"0 9 * * mon-fri", []time.Time{time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)}},
+		{"0 0 1 JAN,JUL *", []time.Time{time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 * * 7", []time.Time{time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)}},
+		{"*/20 * * * * *", []time.Time{time.Date(2025, 1, 1, 12, 0, 20, 0, time.UTC), time.Date(2025, 1, 1, 12, 0, 40, 0, time.UTC), time.Date(2025, 1, 1, 12, 1, 0, 0, time.UTC)}},
+		{"0 0 L * *", []time.Time{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 L-2 * *", []time.Time{time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)}},
+		// 2025-02-01 is a Saturday and 2025-03-15 a Saturday.
+		{"0 0 1W 2 *", []time.Time{time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 15W 3 *", []time.Time{time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)}},
+		// 2025-08-31 is a Sunday.
+		{"0 0 LW 8 *", []time.Time{time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 * * FRIL", []time.Time{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 ? * 2#1", []time.Time{time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 4, 0, 0, 0, 0, time.UTC)}},
+		// Both day fields restricted: the 10th or any Monday.
+		{"0 0 10 * 1", []time.Time{time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)}},
+		{"0 0 29 2 *", []time.Time{time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)}},
+		// Five Mondays in February only happen once in 28 years.
+		{"0 0 * FEB MON#5", []time.Time{time.Date(2044, 2, 29, 0, 0, 0, 0, time.UTC)}},
+		// A starred day of month field keeps the fields combined with AND.
+		{"0 0 */2 * 1", []time.Time{time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCronExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronExpression() error = %v", err)
+			}
+			next := base
+			for i, want := range tt.want {
+				next = cron.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("run %d: Next() = %v, want %v", i, next, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCronDialectErrors(t *testing.T) {
+	tests := map[string]string{
+		"@fortnightly":      "unknown macro @fortnightly",
+		"@daily 5":          "@daily takes no arguments",
+		"@every soon":       `invalid @every duration "soon"`,
+		"@every 500ms":      "at least 1s",
+		"0 9 * * MON-FRY":   "invalid day of week field: invalid range end: invalid value: FRY (use 0-7 or SUN-SAT)",
+		"0 9 * JAM *":       "invalid month field",
+		"0 L * * *":         "L, W and # are only allowed in the day fields",
+		"0 0 L-40 * *":      "L-n takes 0 to 30 days",
+		"0 0 1-5W * *":      "W takes a single day",
+		"0 0 * * 1#6":       "the week after # must be 1 to 5",
+		"0 0 * * L":         "L takes a single weekday",
+		"? * * * *":         "? is only allowed in the day of month and day of week fields",
+		"60 * * * * *":      "invalid second field: value 60 out of range [0, 59]",
+		"* * * * * * *":     "expected 5 fields, or 6 with seconds, got 7",
+		"0 0 * * 3-1":       "range start 3 greater than end 1",
+		"0 */0 * * *":       "step must be positive",
+		"0 0 31 2 *":        `"0 0 31 2 *" never fires`,
+		"0 0 31 4,6 *":      "never fires",
+		"0 0 15W,L 2 MON#2": "",
+	}
+	for expr, want := range tests {
+		_, err := ParseCronExpression(expr)
+		if want == "" {
+			if err != nil {
+				t.Errorf("ParseCronExpression(%q) error = %v", expr, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseCronExpression(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+}
+
+func TestCronExpressionString(t *testing.T) {
+	tests := map[string]string{
+		"0 9 * * mon-fri":     "0 9 * * 1-5",
+		"*/15 0 * * *":        "0,15,30,45 0 * * *",
+		"30 0 12 L,15W * *":   "30 0 12 L,15W * *",
+		"0 0 ? * FRIL,1#2":    "0 0 * * 5L,1#2",
+		"@daily":              "0 0 * * *",
+		"@every 1h":           "@every 1h0m0s",
+		"0 0 1 jan-mar sun,7": "0 0 1 1-3 0",
+	}
+	for expr, want := range tests {
+		cron, err := ParseCronExpression(expr)
+		if err != nil {
+			t.Fatalf("ParseCronExpression(%q) error = %v", expr, err)
+		}
+		if got := cron.String(); got != want {
+			t.Errorf("String(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	cfg := &config.Config{Checks: []config.CheckConfig{
+		{Name: "api", Schedule: config.Schedule{Cron: "@every 30s"}},
+		{Name: "disk", Schedule: config.Schedule{Interval: "1m"}},
+	}}
+	if err := ValidateSchedules(cfg); err != nil {
+		t.Fatalf("ValidateSchedules() error = %v", err)
+	}
+
+	cfg.Checks = append(cfg.Checks, config.CheckConfig{Name: "report", Schedule: config.Schedule{Cron: "0 0 30 2 *"}})
+	if err := ValidateSchedules(cfg); err == nil || !strings.Contains(err.Error(), "check report: invalid cron") {
+		t.Errorf("ValidateSchedules() error = %v", err)
+	}
+
+	cfg.Checks = cfg.Checks[:2]
+	cfg.MaintenanceWindows = []config.MaintenanceWindowConfig{{Name: "deploy", Cron: "0 0 31 4 *", Duration: "1h", Checks: []string{"api"}}}
+	if err := ValidateSchedules(cfg); err == nil || !strings.Contains(err.Error(), "maintenance window deploy") {
+		t.Errorf("ValidateSchedules() error = %v", err)
+	}
+}
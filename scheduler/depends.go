@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pfarrer/foghorn/logger"
+)
+
+// StatusSuppressed marks a check that was not run because one of its
+// dependencies is failing.
+const StatusSuppressed = "suppressed"
+
+type DependentCheckConfig interface {
+	CheckConfig
+	GetDependencies() []string
+}
+
+func checkDependencies(config CheckConfig) []string {
+	dependent, ok := config.(DependentCheckConfig)
+	if !ok {
+		return nil
+	}
+	return dependent.GetDependencies()
+}
+
+// failedDependencyLocked returns the failing check that suppresses the named
+// check and its status. Suppressed dependencies are followed to the root
+// cause; a dependency that has not run yet does not suppress.
+func (s *Scheduler) failedDependencyLocked(name string, visited map[string]bool) (string, string) {
+	check, ok := s.checks[name]
+	if !ok || visited[name] {
+		return "", ""
+	}
+	visited[name] = true
+	for _, dep := range checkDependencies(check.Config) {
+		parent, ok := s.checks[dep]
+		if !ok {
+			continue
+		}
+		if isFailureStatus(parent.LastStatus) {
+			return dep, parent.LastStatus
+		}
+		if parent.LastStatus == StatusSuppressed {
+			if cause, status := s.failedDependencyLocked(dep, visited); cause != "" {
+				return cause, status
+			}
+		}
+	}
+	return "", ""
+}
+
+// suppressCheck records a suppressed result instead of running the check and
+// schedules its next run as if it had run.
+func (s *Scheduler) suppressCheck(name string, check *ScheduledCheck, cause, status string) {
+	logger.Info("Suppressing check %s: dependency %s is %s", name, cause, status)
+	now := time.Now().In(s.location)
+	s.mu.Lock()
+	check.SuppressedBy = cause
+	s.mu.Unlock()
+
+	s.handleCheckResult(Result{
+		CheckName:  name,
+		Status:     StatusSuppressed,
+		Message:    fmt.Sprintf("dependency %s is %s", cause, status),
+		StartedAt:  now,
+		FinishedAt: now,
+	})
+
+	s.mu.Lock()
+	check.LastRun = &now
+	s.scheduleNextRunLocked(check, now)
+	s.finishOneShotRunLocked(name, nil)
+	s.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+type DependentMockCheckConfig struct {
+	IntervalMockCheckConfig
+	dependsOn []string
+}
+
+func (m *DependentMockCheckConfig) GetDependencies() []string {
+	return m.dependsOn
+}
+
+func TestDependencySuppression(t *testing.T) {
+	executor := &sequenceExecutor{statuses: []string{"pass"}}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+	for _, check := range []*DependentMockCheckConfig{
+		{IntervalMockCheckConfig{name: "dns", interval: "1m", enabled: true}, nil},
+		{IntervalMockCheckConfig{name: "api", interval: "1m", enabled: true}, []string{"dns"}},
+		{IntervalMockCheckConfig{name: "web", interval: "1m", enabled: true}, []string{"api"}},
+	} {
+		if err := scheduler.AddCheck(check); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+
+	scheduler.handleCheckResult(testResult("dns", "fail", time.Second))
+	before := time.Now()
+	results, err := scheduler.RunOnce([]string{"api", "web"})
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if executor.runs != 0 {
+		t.Fatalf("suppressed checks ran %d times", executor.runs)
+	}
+	for i, name := range []string{"api", "web"} {
+		check, _ := scheduler.GetCheckStatus(name)
+		if results[i].Status != StatusSuppressed || results[i].Message != "dependency dns is fail" {
+			t.Errorf("%s result = %+v", name, results[i])
+		}
+		if check.LastStatus != StatusSuppressed || check.SuppressedBy != "dns" || !check.NextRun.After(before) {
+			t.Errorf("%s = %s by %q, next run %v", name, check.LastStatus, check.SuppressedBy, check.NextRun)
+		}
+	}
+	if snapshot := scheduler.Snapshot().Checks["web"]; snapshot.SuppressedBy != "dns" || len(snapshot.DependsOn) != 1 {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+
+	scheduler.handleCheckResult(testResult("dns", "pass", time.Second))
+	results, err = scheduler.RunOnce([]string{"api", "web"})
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if executor.runs != 2 || results[0].Status != "pass" || results[1].Status != "pass" {
+		t.Fatalf("recovered checks = %+v after %d runs", results, executor.runs)
+	}
+	if check, _ := scheduler.GetCheckStatus("web"); check.SuppressedBy != "" {
+		t.Errorf("SuppressedBy = %q after recovery", check.SuppressedBy)
+	}
+	for _, transition := range handler.transitions {
+		if transition.CheckName != "dns" {
+			t.Errorf("unexpected transition %+v", transition)
+		}
+	}
+}
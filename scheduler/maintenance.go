@@ -0,0 +1,288 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pfarrer/foghorn/logger"
+)
+
+// StatusMaintenance marks a result recorded during a maintenance window or
+// silence. It is not an alert status and is left out of the counts.
+const StatusMaintenance = "maintenance"
+
+const (
+	MaintenanceModeSkip   = "skip"
+	MaintenanceModeRecord = "record"
+)
+
+var (
+	ErrSilenceNotFound = errors.New("silence not found")
+	ErrInvalidSilence  = errors.New("invalid silence")
+)
+
+// MaintenanceWindow is a recurring (Cron plus Duration) or fixed (Start to
+// End) period during which matching checks are in maintenance. Silences are
+// fixed windows created at runtime.
+type MaintenanceWindow struct {
+	Name     string
+	Checks   []string
+	Tags     []string
+	Mode     string
+	Comment  string
+	Cron     string
+	Duration time.Duration
+	Start    time.Time
+	End      time.Time
+	Silence  bool
+	cron     *CronExpression
+}
+
+// MaintenanceStatus is the snapshot of a maintenance window or silence.
+type MaintenanceStatus struct {
+	Name        string    `json:"name"`
+	Silence     bool      `json:"silence,omitempty"`
+	Checks      []string  `json:"checks,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Mode        string    `json:"mode"`
+	Comment     string    `json:"comment,omitempty"`
+	Cron        string    `json:"cron,omitempty"`
+	Duration    string    `json:"duration,omitempty"`
+	Start       time.Time `json:"start,omitzero"`
+	End         time.Time `json:"end,omitzero"`
+	Active      bool      `json:"active"`
+	ActiveUntil time.Time `json:"active_until,omitzero"`
+}
+
+func (w *MaintenanceWindow) matches(name string, tags []string) bool {
+	if slices.Contains(w.Checks, "*") || slices.Contains(w.Checks, name) {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(w.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeUntil returns the end of the occurrence that covers t, or the zero
+// time when the window is not active at t.
+func (w *MaintenanceWindow) activeUntil(t time.Time) time.Time {
+	if w.cron == nil {
+		if !t.Before(w.Start) && t.Before(w.End) {
+			return w.End
+		}
+		return time.Time{}
+	}
+	if start := w.cron.Next(t.Add(-w.Duration)); !start.IsZero() && !start.After(t) {
+		return start.Add(w.Duration)
+	}
+	return time.Time{}
+}
+
+func (w *MaintenanceWindow) status(now time.Time) MaintenanceStatus {
+	status := MaintenanceStatus{
+		Name:    w.Name,
+		Silence: w.Silence,
+		Checks:  slices.Clone(w.Checks),
+		Tags:    slices.Clone(w.Tags),
+		Mode:    w.Mode,
+		Comment: w.Comment,
+		Cron:    w.Cron,
+		Start:   w.Start,
+		End:     w.End,
+	}
+	if w.Duration > 0 {
+		status.Duration = w.Duration.String()
+	}
+	if until := w.activeUntil(now); !until.IsZero() {
+		status.Active = true
+		status.ActiveUntil = until
+	}
+	return status
+}
+
+func normalizeWindow(w MaintenanceWindow) (*MaintenanceWindow, error) {
+	if w.Mode == "" {
+		w.Mode = MaintenanceModeSkip
+	}
+	if w.Mode != MaintenanceModeSkip && w.Mode != MaintenanceModeRecord {
+		return nil, fmt.Errorf("maintenance window %s: mode must be one of %s, %s", w.Name, MaintenanceModeSkip, MaintenanceModeRecord)
+	}
+	if len(w.Checks) == 0 && len(w.Tags) == 0 {
+		return nil, fmt.Errorf("maintenance window %s: checks or tags are required", w.Name)
+	}
+	if w.Cron != "" {
+		parsed, err := ParseCronExpression(w.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %s: invalid cron: %w", w.Name, err)
+		}
+		if w.Duration <= 0 {
+			return nil, fmt.Errorf("maintenance window %s: duration must be positive", w.Name)
+		}
+		w.cron = parsed
+	} else if !w.End.After(w.Start) {
+		return nil, fmt.Errorf("maintenance window %s: end must be after start", w.Name)
+	}
+	return &w, nil
+}
+
+// SetMaintenanceWindows replaces the configured maintenance windows.
+// Silences are kept. If any window is invalid nothing is changed.
+func (s *Scheduler) SetMaintenanceWindows(windows []MaintenanceWindow) error {
+	parsed := make([]*MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		window, err := normalizeWindow(w)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, window)
+	}
+	s.mu.Lock()
+	s.windows = parsed
+	s.updateMaintenanceLocked(time.Now().In(s.location))
+	s.mu.Unlock()
+	return nil
+}
+
+// AddSilence puts the matching checks into maintenance from now until
+// silence.End. The silence gets a generated name, which RemoveSilence takes.
+func (s *Scheduler) AddSilence(silence MaintenanceWindow) (MaintenanceStatus, error) {
+	now := time.Now().In(s.location)
+	if !silence.End.After(now) {
+		return MaintenanceStatus{}, fmt.Errorf("%w: expiry must be in the future", ErrInvalidSilence)
+	}
+	if silence.Comment == "" {
+		return MaintenanceStatus{}, fmt.Errorf("%w: comment is required", ErrInvalidSilence)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range silence.Checks {
+		if _, exists := s.checks[name]; !exists && name != "*" {
+			return MaintenanceStatus{}, fmt.Errorf("%w: unknown check %s", ErrInvalidSilence, name)
+		}
+	}
+	silence.Name = "silence"
+	silence.Silence = true
+	silence.Start = now
+	silence.Cron = ""
+	window, err := normalizeWindow(silence)
+	if err != nil {
+		return MaintenanceStatus{}, fmt.Errorf("%w: %v", ErrInvalidSilence, err)
+	}
+	s.silenceSeq++
+	window.Name = fmt.Sprintf("silence-%d", s.silenceSeq)
+	s.silences = append(s.silences, window)
+	s.updateMaintenanceLocked(now)
+	logger.Info("Added %s until %s: %s", window.Name, window.End.Format(time.RFC3339), window.Comment)
+	return window.status(now), nil
+}
+
+func (s *Scheduler) RemoveSilence(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, silence := range s.silences {
+		if silence.Name == name {
+			s.silences = slices.Delete(s.silences, i, i+1)
+			s.updateMaintenanceLocked(time.Now().In(s.location))
+			logger.Info("Removed %s", name)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrSilenceNotFound, name)
+}
+
+// Silences returns the silences that have not expired yet.
+func (s *Scheduler) Silences() []MaintenanceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now().In(s.location)
+	out := make([]MaintenanceStatus, 0, len(s.silences))
+	for _, silence := range s.silences {
+		if now.Before(silence.End) {
+			out = append(out, silence.status(now))
+		}
+	}
+	return out
+}
+
+// activeMaintenanceLocked returns the window that puts the check into
+// maintenance at t.
+func (s *Scheduler) activeMaintenanceLocked(name string, check *ScheduledCheck, t time.Time) *MaintenanceWindow {
+	return matchMaintenance(s.activeWindowsLocked(t), name, checkTags(check.Config))
+}
+
+func (s *Scheduler) activeWindowsLocked(t time.Time) []*MaintenanceWindow {
+	var active []*MaintenanceWindow
+	for _, windows := range [][]*MaintenanceWindow{s.silences, s.windows} {
+		for _, w := range windows {
+			if !w.activeUntil(t).IsZero() {
+				active = append(active, w)
+			}
+		}
+	}
+	return active
+}
+
+// matchMaintenance picks the first active window that matches a check. Skip
+// windows take precedence over record windows.
+func matchMaintenance(active []*MaintenanceWindow, name string, tags []string) *MaintenanceWindow {
+	var match *MaintenanceWindow
+	for _, w := range active {
+		if !w.matches(name, tags) {
+			continue
+		}
+		if w.Mode == MaintenanceModeSkip {
+			return w
+		}
+		if match == nil {
+			match = w
+		}
+	}
+	return match
+}
+
+// updateMaintenanceLocked drops expired silences and refreshes the
+// maintenance window shown for every check.
+func (s *Scheduler) updateMaintenanceLocked(now time.Time) {
+	s.silences = slices.DeleteFunc(s.silences, func(w *MaintenanceWindow) bool {
+		return !now.Before(w.End)
+	})
+	active := s.activeWindowsLocked(now)
+	for name, check := range s.checks {
+		check.Maintenance = ""
+		if w := matchMaintenance(active, name, checkTags(check.Config)); w != nil {
+			check.Maintenance = w.Name
+		}
+	}
+}
+
+// skipForMaintenance reports whether a due check is in a skip window. The
+// run is skipped and the next one scheduled as if it had run.
+func (s *Scheduler) skipForMaintenance(name string, check *ScheduledCheck, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.activeMaintenanceLocked(name, check, now)
+	if w == nil || w.Mode != MaintenanceModeSkip {
+		return false
+	}
+	s.scheduleNextRunLocked(check, now)
+	logger.Debug("Skipping check %s during maintenance %s (next run: %v)", name, w.Name, check.NextRun.Format(time.RFC3339))
+	return true
+}
+
+// maintenanceResult rewrites a result that completed during maintenance.
+// The original status is kept in the message.
+func maintenanceResult(result Result, window *MaintenanceWindow) Result {
+	message := fmt.Sprintf("%s during %s", result.Status, window.Name)
+	if result.Message != "" {
+		message += ": " + result.Message
+	}
+	result.Status = StatusMaintenance
+	result.Message = message
+	return result
+}
@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowActiveUntil(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recurring, err := normalizeWindow(MaintenanceWindow{Name: "nightly", Checks: []string{"*"}, Cron: "0 2 * * *", Duration: 30 * time.Minute})
+	if err != nil {
+		t.Fatalf("normalizeWindow() error = %v", err)
+	}
+	fixed, err := normalizeWindow(MaintenanceWindow{Name: "migration", Checks: []string{"*"}, Start: day.Add(22 * time.Hour), End: day.Add(25 * time.Hour)})
+	if err != nil {
+		t.Fatalf("normalizeWindow() error = %v", err)
+	}
+
+	tests := []struct {
+		window *MaintenanceWindow
+		at     time.Duration
+		want   time.Duration
+	}{
+		{recurring, 2*time.Hour + 10*time.Minute, 2*time.Hour + 30*time.Minute},
+		{recurring, 2 * time.Hour, 2*time.Hour + 30*time.Minute},
+		{recurring, 2*time.Hour + 30*time.Minute, 0},
+		{recurring, time.Hour + 59*time.Minute, 0},
+		{recurring, 26*time.Hour + 5*time.Minute, 26*time.Hour + 30*time.Minute},
+		{fixed, 22 * time.Hour, 25 * time.Hour},
+		{fixed, 25 * time.Hour, 0},
+	}
+	for _, tt := range tests {
+		got := tt.window.activeUntil(day.Add(tt.at))
+		want := time.Time{}
+		if tt.want > 0 {
+			want = day.Add(tt.want)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%s.activeUntil(+%v) = %v, want %v", tt.window.Name, tt.at, got, want)
+		}
+	}
+
+	if _, err := normalizeWindow(MaintenanceWindow{Name: "bad", Checks: []string{"*"}, Cron: "0 2 * *", Duration: time.Minute}); err == nil {
+		t.Errorf("an invalid cron should be rejected")
+	}
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	executor := &sequenceExecutor{statuses: []string{"pass"}}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+	for _, check := range []*TaggedMockCheckConfig{
+		{MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}, nil},
+		{MockCheckConfig{name: "db", schedule: "*/5 * * * *", enabled: true}, []string{"storage"}},
+		{MockCheckConfig{name: "web", schedule: "*/5 * * * *", enabled: true}, nil},
+	} {
+		if err := scheduler.AddCheck(check); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+	scheduler.handleCheckResult(testResult("db", "pass", time.Second))
+
+	now := time.Now().UTC()
+	err := scheduler.SetMaintenanceWindows([]MaintenanceWindow{
+		{Name: "deploy", Checks: []string{"api"}, Start: now.Add(-time.Minute), End: now.Add(time.Hour)},
+		{Name: "migration", Tags: []string{"storage"}, Mode: MaintenanceModeRecord, Start: now.Add(-time.Minute), End: now.Add(time.Hour)},
+		{Name: "later", Checks: []string{"web"}, Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("SetMaintenanceWindows() error = %v", err)
+	}
+
+	api, _ := scheduler.GetCheckStatus("api")
+	api.NextRun = now.Add(-time.Second)
+	scheduler.tick()
+	if executor.runs != 0 || !api.NextRun.After(now) {
+		t.Fatalf("api in a skip window ran %d times, next run %v", executor.runs, api.NextRun)
+	}
+
+	scheduler.handleCheckResult(testResult("db", "fail", time.Second))
+	db, _ := scheduler.GetCheckStatus("db")
+	if db.LastStatus != "pass" || db.LastResult.Status != StatusMaintenance || db.LastResult.Message != "fail during migration" {
+		t.Fatalf("db = %s, last result %s %q", db.LastStatus, db.LastResult.Status, db.LastResult.Message)
+	}
+	if len(handler.transitions) != 0 {
+		t.Errorf("maintenance results should not alert: %+v", handler.transitions)
+	}
+
+	snapshot := scheduler.Snapshot()
+	if snapshot.Checks["api"].Maintenance != "deploy" || snapshot.Checks["db"].Maintenance != "migration" || snapshot.Checks["web"].Maintenance != "" {
+		t.Errorf("snapshot maintenance = %+v", snapshot.Checks)
+	}
+	if snapshot.Counts.Maintenance != 2 || snapshot.Counts.Pass != 0 || len(snapshot.Maintenance) != 3 || !snapshot.Maintenance[0].Active || snapshot.Maintenance[2].Active {
+		t.Errorf("snapshot counts = %+v, windows = %+v", snapshot.Counts, snapshot.Maintenance)
+	}
+}
+
+func TestMaintenanceResultsKeepStatusAndStreak(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	scheduler.handleCheckResult(testResult("api", "fail", time.Second))
+	silence, err := scheduler.AddSilence(MaintenanceWindow{Checks: []string{"api"}, Comment: "deploy", Mode: MaintenanceModeRecord, End: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("AddSilence() error = %v", err)
+	}
+	scheduler.handleCheckResult(testResult("api", "pass", time.Second))
+	if err := scheduler.RemoveSilence(silence.Name); err != nil {
+		t.Fatalf("RemoveSilence() error = %v", err)
+	}
+	scheduler.handleCheckResult(testResult("api", "fail", time.Second))
+
+	check, _ := scheduler.GetCheckStatus("api")
+	if check.LastStatus != "fail" || check.ConsecutiveFailures != 2 {
+		t.Errorf("status = %s with %d consecutive failures, want fail with 2", check.LastStatus, check.ConsecutiveFailures)
+	}
+	if check.RunCounts[StatusMaintenance] != 0 || check.RunCounts["fail"] != 2 {
+		t.Errorf("run counts = %v, want only the failures", check.RunCounts)
+	}
+	if len(check.History) != 3 || check.History[1].Status != StatusMaintenance {
+		t.Errorf("history = %+v, want the maintenance result kept", check.History)
+	}
+	if len(handler.transitions) != 1 {
+		t.Errorf("transitions = %+v, want only the first failure", handler.transitions)
+	}
+}
+
+func TestApplyStateSkipsMaintenanceResults(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	now := time.Now()
+	scheduler.ApplyState(map[string]CheckState{"api": {
+		LastStatus: StatusMaintenance,
+		LastRun:    now,
+		History: []CheckHistoryEntry{
+			{Status: "fail", CompletedAt: now.Add(-10 * time.Minute)},
+			{Status: "fail", CompletedAt: now.Add(-5 * time.Minute)},
+			{Status: StatusMaintenance, CompletedAt: now},
+		},
+	}})
+
+	check, _ := scheduler.GetCheckStatus("api")
+	if check.LastStatus != "fail" || check.ConsecutiveFailures != 2 {
+		t.Errorf("restored %s with %d consecutive failures, want fail with 2", check.LastStatus, check.ConsecutiveFailures)
+	}
+}
+
+func TestSilences(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	expiry := time.Now().Add(time.Hour)
+
+	for _, silence := range []MaintenanceWindow{
+		{Checks: []string{"api"}, End: expiry},
+		{Checks: []string{"api"}, Comment: "deploy", End: time.Now().Add(-time.Minute)},
+		{Checks: []string{"missing"}, Comment: "deploy", End: expiry},
+		{Comment: "deploy", End: expiry},
+	} {
+		if _, err := scheduler.AddSilence(silence); !errors.Is(err, ErrInvalidSilence) {
+			t.Errorf("AddSilence(%+v) error = %v, want ErrInvalidSilence", silence, err)
+		}
+	}
+
+	silence, err := scheduler.AddSilence(MaintenanceWindow{Checks: []string{"api"}, Comment: "deploy", Mode: MaintenanceModeRecord, End: expiry})
+	if err != nil {
+		t.Fatalf("AddSilence() error = %v", err)
+	}
+	if silence.Name != "silence-1" || !silence.Silence || !silence.Active || silence.Comment != "deploy" {
+		t.Fatalf("silence = %+v", silence)
+	}
+	if check, _ := scheduler.GetCheckStatus("api"); check.Maintenance != "silence-1" {
+		t.Errorf("Maintenance = %q", check.Maintenance)
+	}
+	scheduler.handleCheckResult(testResult("api", "fail", time.Second))
+	if check, _ := scheduler.GetCheckStatus("api"); check.LastStatus != "unknown" || check.LastResult.Status != StatusMaintenance {
+		t.Errorf("LastStatus = %s, want the status from before the silence", check.LastStatus)
+	}
+	if len(scheduler.Silences()) != 1 {
+		t.Errorf("Silences() = %+v", scheduler.Silences())
+	}
+
+	if err := scheduler.RemoveSilence("silence-1"); err != nil {
+		t.Fatalf("RemoveSilence() error = %v", err)
+	}
+	if err := scheduler.RemoveSilence("silence-1"); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("RemoveSilence() twice error = %v", err)
+	}
+	if check, _ := scheduler.GetCheckStatus("api"); check.Maintenance != "" {
+		t.Errorf("Maintenance = %q after removal", check.Maintenance)
+	}
+}
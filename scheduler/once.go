@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// oneShotRun collects the result of a check started by RunOnce. done is
+// closed once the executor has returned.
+type oneShotRun struct {
+	result *Result
+	err    error
+	done   chan struct{}
+}
+
+// RunOnce runs each named check once through the concurrency queue and waits
+// for all of them. Results are returned in the order of names. A run that
+// ends without reporting a result yields an error result. The scheduler must
+// not be started, so no further runs are scheduled.
+func (s *Scheduler) RunOnce(names []string) ([]Result, error) {
+	runs := make([]*oneShotRun, len(names))
+	checks := make([]*ScheduledCheck, len(names))
+	s.mu.Lock()
+	for i, name := range names {
+		check, exists := s.checks[name]
+		if !exists {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+		}
+		if _, pending := s.oneShotRuns[name]; pending || check.Running || check.IsQueued {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrCheckRunning, name)
+		}
+		checks[i] = check
+	}
+	if s.oneShotRuns == nil {
+		s.oneShotRuns = make(map[string]*oneShotRun, len(names))
+	}
+	for i, name := range names {
+		runs[i] = &oneShotRun{done: make(chan struct{})}
+		s.oneShotRuns[name] = runs[i]
+	}
+	s.mu.Unlock()
+
+	for i, name := range names {
+		if err := s.executeCheck(name, checks[i]); err != nil {
+			s.mu.Lock()
+			s.finishOneShotRunLocked(name, err)
+			s.mu.Unlock()
+		}
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	results := make([]Result, len(names))
+	for i, run := range runs {
+		for waiting := true; waiting; {
+			select {
+			case <-run.done:
+				waiting = false
+			case <-ticker.C:
+				s.processQueue()
+			}
+		}
+		if run.result != nil {
+			results[i] = *run.result
+			continue
+		}
+		message := "check did not report a result"
+		if run.err != nil {
+			message = run.err.Error()
+		}
+		now := time.Now().In(s.location)
+		results[i] = Result{CheckName: names[i], Status: "error", Error: message, StartedAt: now, FinishedAt: now}
+	}
+	return results, nil
+}
+
+func (s *Scheduler) finishOneShotRunLocked(name string, err error) {
+	run, ok := s.oneShotRuns[name]
+	if !ok {
+		return
+	}
+	run.err = err
+	delete(s.oneShotRuns, name)
+	close(run.done)
+}
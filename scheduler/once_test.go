@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type reportingExecutor struct {
+	mu       sync.Mutex
+	running  int
+	peak     int
+	statuses map[string]string
+	callback func(result Result)
+}
+
+func (r *reportingExecutor) Execute(check CheckConfig) error {
+	r.mu.Lock()
+	r.running++
+	r.peak = max(r.peak, r.running)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.running--
+		r.mu.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	status, ok := r.statuses[check.GetName()]
+	if !ok {
+		return errors.New("image not found")
+	}
+	now := time.Now()
+	r.callback(Result{CheckName: check.GetName(), Status: status, StartedAt: now.Add(-time.Second), FinishedAt: now})
+	return nil
+}
+
+func (r *reportingExecutor) SetResultCallback(callback func(result Result)) {
+	r.callback = callback
+}
+
+func TestRunOnce(t *testing.T) {
+	executor := &reportingExecutor{statuses: map[string]string{"api": "pass", "db": "fail", "disk": "warn"}}
+	scheduler := NewScheduler(executor, time.UTC, 1)
+	for _, name := range []string{"api", "db", "disk", "broken"} {
+		if err := scheduler.AddCheck(&IntervalMockCheckConfig{name: name, interval: "1h", enabled: true}); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+
+	results, err := scheduler.RunOnce([]string{"api", "db", "disk", "broken"})
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	want := []string{"pass", "fail", "warn", "error"}
+	for i, result := range results {
+		if result.Status != want[i] {
+			t.Errorf("results[%d] = %s %s, want %s", i, result.CheckName, result.Status, want[i])
+		}
+	}
+	if results[3].Error != "image not found" {
+		t.Errorf("error result = %+v", results[3])
+	}
+	if executor.peak != 1 {
+		t.Errorf("peak concurrency = %d, want 1", executor.peak)
+	}
+	if check, _ := scheduler.GetCheckStatus("db"); check.LastStatus != "fail" {
+		t.Errorf("LastStatus = %s, want fail", check.LastStatus)
+	}
+
+	if _, err := scheduler.RunOnce([]string{"missing"}); !errors.Is(err, ErrCheckNotFound) {
+		t.Fatalf("RunOnce(missing) error = %v, want ErrCheckNotFound", err)
+	}
+}
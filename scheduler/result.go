@@ -0,0 +1,42 @@
+package scheduler
+
+import "time"
+
+// Result is the outcome of a single check execution as reported by the
+// executor. ExitCode is nil when the container never exited on its own.
+type Result struct {
+	CheckName  string                 `json:"check_name"`
+	Status     string                 `json:"status"`
+	Message    string                 `json:"message,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	ExitCode   *int                   `json:"exit_code,omitempty"`
+	Image      string                 `json:"image,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+}
+
+func (r Result) Duration() time.Duration {
+	if r.StartedAt.IsZero() || r.FinishedAt.Before(r.StartedAt) {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+func copyResult(r *Result) *Result {
+	if r == nil {
+		return nil
+	}
+	out := *r
+	if r.ExitCode != nil {
+		code := *r.ExitCode
+		out.ExitCode = &code
+	}
+	if r.Data != nil {
+		out.Data = make(map[string]interface{}, len(r.Data))
+		for k, v := range r.Data {
+			out.Data[k] = v
+		}
+	}
+	return &out
+}
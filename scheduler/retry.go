@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetryPolicy controls how a failed run is retried within its scheduled slot
+// and how many consecutive results it takes to change a check's status.
+type RetryPolicy struct {
+	Retries           int
+	Delay             time.Duration
+	MaxDelay          time.Duration
+	Exponential       bool
+	FailureThreshold  int
+	RecoveryThreshold int
+}
+
+type RetryCheckConfig interface {
+	CheckConfig
+	GetRetryPolicy() RetryPolicy
+}
+
+// retryPolicy returns the policy of a check. Checks without one run once and
+// change status on the first differing result.
+func retryPolicy(config CheckConfig) RetryPolicy {
+	var policy RetryPolicy
+	if retrying, ok := config.(RetryCheckConfig); ok {
+		policy = retrying.GetRetryPolicy()
+	}
+	policy.Retries = max(policy.Retries, 0)
+	policy.FailureThreshold = max(policy.FailureThreshold, 1)
+	policy.RecoveryThreshold = max(policy.RecoveryThreshold, 1)
+	return policy
+}
+
+// delay returns the wait before the given retry, starting at 1. Exponential
+// backoff doubles the delay for every further retry, up to MaxDelay if set.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Delay
+	if !p.Exponential {
+		return delay
+	}
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	return delay
+}
+
+func isFailureStatus(status string) bool {
+	return status == "fail" || status == "error"
+}
+
+// commitStatus applies the failure and recovery thresholds to a result and
+// returns the status the check should report. A result that moves a check
+// into or out of failure stays pending until enough consecutive results agree.
+func commitStatus(check *ScheduledCheck, status string, policy RetryPolicy) string {
+	failing := isFailureStatus(status)
+	if !isAlertStatus(status) || failing == isFailureStatus(check.LastStatus) {
+		clearPending(check)
+		return status
+	}
+
+	threshold := policy.FailureThreshold
+	if !failing {
+		threshold = policy.RecoveryThreshold
+	}
+	if check.PendingStatus != "" && isFailureStatus(check.PendingStatus) == failing {
+		check.PendingCount++
+	} else {
+		check.PendingCount = 1
+	}
+	if check.PendingCount >= threshold {
+		clearPending(check)
+		return status
+	}
+	check.PendingStatus = status
+	check.PendingThreshold = threshold
+	return check.LastStatus
+}
+
+func clearPending(check *ScheduledCheck) {
+	check.PendingStatus = ""
+	check.PendingCount = 0
+	check.PendingThreshold = 0
+}
+
+// PendingLabel describes a status change that has not reached its threshold
+// yet, such as "failing 2/3". It is empty when nothing is pending.
+func (c *ScheduledCheck) PendingLabel() string {
+	if c.PendingStatus == "" {
+		return ""
+	}
+	verb := "recovering"
+	if isFailureStatus(c.PendingStatus) {
+		verb = "failing"
+	}
+	return fmt.Sprintf("%s %d/%d", verb, c.PendingCount, c.PendingThreshold)
+}
+
+// AttemptLabel describes a run that is being retried, such as "retry 2/3".
+// It is empty for first attempts.
+func (c *ScheduledCheck) AttemptLabel() string {
+	if !c.Running || c.Attempt <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("retry %d/%d", c.Attempt, c.MaxAttempts)
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type RetryMockCheckConfig struct {
+	IntervalMockCheckConfig
+	policy RetryPolicy
+}
+
+func (m *RetryMockCheckConfig) GetRetryPolicy() RetryPolicy {
+	return m.policy
+}
+
+// sequenceExecutor reports the next status of its sequence on every run and
+// repeats the last one once the sequence is used up.
+type sequenceExecutor struct {
+	mu       sync.Mutex
+	statuses []string
+	runs     int
+	callback func(result Result)
+}
+
+func (e *sequenceExecutor) Execute(check CheckConfig) error {
+	e.mu.Lock()
+	status := e.statuses[min(e.runs, len(e.statuses)-1)]
+	e.runs++
+	e.mu.Unlock()
+	now := time.Now()
+	e.callback(Result{CheckName: check.GetName(), Status: status, StartedAt: now, FinishedAt: now})
+	return nil
+}
+
+func (e *sequenceExecutor) SetResultCallback(callback func(result Result)) {
+	e.callback = callback
+}
+
+func TestRetriesWithinSlot(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		policy   RetryPolicy
+		want     string
+		runs     int
+	}{
+		{"recovers on retry", []string{"fail", "error", "pass"}, RetryPolicy{Retries: 2, Delay: time.Millisecond}, "pass", 3},
+		{"retries exhausted", []string{"fail"}, RetryPolicy{Retries: 2, Delay: time.Millisecond, Exponential: true}, "fail", 3},
+		{"warn is not retried", []string{"warn", "pass"}, RetryPolicy{Retries: 2}, "warn", 1},
+		{"no retries", []string{"fail", "pass"}, RetryPolicy{}, "fail", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &sequenceExecutor{statuses: tt.statuses}
+			scheduler := NewScheduler(executor, time.UTC, 0)
+			check := &RetryMockCheckConfig{IntervalMockCheckConfig{name: "api", interval: "1h", enabled: true}, tt.policy}
+			if err := scheduler.AddCheck(check); err != nil {
+				t.Fatalf("AddCheck() error = %v", err)
+			}
+
+			results, err := scheduler.RunOnce([]string{"api"})
+			if err != nil {
+				t.Fatalf("RunOnce() error = %v", err)
+			}
+			if results[0].Status != tt.want || executor.runs != tt.runs {
+				t.Fatalf("result = %s after %d runs, want %s after %d", results[0].Status, executor.runs, tt.want, tt.runs)
+			}
+			status, _ := scheduler.GetCheckStatus("api")
+			if len(status.History) != 1 || status.RunCounts[tt.want] != 1 || status.Attempt != 0 {
+				t.Errorf("only the final attempt should be recorded: %+v", status)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	constant := RetryPolicy{Delay: time.Second}
+	exponential := RetryPolicy{Delay: time.Second, Exponential: true}
+	capped := RetryPolicy{Delay: time.Second, MaxDelay: 5 * time.Second, Exponential: true}
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second} {
+		if got := exponential.delay(retry); got != want {
+			t.Errorf("exponential delay(%d) = %v, want %v", retry, got, want)
+		}
+		if got := constant.delay(retry); got != time.Second {
+			t.Errorf("constant delay(%d) = %v, want 1s", retry, got)
+		}
+		if got := capped.delay(retry); got != min(want, 5*time.Second) {
+			t.Errorf("capped delay(%d) = %v, want at most 5s", retry, got)
+		}
+	}
+	if got := capped.delay(40); got != 5*time.Second {
+		t.Errorf("capped delay(40) = %v, want 5s", got)
+	}
+}
+
+func TestRetryWaitReleasesConcurrencySlot(t *testing.T) {
+	executor := &sequenceExecutor{statuses: []string{"fail", "pass"}}
+	scheduler := NewScheduler(executor, time.UTC, 1)
+	defer scheduler.Stop()
+	flaky := &RetryMockCheckConfig{IntervalMockCheckConfig{name: "flaky", interval: "1h", enabled: true}, RetryPolicy{Retries: 1, Delay: time.Hour}}
+	for _, check := range []CheckConfig{flaky, &IntervalMockCheckConfig{name: "api", interval: "1h", enabled: true}} {
+		if err := scheduler.AddCheck(check); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+
+	if err := scheduler.TriggerCheck("flaky"); err != nil {
+		t.Fatalf("TriggerCheck(flaky) error = %v", err)
+	}
+	waitFor(t, func() bool { return scheduler.Snapshot().Counts.Running == 0 })
+	if err := scheduler.TriggerCheck("api"); err != nil {
+		t.Fatalf("TriggerCheck(api) error = %v", err)
+	}
+	waitFor(t, func() bool { return scheduler.Snapshot().Checks["api"].LastStatus == "pass" })
+
+	snapshot := scheduler.Snapshot()
+	if !snapshot.Checks["flaky"].Running || snapshot.Checks["flaky"].Attempt != 1 {
+		t.Errorf("flaky = %+v, want it waiting for its retry", snapshot.Checks["flaky"])
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within a second")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStatusThresholds(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+	check := &RetryMockCheckConfig{
+		IntervalMockCheckConfig{name: "api", interval: "1m", enabled: true},
+		RetryPolicy{FailureThreshold: 3, RecoveryThreshold: 2},
+	}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	steps := []struct {
+		status, want, pending string
+	}{
+		{"pass", "pass", ""},
+		{"fail", "pass", "failing 1/3"},
+		{"pass", "pass", ""},
+		{"fail", "pass", "failing 1/3"},
+		{"error", "pass", "failing 2/3"},
+		{"fail", "fail", ""},
+		{"error", "error", ""},
+		{"warn", "error", "recovering 1/2"},
+		{"pass", "pass", ""},
+	}
+	for i, step := range steps {
+		scheduler.handleCheckResult(testResult("api", step.status, time.Second))
+		status, _ := scheduler.GetCheckStatus("api")
+		if status.LastStatus != step.want || status.PendingLabel() != step.pending {
+			t.Fatalf("step %d (%s): status = %s %q, want %s %q", i, step.status, status.LastStatus, status.PendingLabel(), step.want, step.pending)
+		}
+		if step.pending != "" && scheduler.Snapshot().Checks["api"].Pending != step.pending {
+			t.Fatalf("step %d: snapshot pending = %q", i, scheduler.Snapshot().Checks["api"].Pending)
+		}
+	}
+
+	var got []string
+	for _, transition := range handler.transitions {
+		got = append(got, transition.PreviousStatus+">"+transition.Status)
+	}
+	if len(got) != 3 || got[0] != "pass>fail" || got[1] != "fail>error" || got[2] != "error>pass" {
+		t.Errorf("transitions = %v", got)
+	}
+	if status, _ := scheduler.GetCheckStatus("api"); len(status.History) != len(steps) {
+		t.Errorf("every result should be recorded in the history, got %d", len(status.History))
+	}
+}
@@ -2,12 +2,14 @@ package scheduler
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/pfarrer/foghorn/events"
 	"github.com/pfarrer/foghorn/logger"
 )
 
@@ -23,22 +25,66 @@ type IntervalCheckConfig interface {
 	GetInterval() string
 }
 
+type TaggedCheckConfig interface {
+	CheckConfig
+	GetTags() []string
+}
+
+// ZonedCheckConfig is a check whose cron schedule is evaluated in its own
+// IANA time zone instead of the scheduler's.
+type ZonedCheckConfig interface {
+	CheckConfig
+	GetTimezone() string
+}
+
+type DescribedCheckConfig interface {
+	CheckConfig
+	GetDescription() string
+	GetImage() string
+}
+
 type CheckExecutor interface {
 	Execute(check CheckConfig) error
-	SetResultCallback(callback func(checkName string, status string, duration time.Duration))
+	SetResultCallback(callback func(result Result))
 }
 
 type ScheduledCheck struct {
-	Config       CheckConfig
-	NextRun      time.Time
-	LastRun      *time.Time
-	LastStatus   string
-	LastDuration time.Duration
-	Running      bool
-	ScheduleType ScheduleType
-	Interval     time.Duration
-	IsQueued     bool
-	History      []CheckHistoryEntry
+	Config              CheckConfig
+	NextRun             time.Time
+	LastRun             *time.Time
+	LastStatus          string
+	LastDuration        time.Duration
+	LastResult          *Result
+	LastSuccess         *time.Time
+	ConsecutiveFailures int
+	RunCounts           map[string]int64
+	Running             bool
+	Paused              bool
+	ScheduleType        ScheduleType
+	Interval            time.Duration
+	Location            *time.Location
+	// Splay is the offset of an interval check and Jitter the bound of the
+	// random delay of a cron check. Delay is the part of NextRun that comes
+	// from either.
+	Splay            time.Duration
+	Jitter           time.Duration
+	Delay            time.Duration
+	IsQueued         bool
+	History          []CheckHistoryEntry
+	SuppressedBy     string
+	Maintenance      string
+	PendingStatus    string
+	PendingCount     int
+	PendingThreshold int
+	Attempt          int
+	MaxAttempts      int
+	// Window is the schedule window that was active at the last tick, or
+	// empty when the check follows its own schedule.
+	Window      string
+	retryResult *Result
+	windows     []*ScheduleWindow
+	// cron is the parsed cron schedule, so it is parsed once per change.
+	cron *CronExpression
 }
 
 type Scheduler struct {
@@ -49,19 +95,41 @@ type Scheduler struct {
 	location            *time.Location
 	maxConcurrentChecks int
 	runningChecks       int
+	slotFreed           chan struct{}
 	queue               []CheckConfig
 	startTime           time.Time
 	mu                  sync.RWMutex
 	resultLogger        ResultLogger
+	transitionHandler   TransitionHandler
+	eventPublisher      events.Publisher
+	oneShotRuns         map[string]*oneShotRun
+	windows             []*MaintenanceWindow
+	silences            []*MaintenanceWindow
+	silenceSeq          int
 }
 
 type ResultLogger interface {
-	RecordResult(checkName string, status string, duration time.Duration, completedAt time.Time) error
+	RecordResult(result Result) error
+}
+
+type TransitionHandler interface {
+	HandleTransition(transition StatusTransition)
+}
+
+type StatusTransition struct {
+	CheckName      string
+	Tags           []string
+	PreviousStatus string
+	Status         string
+	Message        string
+	Duration       time.Duration
+	CompletedAt    time.Time
 }
 
 type CheckHistoryEntry struct {
 	Status      string
 	CompletedAt time.Time
+	Duration    time.Duration
 }
 
 const maxHistoryEntries = 10
@@ -76,6 +144,7 @@ func NewScheduler(executor CheckExecutor, location *time.Location, maxConcurrent
 		stopChan:            make(chan struct{}),
 		location:            location,
 		maxConcurrentChecks: maxConcurrentChecks,
+		slotFreed:           make(chan struct{}),
 		queue:               make([]CheckConfig, 0),
 		startTime:           time.Now(),
 	}
@@ -91,46 +160,207 @@ func (s *Scheduler) SetResultLogger(logger ResultLogger) {
 	s.resultLogger = logger
 }
 
+func (s *Scheduler) SetTransitionHandler(handler TransitionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transitionHandler = handler
+}
+
+func (s *Scheduler) SetEventPublisher(publisher events.Publisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventPublisher = publisher
+}
+
+func publishEvent(publisher events.Publisher, event events.Event) {
+	if publisher != nil {
+		publisher.Publish(event)
+	}
+}
+
 func (s *Scheduler) AddCheck(config CheckConfig) error {
-	if config.GetSchedule() == "" {
-		return fmt.Errorf("check %s: schedule is required", config.GetName())
+	check, err := s.newScheduledCheck(config)
+	if err != nil {
+		return err
 	}
 
-	var nextRun time.Time
+	s.mu.Lock()
+	s.checks[config.GetName()] = check
+	s.mu.Unlock()
+
+	logger.Info("Added check %s (enabled: %v, next run: %v)", config.GetName(), config.IsEnabled(), check.NextRun.Format(time.RFC3339))
+
+	return nil
+}
+
+func (s *Scheduler) newScheduledCheck(config CheckConfig) (*ScheduledCheck, error) {
 	var scheduleType ScheduleType
 	var interval time.Duration
+	var cron *CronExpression
 	var err error
+	splay, jitter := scheduleSpread(config)
+
+	location := s.location
+	if zoned, ok := config.(ZonedCheckConfig); ok && zoned.GetTimezone() != "" {
+		location, err = time.LoadLocation(zoned.GetTimezone())
+		if err != nil {
+			return nil, fmt.Errorf("check %s: unknown time zone %q", config.GetName(), zoned.GetTimezone())
+		}
+	}
+	windows, err := newScheduleWindows(config, location)
+	if err != nil {
+		return nil, err
+	}
+	if config.GetSchedule() == "" && len(windows) == 0 {
+		return nil, fmt.Errorf("check %s: schedule is required", config.GetName())
+	}
 
 	if intervalCheck, ok := config.(IntervalCheckConfig); ok && intervalCheck.GetScheduleType() == ScheduleTypeInterval {
 		scheduleType = ScheduleTypeInterval
 		interval, err = parseInterval(intervalCheck.GetInterval())
 		if err != nil {
-			return fmt.Errorf("check %s: failed to parse interval: %w", config.GetName(), err)
+			return nil, fmt.Errorf("check %s: failed to parse interval: %w", config.GetName(), err)
 		}
-		nextRun = time.Now().In(s.location)
+		splay, jitter = splayOffset(config.GetName(), min(splay, interval)), 0
+	} else if config.GetSchedule() == "" {
+		scheduleType = ScheduleTypeWindowed
+		splay = 0
 	} else {
 		scheduleType = ScheduleTypeCron
-		nextRun, err = s.calculateNextRun(config.GetSchedule())
+		cron, err = ParseCronExpression(config.GetSchedule())
 		if err != nil {
-			return fmt.Errorf("check %s: failed to calculate next run: %w", config.GetName(), err)
+			return nil, fmt.Errorf("check %s: failed to calculate next run: %w", config.GetName(), err)
 		}
+		splay = 0
 	}
 
-	s.checks[config.GetName()] = &ScheduledCheck{
+	check := &ScheduledCheck{
 		Config:       config,
-		NextRun:      nextRun,
 		ScheduleType: scheduleType,
 		Interval:     interval,
+		Location:     location,
+		Splay:        splay,
+		Jitter:       jitter,
 		LastStatus:   "unknown",
+		cron:         cron,
+		windows:      windows,
 	}
-
-	logger.Info("Added check %s (enabled: %v, next run: %v)", config.GetName(), config.IsEnabled(), nextRun.Format(time.RFC3339))
-
-	return nil
+	// The configured start policy applies in ApplyState, when the daemon
+	// starts. Checks added later start like an overdue check would.
+	now := time.Now().In(s.location)
+	check.planFirstRun(StartPolicy{}.withDefaults(scheduleType), nil, now)
+	if len(windows) > 0 {
+		_, check.Window, _ = check.ruleAt(now)
+	}
+	return check, nil
 }
 
+// RemoveCheck drops a check from the schedule and the queue. A run that is
+// already in progress finishes, but its result no longer updates the check.
 func (s *Scheduler) RemoveCheck(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeCheckLocked(name)
+}
+
+func (s *Scheduler) removeCheckLocked(name string) {
 	delete(s.checks, name)
+	queue := s.queue[:0]
+	for _, queued := range s.queue {
+		if queued.GetName() != name {
+			queue = append(queue, queued)
+		}
+	}
+	s.queue = queue
+}
+
+type ReconcileResult struct {
+	Added   []string `json:"added,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func (r ReconcileResult) Changed() bool {
+	return len(r.Added)+len(r.Updated)+len(r.Removed) > 0
+}
+
+// Reconcile replaces the set of scheduled checks with configs. New checks are
+// added, missing ones removed and changed ones updated in place so they keep
+// their status and history. If any config is invalid nothing is changed.
+func (s *Scheduler) Reconcile(configs []CheckConfig) (ReconcileResult, error) {
+	planned := make(map[string]*ScheduledCheck, len(configs))
+	for _, config := range configs {
+		if _, exists := planned[config.GetName()]; exists {
+			return ReconcileResult{}, fmt.Errorf("check %s: duplicate check name", config.GetName())
+		}
+		check, err := s.newScheduledCheck(config)
+		if err != nil {
+			return ReconcileResult{}, err
+		}
+		planned[config.GetName()] = check
+	}
+
+	var result ReconcileResult
+	s.mu.Lock()
+	for name := range s.checks {
+		if _, keep := planned[name]; !keep {
+			s.removeCheckLocked(name)
+			result.Removed = append(result.Removed, name)
+		}
+	}
+	for name, check := range planned {
+		existing, exists := s.checks[name]
+		if !exists {
+			s.checks[name] = check
+			result.Added = append(result.Added, name)
+			continue
+		}
+		changed := !reflect.DeepEqual(existing.Config, check.Config)
+		scheduleChanged := existing.ScheduleType != check.ScheduleType ||
+			existing.Interval != check.Interval ||
+			existing.Config.GetSchedule() != check.Config.GetSchedule() ||
+			existing.Location.String() != check.Location.String() ||
+			!reflect.DeepEqual(existing.windows, check.windows)
+		existing.Config = check.Config
+		existing.Splay = check.Splay
+		existing.Jitter = check.Jitter
+		if scheduleChanged {
+			existing.ScheduleType = check.ScheduleType
+			existing.Interval = check.Interval
+			existing.Location = check.Location
+			existing.cron = check.cron
+			existing.windows = check.windows
+			existing.Window = check.Window
+			existing.NextRun = check.NextRun
+			existing.Delay = check.Delay
+			if existing.LastRun != nil {
+				existing.reschedule(time.Now().In(s.location))
+			}
+		}
+		for i, queued := range s.queue {
+			if queued.GetName() == name {
+				s.queue[i] = check.Config
+			}
+		}
+		if changed {
+			result.Updated = append(result.Updated, name)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Updated)
+	sort.Strings(result.Removed)
+	for _, name := range result.Added {
+		logger.Info("Added check %s (next run: %v)", name, planned[name].NextRun.Format(time.RFC3339))
+	}
+	for _, name := range result.Updated {
+		logger.Info("Updated check %s", name)
+	}
+	for _, name := range result.Removed {
+		logger.Info("Removed check %s", name)
+	}
+	return result, nil
 }
 
 func (s *Scheduler) Start(interval time.Duration) {
@@ -169,9 +399,15 @@ func (s *Scheduler) tick() {
 		check *ScheduledCheck
 	}
 
+	s.mu.Lock()
+	s.updateMaintenanceLocked(now)
+	s.updateWindowsLocked(now)
+	s.mu.Unlock()
+
 	s.mu.RLock()
 	for name, check := range s.checks {
-		if !check.Config.IsEnabled() || check.Running {
+		// Windowed checks that never run have no next run.
+		if !check.Config.IsEnabled() || check.Paused || check.Running || check.NextRun.IsZero() {
 			continue
 		}
 		if now.After(check.NextRun) || now.Equal(check.NextRun) {
@@ -184,18 +420,29 @@ func (s *Scheduler) tick() {
 	s.mu.RUnlock()
 
 	if len(due) > 1 {
+		s.mu.RLock()
+		priorities := make(map[string]time.Duration, len(due))
+		for _, item := range due {
+			priorities[item.name] = s.priorityDuration(item.check, now)
+		}
 		sort.Slice(due, func(i, j int) bool {
-			pi := s.priorityDuration(due[i].check, now)
-			pj := s.priorityDuration(due[j].check, now)
+			pi := priorities[due[i].name]
+			pj := priorities[due[j].name]
 			if pi == pj {
 				return due[i].name < due[j].name
 			}
 			return pi > pj
 		})
+		s.mu.RUnlock()
 	}
 
 	for _, item := range due {
-		s.executeCheck(item.name, item.check)
+		if s.skipForMaintenance(item.name, item.check, now) {
+			continue
+		}
+		if err := s.executeCheck(item.name, item.check); err != nil {
+			logger.Debug("Not starting check %s: %v", item.name, err)
+		}
 	}
 }
 
@@ -221,7 +468,9 @@ func (s *Scheduler) processQueue() {
 			s.mu.Lock()
 			check.IsQueued = false
 			s.mu.Unlock()
-			s.executeCheck(checkConfig.GetName(), check)
+			if err := s.executeCheck(checkConfig.GetName(), check); err != nil {
+				logger.Debug("Not starting queued check %s: %v", checkConfig.GetName(), err)
+			}
 		}
 	}
 
@@ -239,61 +488,200 @@ func (s *Scheduler) processQueue() {
 	s.mu.Unlock()
 }
 
-func (s *Scheduler) executeCheck(name string, check *ScheduledCheck) {
+// executeCheck starts a check, or queues it when the concurrency limit is
+// reached. Whether the check is already running or queued is checked under
+// the same lock that marks it, so a check never runs twice at once.
+func (s *Scheduler) executeCheck(name string, check *ScheduledCheck) error {
 	s.mu.Lock()
+	if check.Running || check.IsQueued {
+		s.mu.Unlock()
+		return ErrCheckRunning
+	}
+	if cause, status := s.failedDependencyLocked(name, map[string]bool{}); cause != "" {
+		check.IsQueued = false
+		s.mu.Unlock()
+		s.suppressCheck(name, check, cause, status)
+		return nil
+	}
 	if s.maxConcurrentChecks > 0 && s.runningChecks >= s.maxConcurrentChecks {
 		logger.Debug("Queuing check %s (concurrency limit reached: %d)", name, s.maxConcurrentChecks)
 		s.queue = append(s.queue, check.Config)
 		s.sortQueueLocked(time.Now().In(s.location))
 		check.IsQueued = true
+		publisher := s.eventPublisher
 		s.mu.Unlock()
-		return
+		publishEvent(publisher, events.Event{Type: events.CheckQueued, Check: name})
+		return nil
 	}
 
 	check.Running = true
 	check.IsQueued = false
+	check.Attempt = 1
+	check.MaxAttempts = retryPolicy(check.Config).Retries + 1
 	s.runningChecks++
 	now := time.Now().In(s.location)
 	check.LastRun = &now
+	config := check.Config
+	nextRun := check.NextRun
+	publisher := s.eventPublisher
 	s.mu.Unlock()
 
-	logger.Info("Executing check: %s (next run: %v)", name, check.NextRun.Format(time.RFC3339))
+	logger.Info("Executing check: %s (next run: %v)", name, nextRun.Format(time.RFC3339))
+	publishEvent(publisher, events.Event{Type: events.CheckStarted, Check: name, Time: now})
 
 	startTime := time.Now()
 	go func() {
+		var execErr error
+		holdsSlot := true
 		defer func() {
 			s.mu.Lock()
 			check.Running = false
-			s.runningChecks--
+			if holdsSlot {
+				s.releaseSlotLocked()
+			}
 			now := time.Now().In(s.location)
 			check.LastDuration = now.Sub(startTime)
-			if check.ScheduleType == ScheduleTypeInterval && check.Interval > 0 {
-				check.NextRun = now.Add(check.Interval)
-			} else {
-				nextRun, err := s.calculateNextRun(check.Config.GetSchedule())
-				if err == nil {
-					check.NextRun = nextRun
-				}
-			}
+			s.scheduleNextRunLocked(check, now)
 			check.LastRun = &now
+			check.Attempt = 0
+			check.MaxAttempts = 0
+			check.retryResult = nil
+			nextRun := check.NextRun
+			s.finishOneShotRunLocked(name, execErr)
 			s.mu.Unlock()
-			logger.Debug("Check %s completed (next run: %v)", name, check.NextRun.Format(time.RFC3339))
+			logger.Debug("Check %s completed (next run: %v)", name, nextRun.Format(time.RFC3339))
 		}()
 
-		if err := s.executor.Execute(check.Config); err != nil {
-			logger.Error("Error executing check %s: %v", name, err)
+		for {
+			if execErr = s.executor.Execute(config); execErr != nil {
+				logger.Error("Error executing check %s: %v", name, execErr)
+			}
+			delay, retry := s.retryDelay(check)
+			if !retry {
+				return
+			}
+			// The slot is given back while waiting, so retrying checks do
+			// not hold up the others under max_concurrent_checks.
+			s.mu.Lock()
+			s.releaseSlotLocked()
+			s.mu.Unlock()
+			holdsSlot = false
+			if !s.awaitRetry(delay) {
+				s.abandonRetry(check)
+				return
+			}
+			holdsSlot = true
+			config = s.startRetry(name, check)
 		}
 	}()
+	return nil
 }
 
-func (s *Scheduler) calculateNextRun(cronExpr string) (time.Time, error) {
-	parsed, err := ParseCronExpression(cronExpr)
-	if err != nil {
-		return time.Time{}, err
+// releaseSlotLocked frees a concurrency slot and wakes the retries waiting
+// for one.
+func (s *Scheduler) releaseSlotLocked() {
+	s.runningChecks--
+	close(s.slotFreed)
+	s.slotFreed = make(chan struct{})
+}
+
+// awaitRetry waits for the retry delay and then for a free concurrency slot,
+// which it takes. It reports false if the scheduler stops first.
+func (s *Scheduler) awaitRetry(delay time.Duration) bool {
+	select {
+	case <-time.After(delay):
+	case <-s.stopChan:
+		return false
+	}
+	for {
+		s.mu.Lock()
+		if s.maxConcurrentChecks <= 0 || s.runningChecks < s.maxConcurrentChecks {
+			s.runningChecks++
+			s.mu.Unlock()
+			return true
+		}
+		freed := s.slotFreed
+		s.mu.Unlock()
+		select {
+		case <-freed:
+		case <-s.stopChan:
+			return false
+		}
 	}
+}
 
-	now := time.Now().In(s.location)
-	return parsed.Next(now), nil
+// retryDelay reports whether the last attempt of a running check failed and
+// should be retried, and how long to wait first.
+func (s *Scheduler) retryDelay(check *ScheduledCheck) (time.Duration, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if check.retryResult == nil {
+		return 0, false
+	}
+	return retryPolicy(check.Config).delay(check.Attempt), true
+}
+
+func (s *Scheduler) startRetry(name string, check *ScheduledCheck) CheckConfig {
+	s.mu.Lock()
+	check.Attempt++
+	check.retryResult = nil
+	attempt, maxAttempts := check.Attempt, check.MaxAttempts
+	config := check.Config
+	publisher := s.eventPublisher
+	s.mu.Unlock()
+
+	logger.Info("Retrying check: %s (attempt %d of %d)", name, attempt, maxAttempts)
+	publishEvent(publisher, events.Event{
+		Type:  events.CheckStarted,
+		Check: name,
+		Time:  time.Now().In(s.location),
+		Data:  map[string]interface{}{"attempt": attempt, "max_attempts": maxAttempts},
+	})
+	return config
+}
+
+// abandonRetry records the failed attempt that was waiting for a retry when
+// the scheduler stopped, so the result is not lost.
+func (s *Scheduler) abandonRetry(check *ScheduledCheck) {
+	s.mu.Lock()
+	result := check.retryResult
+	check.retryResult = nil
+	check.MaxAttempts = check.Attempt
+	s.mu.Unlock()
+	if result != nil {
+		s.handleCheckResult(*result)
+	}
+}
+
+// scheduleNextRunLocked sets the next run of a check that ran or was skipped
+// at now, which the caller takes in the scheduler's location.
+func (s *Scheduler) scheduleNextRunLocked(check *ScheduledCheck, now time.Time) {
+	if len(check.windows) > 0 {
+		check.NextRun, check.Delay = check.scheduleWindowedRun(now, now)
+		return
+	}
+	if check.ScheduleType == ScheduleTypeInterval && check.Interval > 0 {
+		check.NextRun = now.Add(check.Interval)
+		check.Delay = 0
+		return
+	}
+	if nextRun := check.nextCronRun(now); !nextRun.IsZero() {
+		check.Delay = jitterDelay(check.Jitter)
+		check.NextRun = nextRun.Add(check.Delay)
+	}
+}
+
+// nextCronRun returns the next activation of the check's cron schedule in its
+// location, or the zero time for checks without one.
+func (c *ScheduledCheck) nextCronRun(now time.Time) time.Time {
+	if c.cron == nil {
+		return time.Time{}
+	}
+	location := c.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return c.cron.Next(now.In(location))
 }
 
 func (s *Scheduler) priorityDuration(check *ScheduledCheck, now time.Time) time.Duration {
@@ -304,7 +692,7 @@ func (s *Scheduler) priorityDuration(check *ScheduledCheck, now time.Time) time.
 		return check.Interval
 	}
 	if check.ScheduleType == ScheduleTypeCron {
-		if next, err := s.calculateNextRun(check.Config.GetSchedule()); err == nil {
+		if next := check.nextCronRun(now); !next.IsZero() {
 			return next.Sub(now)
 		}
 	}
@@ -318,11 +706,13 @@ func (s *Scheduler) sortQueueLocked(now time.Time) {
 	if len(s.queue) < 2 {
 		return
 	}
+	priorities := make(map[string]time.Duration, len(s.queue))
+	for _, queued := range s.queue {
+		priorities[queued.GetName()] = s.priorityDuration(s.checks[queued.GetName()], now)
+	}
 	sort.Slice(s.queue, func(i, j int) bool {
-		ci := s.checks[s.queue[i].GetName()]
-		cj := s.checks[s.queue[j].GetName()]
-		pi := s.priorityDuration(ci, now)
-		pj := s.priorityDuration(cj, now)
+		pi := priorities[s.queue[i].GetName()]
+		pj := priorities[s.queue[j].GetName()]
 		if pi == pj {
 			return s.queue[i].GetName() < s.queue[j].GetName()
 		}
@@ -393,6 +783,9 @@ func (s *Scheduler) GetCounts() (total, running, queued, pass, fail, warn int) {
 	queued = len(s.queue)
 
 	for _, check := range s.checks {
+		if check.Maintenance != "" {
+			continue
+		}
 		switch check.LastStatus {
 		case "pass":
 			pass++
@@ -406,43 +799,169 @@ func (s *Scheduler) GetCounts() (total, running, queued, pass, fail, warn int) {
 	return
 }
 
-func (s *Scheduler) handleCheckResult(checkName string, status string, duration time.Duration) {
+func (s *Scheduler) handleCheckResult(result Result) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
-	completedAt := time.Now().In(s.location)
+	checkName := result.CheckName
+	status := result.Status
+	duration := result.Duration()
+	if result.FinishedAt.IsZero() {
+		result.FinishedAt = time.Now()
+	}
+	completedAt := result.FinishedAt.In(s.location)
+	var transition *StatusTransition
 	if check, exists := s.checks[checkName]; exists {
-		check.LastStatus = status
+		if window := s.activeMaintenanceLocked(checkName, check, completedAt); window != nil {
+			result = maintenanceResult(result, window)
+			status = result.Status
+		}
+		if check.Running && check.Attempt < check.MaxAttempts && isFailureStatus(status) {
+			check.retryResult = copyResult(&result)
+			attempt, maxAttempts := check.Attempt, check.MaxAttempts
+			publisher := s.eventPublisher
+			s.mu.Unlock()
+			logger.Info("Check %s returned %s on attempt %d of %d, retrying", checkName, status, attempt, maxAttempts)
+			publishEvent(publisher, events.Event{
+				Type:    events.CheckRetrying,
+				Time:    result.FinishedAt,
+				Check:   checkName,
+				Status:  status,
+				Message: result.Message,
+				Data:    map[string]interface{}{"attempt": attempt, "max_attempts": maxAttempts},
+			})
+			return
+		}
+		if status != StatusSuppressed {
+			check.SuppressedBy = ""
+		}
+		// Maintenance results are recorded but leave the status and its
+		// pending threshold alone, so the end of a window does not alert.
+		previous := check.LastStatus
+		committed := previous
+		if status != StatusMaintenance {
+			committed = commitStatus(check, status, retryPolicy(check.Config))
+		}
+		check.LastStatus = committed
 		check.LastDuration = duration
+		check.LastResult = copyResult(&result)
+		if run, ok := s.oneShotRuns[checkName]; ok {
+			run.result = copyResult(&result)
+		}
+		recordRun(check, status, completedAt)
 		check.History = trimHistory(append(check.History, CheckHistoryEntry{
 			Status:      status,
 			CompletedAt: completedAt,
+			Duration:    duration,
 		}))
+		if isTransition(previous, committed) {
+			transition = &StatusTransition{
+				CheckName:      checkName,
+				Tags:           checkTags(check.Config),
+				PreviousStatus: previous,
+				Status:         committed,
+				Message:        result.Message,
+				Duration:       duration,
+				CompletedAt:    completedAt,
+			}
+		}
 	}
 
 	if s.resultLogger != nil {
-		if err := s.resultLogger.RecordResult(checkName, status, duration, completedAt); err != nil {
+		if err := s.resultLogger.RecordResult(result); err != nil {
 			logger.Error("Failed to persist state for %s: %v", checkName, err)
 		}
 	}
+	handler := s.transitionHandler
+	publisher := s.eventPublisher
+	s.mu.Unlock()
+
+	publishEvent(publisher, completedEvent(result))
+	if transition != nil && handler != nil {
+		logger.Info("Check %s changed status from %s to %s", checkName, transition.PreviousStatus, transition.Status)
+		handler.HandleTransition(*transition)
+	}
+}
+
+func completedEvent(result Result) events.Event {
+	data := map[string]interface{}{
+		"duration_ms": result.Duration().Milliseconds(),
+	}
+	if result.ExitCode != nil {
+		data["exit_code"] = *result.ExitCode
+	}
+	if result.Error != "" {
+		data["error"] = result.Error
+	}
+	return events.Event{
+		Type:    events.CheckCompleted,
+		Time:    result.FinishedAt,
+		Check:   result.CheckName,
+		Status:  result.Status,
+		Message: result.Message,
+		Data:    data,
+	}
+}
+
+// isTransition reports whether a status change should be announced. A check
+// that comes up passing after a restart is not a change worth alerting on.
+func isTransition(previous, current string) bool {
+	if previous == current || !isAlertStatus(current) {
+		return false
+	}
+	if !isAlertStatus(previous) {
+		return current != "pass"
+	}
+	return true
+}
+
+func isAlertStatus(status string) bool {
+	switch status {
+	case "pass", "warn", "fail", "error":
+		return true
+	default:
+		return false
+	}
+}
+
+func checkTags(config CheckConfig) []string {
+	tagged, ok := config.(TaggedCheckConfig)
+	if !ok {
+		return nil
+	}
+	tags := tagged.GetTags()
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make([]string, len(tags))
+	copy(out, tags)
+	return out
 }
 
 type CheckState struct {
 	LastStatus   string
 	LastDuration time.Duration
 	LastRun      time.Time
+	LastResult   *Result
+	Paused       bool
 	History      []CheckHistoryEntry
 }
 
+// ApplyState restores the persisted state when the daemon starts and plans
+// the first run of every check with its start policy.
 func (s *Scheduler) ApplyState(states map[string]CheckState) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for name, state := range states {
-		check, exists := s.checks[name]
+	now := time.Now().In(s.location)
+	for name, check := range s.checks {
+		state, exists := states[name]
 		if !exists {
+			check.planFirstRun(startPolicy(check), check.LastRun, now)
 			continue
 		}
+		if state.LastStatus == StatusMaintenance {
+			state.LastStatus = statusBeforeMaintenance(state.History)
+		}
 		if state.LastStatus != "" {
 			check.LastStatus = state.LastStatus
 		}
@@ -452,12 +971,70 @@ func (s *Scheduler) ApplyState(states map[string]CheckState) {
 		if !state.LastRun.IsZero() {
 			lastRun := state.LastRun
 			check.LastRun = &lastRun
-			if check.ScheduleType == ScheduleTypeInterval && check.Interval > 0 {
-				check.NextRun = lastRun.Add(check.Interval)
-			}
+		}
+		check.planFirstRun(startPolicy(check), check.LastRun, now)
+		check.Paused = state.Paused
+		if state.LastResult != nil {
+			check.LastResult = copyResult(state.LastResult)
 		}
 		if len(state.History) > 0 {
 			check.History = trimHistory(state.History)
+			restoreRunState(check)
+		}
+	}
+}
+
+// recordRun counts a completed run. Maintenance results are left out of the
+// counts and do not break a failure streak.
+func recordRun(check *ScheduledCheck, status string, completedAt time.Time) {
+	if status == StatusMaintenance {
+		return
+	}
+	if check.RunCounts == nil {
+		check.RunCounts = make(map[string]int64)
+	}
+	check.RunCounts[status]++
+	switch status {
+	case "pass":
+		check.ConsecutiveFailures = 0
+		check.LastSuccess = &completedAt
+	case "fail", "error":
+		check.ConsecutiveFailures++
+	default:
+		check.ConsecutiveFailures = 0
+	}
+}
+
+// statusBeforeMaintenance returns the latest restored status that was not
+// recorded during maintenance, or "" if there is none.
+func statusBeforeMaintenance(history []CheckHistoryEntry) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Status != StatusMaintenance {
+			return history[i].Status
+		}
+	}
+	return ""
+}
+
+// restoreRunState derives the consecutive failures and last success from the
+// restored history. Both are bounded by the history length.
+func restoreRunState(check *ScheduledCheck) {
+	check.ConsecutiveFailures = 0
+	for i := len(check.History) - 1; i >= 0; i-- {
+		status := check.History[i].Status
+		if status == StatusMaintenance {
+			continue
+		}
+		if status != "fail" && status != "error" {
+			break
+		}
+		check.ConsecutiveFailures++
+	}
+	for i := len(check.History) - 1; i >= 0; i-- {
+		if check.History[i].Status == "pass" {
+			completedAt := check.History[i].CompletedAt
+			check.LastSuccess = &completedAt
+			break
 		}
 	}
 }
@@ -2,8 +2,10 @@ package scheduler
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
+	_ "time/tzdata"
 )
 
 type MockCheckConfig struct {
@@ -52,32 +54,46 @@ func (m *IntervalMockCheckConfig) GetInterval() string {
 }
 
 type MockExecutor struct {
+	mu       sync.Mutex
 	executed []string
-	callback func(checkName string, status string, duration time.Duration)
+	callback func(result Result)
 }
 
 func (m *MockExecutor) Execute(check CheckConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.executed = append(m.executed, check.GetName())
 	return nil
 }
 
-func (m *MockExecutor) SetResultCallback(callback func(checkName string, status string, duration time.Duration)) {
+// Executed returns the names of the checks run so far. Checks run on the
+// scheduler's goroutines, so tests read them through the lock.
+func (m *MockExecutor) Executed() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.executed...)
+}
+
+func (m *MockExecutor) SetResultCallback(callback func(result Result)) {
 	m.callback = callback
 }
 
 type SlowExecutor struct {
+	mu       sync.Mutex
 	executed []string
 	blocker  chan struct{}
-	callback func(checkName string, status string, duration time.Duration)
+	callback func(result Result)
 }
 
 func (m *SlowExecutor) Execute(check CheckConfig) error {
+	m.mu.Lock()
 	m.executed = append(m.executed, check.GetName())
+	m.mu.Unlock()
 	<-m.blocker
 	return nil
 }
 
-func (m *SlowExecutor) SetResultCallback(callback func(checkName string, status string, duration time.Duration)) {
+func (m *SlowExecutor) SetResultCallback(callback func(result Result)) {
 	m.callback = callback
 }
 
@@ -119,7 +135,7 @@ func TestParseCronExpression(t *testing.T) {
 		},
 		{
 			name:    "invalid - too many fields",
-			expr:    "* * * * * *",
+			expr:    "* * * * * * *",
 			wantErr: true,
 		},
 		{
@@ -144,7 +160,7 @@ func TestParseCronExpression(t *testing.T) {
 		},
 		{
 			name:    "invalid day of week",
-			expr:    "* * * * 7",
+			expr:    "* * * * 8",
 			wantErr: true,
 		},
 	}
@@ -207,6 +223,75 @@ func TestCronExpressionNext(t *testing.T) {
 	}
 }
 
+func TestCronExpressionNextDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	cest := time.FixedZone("CEST", 2*3600)
+	cet := time.FixedZone("CET", 3600)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "skipped time runs once after spring forward",
+			expr:  "30 2 * * *",
+			after: time.Date(2025, 3, 29, 12, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2025, 3, 30, 3, 0, 0, 0, cest),
+				time.Date(2025, 3, 31, 2, 30, 0, 0, cest),
+			},
+		},
+		{
+			name:  "repeated time runs once after fall back",
+			expr:  "30 2 * * *",
+			after: time.Date(2025, 10, 25, 12, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2025, 10, 26, 2, 30, 0, 0, cest),
+				time.Date(2025, 10, 27, 2, 30, 0, 0, cet),
+			},
+		},
+		{
+			name:  "repeated time after restart in the second pass",
+			expr:  "45 2 * * *",
+			after: time.Date(2025, 10, 26, 2, 10, 0, 0, cet),
+			want: []time.Time{
+				time.Date(2025, 10, 27, 2, 45, 0, 0, cet),
+			},
+		},
+		{
+			name:  "wildcard hours follow elapsed time",
+			expr:  "*/30 * * * *",
+			after: time.Date(2025, 10, 26, 2, 10, 0, 0, cest),
+			want: []time.Time{
+				time.Date(2025, 10, 26, 2, 30, 0, 0, cest),
+				time.Date(2025, 10, 26, 2, 0, 0, 0, cet),
+				time.Date(2025, 10, 26, 2, 30, 0, 0, cet),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCronExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("Failed to parse cron expression: %v", err)
+			}
+			next := tt.after.In(berlin)
+			for i, want := range tt.want {
+				next = cron.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("run %d: Next() = %v, want %v", i, next, want)
+				}
+			}
+		})
+	}
+}
+
 func TestSchedulerAddCheck(t *testing.T) {
 	executor := &MockExecutor{}
 	scheduler := NewScheduler(executor, time.UTC, 0)
@@ -267,7 +352,7 @@ func TestSchedulerHistoryTrim(t *testing.T) {
 		if i%2 == 1 {
 			status = "fail"
 		}
-		scheduler.handleCheckResult(check.name, status, time.Second)
+		scheduler.handleCheckResult(testResult(check.name, status, time.Second))
 	}
 
 	checkStatus, exists := scheduler.GetCheckStatus(check.name)
@@ -347,7 +432,7 @@ func TestSchedulerDisabledCheck(t *testing.T) {
 	time.Sleep(150 * time.Millisecond)
 	scheduler.Stop()
 
-	if len(executor.executed) > 0 {
+	if len(executor.Executed()) > 0 {
 		t.Error("Disabled check should not be executed")
 	}
 }
@@ -364,7 +449,7 @@ func TestCronFieldMatches(t *testing.T) {
 			field: CronField{
 				min:    0,
 				max:    59,
-				values: map[int]bool{0: true, 5: true, 10: true},
+				values: 1<<0 | 1<<5 | 1<<10,
 			},
 			value: 5,
 			want:  true,
@@ -374,7 +459,7 @@ func TestCronFieldMatches(t *testing.T) {
 			field: CronField{
 				min:    0,
 				max:    59,
-				values: map[int]bool{0: true, 5: true, 10: true},
+				values: 1<<0 | 1<<5 | 1<<10,
 			},
 			value: 15,
 			want:  false,
@@ -430,6 +515,35 @@ func TestTimeZones(t *testing.T) {
 	}
 }
 
+type ZonedMockCheckConfig struct {
+	MockCheckConfig
+	timezone string
+}
+
+func (m *ZonedMockCheckConfig) GetTimezone() string {
+	return m.timezone
+}
+
+func TestCheckTimeZone(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	check := &ZonedMockCheckConfig{MockCheckConfig{name: "tokyo", schedule: "0 9 * * *", enabled: true}, "Asia/Tokyo"}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	status, _ := scheduler.GetCheckStatus("tokyo")
+	if next := status.NextRun.In(status.Location); next.Hour() != 9 || next.Minute() != 0 || status.Location.String() != "Asia/Tokyo" {
+		t.Errorf("NextRun = %v in %v, want 09:00 Asia/Tokyo", next, status.Location)
+	}
+	if got := scheduler.Snapshot().Checks["tokyo"].Timezone; got != "Asia/Tokyo" {
+		t.Errorf("snapshot timezone = %q", got)
+	}
+
+	invalid := &ZonedMockCheckConfig{MockCheckConfig{name: "bad", schedule: "0 9 * * *", enabled: true}, "Mars/Olympus"}
+	if err := scheduler.AddCheck(invalid); err == nil {
+		t.Errorf("AddCheck() should reject an unknown time zone")
+	}
+}
+
 func TestConcurrencyLimit(t *testing.T) {
 	e := &SlowExecutor{
 		executed: []string{},
@@ -642,7 +756,7 @@ func (b *BlockingExecutor) Execute(check CheckConfig) error {
 	return nil
 }
 
-func (b *BlockingExecutor) SetResultCallback(callback func(checkName string, status string, duration time.Duration)) {
+func (b *BlockingExecutor) SetResultCallback(callback func(result Result)) {
 }
 
 func TestDueCheckPriorityOrder(t *testing.T) {
@@ -807,6 +921,28 @@ func TestMixedCronAndIntervalScheduling(t *testing.T) {
 	}
 }
 
+func TestScheduleNextRunUsesCallerClock(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	scheduler := NewScheduler(&MockExecutor{}, location, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "report", schedule: "0 9 * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	check, _ := scheduler.GetCheckStatus("report")
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, location)
+	scheduler.mu.Lock()
+	scheduler.scheduleNextRunLocked(check, now)
+	scheduler.mu.Unlock()
+
+	want := time.Date(2025, 6, 3, 9, 0, 0, 0, location)
+	if !check.NextRun.Equal(want) {
+		t.Errorf("NextRun = %v, want %v", check.NextRun, want)
+	}
+}
+
 func TestApplyStateUpdatesIntervalNextRun(t *testing.T) {
 	executor := &MockExecutor{}
 	scheduler := NewScheduler(executor, time.UTC, 0)
@@ -843,3 +979,301 @@ func TestApplyStateUpdatesIntervalNextRun(t *testing.T) {
 		t.Fatalf("expected last status pass, got %s", status.LastStatus)
 	}
 }
+
+type TaggedMockCheckConfig struct {
+	MockCheckConfig
+	tags []string
+}
+
+func (m *TaggedMockCheckConfig) GetTags() []string {
+	return m.tags
+}
+
+type recordingTransitionHandler struct {
+	transitions []StatusTransition
+}
+
+func (r *recordingTransitionHandler) HandleTransition(transition StatusTransition) {
+	r.transitions = append(r.transitions, transition)
+}
+
+func TestHandleCheckResultReportsTransitions(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+
+	check := &TaggedMockCheckConfig{
+		MockCheckConfig: MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true},
+		tags:            []string{"prod"},
+	}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	for _, status := range []string{"pass", "pass", "fail", "fail", "error", "pass"} {
+		scheduler.handleCheckResult(testResult("api", status, time.Second))
+	}
+
+	want := [][2]string{{"pass", "fail"}, {"fail", "error"}, {"error", "pass"}}
+	if len(handler.transitions) != len(want) {
+		t.Fatalf("transitions = %+v, want %d entries", handler.transitions, len(want))
+	}
+	for i, tr := range handler.transitions {
+		if tr.PreviousStatus != want[i][0] || tr.Status != want[i][1] {
+			t.Fatalf("transition %d = %s -> %s, want %s -> %s", i, tr.PreviousStatus, tr.Status, want[i][0], want[i][1])
+		}
+		if len(tr.Tags) != 1 || tr.Tags[0] != "prod" {
+			t.Fatalf("transition %d tags = %v, want [prod]", i, tr.Tags)
+		}
+	}
+}
+
+func TestHandleCheckResultInitialFailureIsTransition(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	handler := &recordingTransitionHandler{}
+	scheduler.SetTransitionHandler(handler)
+
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "db", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	scheduler.handleCheckResult(testResult("db", "fail", time.Second))
+
+	if len(handler.transitions) != 1 || handler.transitions[0].PreviousStatus != "unknown" {
+		t.Fatalf("transitions = %+v, want unknown -> fail", handler.transitions)
+	}
+}
+
+func testResult(checkName, status string, duration time.Duration) Result {
+	finished := time.Now()
+	return Result{
+		CheckName:  checkName,
+		Status:     status,
+		StartedAt:  finished.Add(-duration),
+		FinishedAt: finished,
+	}
+}
+
+type recordingResultLogger struct {
+	results []Result
+}
+
+func (r *recordingResultLogger) RecordResult(result Result) error {
+	r.results = append(r.results, result)
+	return nil
+}
+
+func TestHandleCheckResultKeepsFullResult(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+	resultLogger := &recordingResultLogger{}
+	scheduler.SetResultLogger(resultLogger)
+
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "tls", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	exitCode := 0
+	result := testResult("tls", "fail", 2*time.Second)
+	result.Message = "certificate expires in 3 days"
+	result.Data = map[string]interface{}{"days_remaining": float64(3)}
+	result.ExitCode = &exitCode
+	result.Image = "ghcr.io/pfarrer/foghorn-openssl-check:1.0.0"
+	scheduler.handleCheckResult(result)
+
+	check, _ := scheduler.GetCheckStatus("tls")
+	if check.LastResult == nil || check.LastResult.Message != result.Message {
+		t.Fatalf("LastResult = %+v, want message %q", check.LastResult, result.Message)
+	}
+	if check.LastDuration != 2*time.Second {
+		t.Fatalf("LastDuration = %v, want 2s", check.LastDuration)
+	}
+
+	status := scheduler.Snapshot().Checks["tls"]
+	if status.LastResult == nil || status.LastResult.Data["days_remaining"] != float64(3) {
+		t.Fatalf("snapshot LastResult = %+v, want data", status.LastResult)
+	}
+	if status.LastResult.ExitCode == nil || *status.LastResult.ExitCode != 0 {
+		t.Fatalf("snapshot ExitCode = %v, want 0", status.LastResult.ExitCode)
+	}
+
+	if len(resultLogger.results) != 1 || resultLogger.results[0].Image != result.Image {
+		t.Fatalf("recorded results = %+v, want one result with image", resultLogger.results)
+	}
+}
+
+func TestSchedulerRemoveCheckDropsQueuedRun(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 1)
+
+	check := &MockCheckConfig{name: "queued-check", schedule: "* * * * *", enabled: true}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	scheduler.mu.Lock()
+	scheduler.queue = append(scheduler.queue, check)
+	scheduler.mu.Unlock()
+
+	scheduler.RemoveCheck("queued-check")
+
+	if _, _, queued, _, _, _ := scheduler.GetCounts(); queued != 0 {
+		t.Fatalf("queued = %d, want 0 after removal", queued)
+	}
+}
+
+func TestSchedulerReconcile(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+
+	for _, check := range []CheckConfig{
+		&MockCheckConfig{name: "keep", schedule: "*/5 * * * *", enabled: true},
+		&MockCheckConfig{name: "change", schedule: "*/5 * * * *", enabled: true},
+		&MockCheckConfig{name: "drop", schedule: "*/5 * * * *", enabled: true},
+	} {
+		if err := scheduler.AddCheck(check); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+	scheduler.handleCheckResult(testResult("change", "fail", time.Second))
+
+	result, err := scheduler.Reconcile([]CheckConfig{
+		&MockCheckConfig{name: "keep", schedule: "*/5 * * * *", enabled: true},
+		&MockCheckConfig{name: "change", schedule: "0 * * * *", enabled: false},
+		&MockCheckConfig{name: "new", schedule: "*/5 * * * *", enabled: true},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if fmt.Sprint(result.Added) != "[new]" || fmt.Sprint(result.Updated) != "[change]" || fmt.Sprint(result.Removed) != "[drop]" {
+		t.Fatalf("Reconcile() = %+v, want added [new], updated [change], removed [drop]", result)
+	}
+
+	checks := scheduler.GetAllChecks()
+	if len(checks) != 3 {
+		t.Fatalf("checks = %d, want 3", len(checks))
+	}
+	changed := checks["change"]
+	if changed.Config.IsEnabled() || changed.Config.GetSchedule() != "0 * * * *" {
+		t.Fatalf("change config not updated: schedule %q enabled %v", changed.Config.GetSchedule(), changed.Config.IsEnabled())
+	}
+	if changed.LastStatus != "fail" || len(changed.History) != 1 {
+		t.Fatalf("change lost state: status %q history %d", changed.LastStatus, len(changed.History))
+	}
+	if changed.NextRun.Minute() != 0 {
+		t.Fatalf("change NextRun = %v, want top of the hour", changed.NextRun)
+	}
+}
+
+func TestSchedulerReconcileRejectsInvalidConfig(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "existing", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		configs []CheckConfig
+	}{
+		{
+			name: "invalid schedule",
+			configs: []CheckConfig{
+				&MockCheckConfig{name: "new", schedule: "not a cron", enabled: true},
+			},
+		},
+		{
+			name: "duplicate name",
+			configs: []CheckConfig{
+				&MockCheckConfig{name: "dup", schedule: "*/5 * * * *", enabled: true},
+				&MockCheckConfig{name: "dup", schedule: "*/5 * * * *", enabled: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scheduler.Reconcile(tt.configs); err == nil {
+				t.Fatalf("Reconcile() should fail")
+			}
+			checks := scheduler.GetAllChecks()
+			if _, ok := checks["existing"]; !ok || len(checks) != 1 {
+				t.Fatalf("running checks changed after rejected reconcile: %v", checks)
+			}
+		})
+	}
+}
+
+func TestSchedulerReconcileIntervalKeepsCadence(t *testing.T) {
+	executor := &MockExecutor{}
+	scheduler := NewScheduler(executor, time.UTC, 0)
+
+	check := &IntervalMockCheckConfig{name: "interval", enabled: true, interval: "1h"}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	lastRun := time.Now().Add(-10 * time.Minute)
+	scheduler.ApplyState(map[string]CheckState{"interval": {LastRun: lastRun}})
+
+	if _, err := scheduler.Reconcile([]CheckConfig{
+		&IntervalMockCheckConfig{name: "interval", enabled: true, interval: "30m"},
+	}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated, _ := scheduler.GetCheckStatus("interval")
+	if !updated.NextRun.Equal(lastRun.Add(30 * time.Minute)) {
+		t.Fatalf("NextRun = %v, want %v", updated.NextRun, lastRun.Add(30*time.Minute))
+	}
+}
+
+func TestHandleCheckResultTracksRunState(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	for _, status := range []string{"pass", "fail", "error"} {
+		scheduler.handleCheckResult(testResult("api", status, time.Second))
+	}
+
+	status := scheduler.Snapshot().Checks["api"]
+	if status.ConsecutiveFailures != 2 {
+		t.Fatalf("ConsecutiveFailures = %d, want 2", status.ConsecutiveFailures)
+	}
+	if status.LastSuccess == nil {
+		t.Fatalf("LastSuccess should be set after a pass")
+	}
+	if status.RunCounts["pass"] != 1 || status.RunCounts["fail"] != 1 || status.RunCounts["error"] != 1 {
+		t.Fatalf("RunCounts = %v, want one of each", status.RunCounts)
+	}
+
+	scheduler.handleCheckResult(testResult("api", "warn", time.Second))
+	if failures := scheduler.Snapshot().Checks["api"].ConsecutiveFailures; failures != 0 {
+		t.Fatalf("ConsecutiveFailures after warn = %d, want 0", failures)
+	}
+}
+
+func TestApplyStateRestoresRunState(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	if err := scheduler.AddCheck(&MockCheckConfig{name: "api", schedule: "*/5 * * * *", enabled: true}); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+	scheduler.ApplyState(map[string]CheckState{"api": {History: []CheckHistoryEntry{
+		{Status: "pass", CompletedAt: now.Add(-3 * time.Minute)},
+		{Status: "fail", CompletedAt: now.Add(-2 * time.Minute)},
+		{Status: "error", CompletedAt: now.Add(-1 * time.Minute)},
+	}}})
+
+	check, _ := scheduler.GetCheckStatus("api")
+	if check.ConsecutiveFailures != 2 {
+		t.Fatalf("ConsecutiveFailures = %d, want 2", check.ConsecutiveFailures)
+	}
+	if check.LastSuccess == nil || !check.LastSuccess.Equal(now.Add(-3*time.Minute)) {
+		t.Fatalf("LastSuccess = %v, want %v", check.LastSuccess, now.Add(-3*time.Minute))
+	}
+}
@@ -7,27 +7,52 @@ type Snapshot struct {
 	StartedAt   time.Time              `json:"started_at"`
 	Counts      SnapshotCounts         `json:"counts"`
 	Checks      map[string]CheckStatus `json:"checks"`
+	Maintenance []MaintenanceStatus    `json:"maintenance,omitempty"`
 }
 
 type SnapshotCounts struct {
-	Total   int `json:"total"`
-	Running int `json:"running"`
-	Queued  int `json:"queued"`
-	Pass    int `json:"pass"`
-	Fail    int `json:"fail"`
-	Warn    int `json:"warn"`
+	Total         int `json:"total"`
+	Running       int `json:"running"`
+	Queued        int `json:"queued"`
+	Pass          int `json:"pass"`
+	Fail          int `json:"fail"`
+	Warn          int `json:"warn"`
+	Maintenance   int `json:"maintenance,omitempty"`
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
 }
 
 type CheckStatus struct {
-	Name           string              `json:"name"`
-	NextRun        time.Time           `json:"next_run"`
-	LastRun        *time.Time          `json:"last_run,omitempty"`
-	LastStatus     string              `json:"last_status"`
-	LastDurationMs int64               `json:"last_duration_ms"`
-	Running        bool                `json:"running"`
-	Queued         bool                `json:"queued"`
-	ScheduleType   ScheduleType        `json:"schedule_type"`
-	History        []CheckHistoryEntry `json:"history,omitempty"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description,omitempty"`
+	Image               string              `json:"image,omitempty"`
+	Tags                []string            `json:"tags,omitempty"`
+	Enabled             bool                `json:"enabled"`
+	Schedule            string              `json:"schedule"`
+	NextRun             time.Time           `json:"next_run"`
+	NextRunDelayMs      int64               `json:"next_run_delay_ms,omitempty"`
+	LastRun             *time.Time          `json:"last_run,omitempty"`
+	LastStatus          string              `json:"last_status"`
+	LastDurationMs      int64               `json:"last_duration_ms"`
+	LastResult          *Result             `json:"last_result,omitempty"`
+	LastSuccess         *time.Time          `json:"last_success,omitempty"`
+	ConsecutiveFailures int                 `json:"consecutive_failures"`
+	RunCounts           map[string]int64    `json:"run_counts,omitempty"`
+	Running             bool                `json:"running"`
+	Paused              bool                `json:"paused"`
+	Queued              bool                `json:"queued"`
+	ScheduleType        ScheduleType        `json:"schedule_type"`
+	Timezone            string              `json:"timezone,omitempty"`
+	ScheduleWindow      string              `json:"schedule_window,omitempty"`
+	History             []CheckHistoryEntry `json:"history,omitempty"`
+	DependsOn           []string            `json:"depends_on,omitempty"`
+	SuppressedBy        string              `json:"suppressed_by,omitempty"`
+	Maintenance         string              `json:"maintenance,omitempty"`
+	PendingStatus       string              `json:"pending_status,omitempty"`
+	PendingCount        int                 `json:"pending_count,omitempty"`
+	PendingThreshold    int                 `json:"pending_threshold,omitempty"`
+	Pending             string              `json:"pending,omitempty"`
+	Attempt             int                 `json:"attempt,omitempty"`
+	MaxAttempts         int                 `json:"max_attempts,omitempty"`
 }
 
 func (s *Scheduler) Snapshot() Snapshot {
@@ -38,9 +63,10 @@ func (s *Scheduler) Snapshot() Snapshot {
 		GeneratedAt: time.Now().In(s.location),
 		StartedAt:   s.startTime,
 		Counts: SnapshotCounts{
-			Total:   len(s.checks),
-			Running: s.runningChecks,
-			Queued:  len(s.queue),
+			Total:         len(s.checks),
+			Running:       s.runningChecks,
+			Queued:        len(s.queue),
+			MaxConcurrent: s.maxConcurrentChecks,
 		},
 		Checks: make(map[string]CheckStatus, len(s.checks)),
 	}
@@ -48,16 +74,47 @@ func (s *Scheduler) Snapshot() Snapshot {
 	for name, check := range s.checks {
 		lastRun := copyTimePtr(check.LastRun)
 		history := copyHistory(check.History)
-		snapshot.Checks[name] = CheckStatus{
-			Name:           name,
-			NextRun:        check.NextRun,
-			LastRun:        lastRun,
-			LastStatus:     check.LastStatus,
-			LastDurationMs: check.LastDuration.Milliseconds(),
-			Running:        check.Running,
-			Queued:         check.IsQueued,
-			ScheduleType:   check.ScheduleType,
-			History:        history,
+		status := CheckStatus{
+			Name:                name,
+			Tags:                checkTags(check.Config),
+			Enabled:             check.Config.IsEnabled(),
+			Schedule:            check.Config.GetSchedule(),
+			NextRun:             check.NextRun,
+			NextRunDelayMs:      check.Delay.Milliseconds(),
+			LastRun:             lastRun,
+			LastStatus:          check.LastStatus,
+			LastDurationMs:      check.LastDuration.Milliseconds(),
+			LastResult:          copyResult(check.LastResult),
+			LastSuccess:         copyTimePtr(check.LastSuccess),
+			ConsecutiveFailures: check.ConsecutiveFailures,
+			RunCounts:           copyRunCounts(check.RunCounts),
+			Running:             check.Running,
+			Paused:              check.Paused,
+			Queued:              check.IsQueued,
+			ScheduleType:        check.ScheduleType,
+			ScheduleWindow:      check.Window,
+			History:             history,
+			DependsOn:           append([]string(nil), checkDependencies(check.Config)...),
+			SuppressedBy:        check.SuppressedBy,
+			Maintenance:         check.Maintenance,
+			PendingStatus:       check.PendingStatus,
+			PendingCount:        check.PendingCount,
+			PendingThreshold:    check.PendingThreshold,
+			Pending:             check.PendingLabel(),
+			Attempt:             check.Attempt,
+			MaxAttempts:         check.MaxAttempts,
+		}
+		if check.Location != nil {
+			status.Timezone = check.Location.String()
+		}
+		if described, ok := check.Config.(DescribedCheckConfig); ok {
+			status.Description = described.GetDescription()
+			status.Image = described.GetImage()
+		}
+		snapshot.Checks[name] = status
+		if check.Maintenance != "" {
+			snapshot.Counts.Maintenance++
+			continue
 		}
 		switch check.LastStatus {
 		case "pass":
@@ -68,6 +125,11 @@ func (s *Scheduler) Snapshot() Snapshot {
 			snapshot.Counts.Warn++
 		}
 	}
+	for _, windows := range [][]*MaintenanceWindow{s.windows, s.silences} {
+		for _, w := range windows {
+			snapshot.Maintenance = append(snapshot.Maintenance, w.status(snapshot.GeneratedAt))
+		}
+	}
 
 	return snapshot
 }
@@ -80,6 +142,17 @@ func copyTimePtr(t *time.Time) *time.Time {
 	return &v
 }
 
+func copyRunCounts(counts map[string]int64) map[string]int64 {
+	if len(counts) == 0 {
+		return nil
+	}
+	out := make(map[string]int64, len(counts))
+	for status, count := range counts {
+		out[status] = count
+	}
+	return out
+}
+
 func copyHistory(entries []CheckHistoryEntry) []CheckHistoryEntry {
 	if len(entries) == 0 {
 		return nil
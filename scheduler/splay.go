@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// SplayCheckConfig is a check that spreads its runs. Splay offsets the runs
+// of an interval check by a fixed amount hashed from its name, jitter delays
+// every run of a cron check by a random amount.
+type SplayCheckConfig interface {
+	CheckConfig
+	GetSplay() time.Duration
+	GetJitter() time.Duration
+}
+
+func scheduleSpread(config CheckConfig) (splay, jitter time.Duration) {
+	if spread, ok := config.(SplayCheckConfig); ok {
+		return max(spread.GetSplay(), 0), max(spread.GetJitter(), 0)
+	}
+	return 0, 0
+}
+
+// splayOffset returns an offset in [0, bound) derived from the check name, so
+// a check keeps its offset across restarts and reloads.
+func splayOffset(name string, bound time.Duration) time.Duration {
+	if bound < time.Millisecond {
+		return 0
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return time.Duration(hash.Sum64()%uint64(bound/time.Millisecond)) * time.Millisecond
+}
+
+// jitterDelay returns a random delay in [0, bound).
+func jitterDelay(bound time.Duration) time.Duration {
+	if bound < time.Millisecond {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(bound/time.Millisecond))) * time.Millisecond
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type SplayMockCheckConfig struct {
+	IntervalMockCheckConfig
+	splay time.Duration
+}
+
+func (m *SplayMockCheckConfig) GetSplay() time.Duration  { return m.splay }
+func (m *SplayMockCheckConfig) GetJitter() time.Duration { return 0 }
+
+type JitterMockCheckConfig struct {
+	MockCheckConfig
+	jitter time.Duration
+}
+
+func (m *JitterMockCheckConfig) GetSplay() time.Duration  { return 0 }
+func (m *JitterMockCheckConfig) GetJitter() time.Duration { return m.jitter }
+
+func TestSplayOffset(t *testing.T) {
+	if splayOffset("api", 30*time.Second) != splayOffset("api", 30*time.Second) {
+		t.Fatalf("splay offset should be deterministic")
+	}
+	if got := splayOffset("api", 0); got != 0 {
+		t.Errorf("splayOffset() without a bound = %v", got)
+	}
+
+	seconds := make(map[time.Duration]bool)
+	for i := range 40 {
+		offset := splayOffset(fmt.Sprintf("check-%02d", i), 40*time.Second)
+		if offset < 0 || offset >= 40*time.Second {
+			t.Fatalf("offset %v out of range", offset)
+		}
+		seconds[offset.Truncate(time.Second)] = true
+	}
+	if len(seconds) < 20 {
+		t.Errorf("40 checks share %d distinct seconds, want them spread", len(seconds))
+	}
+}
+
+func TestIntervalSplay(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	check := &SplayMockCheckConfig{IntervalMockCheckConfig{name: "api", interval: "10s", enabled: true}, time.Minute}
+	before := time.Now()
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	status, _ := scheduler.GetCheckStatus("api")
+	want := splayOffset("api", 10*time.Second)
+	if status.Splay != want || status.Delay != want {
+		t.Fatalf("splay = %v, delay = %v, want %v bounded by the interval", status.Splay, status.Delay, want)
+	}
+	if status.NextRun.Before(before.Add(want)) || status.NextRun.After(time.Now().Add(want)) {
+		t.Errorf("NextRun = %v, want about now + %v", status.NextRun, want)
+	}
+	if got := scheduler.Snapshot().Checks["api"].NextRunDelayMs; got != want.Milliseconds() {
+		t.Errorf("snapshot delay = %d, want %d", got, want.Milliseconds())
+	}
+
+	scheduler.mu.Lock()
+	scheduler.scheduleNextRunLocked(status, time.Now())
+	scheduler.mu.Unlock()
+	if status.Delay != 0 {
+		t.Errorf("later runs keep the cadence, delay = %v", status.Delay)
+	}
+
+	lastRun := time.Now().Add(-time.Hour)
+	scheduler.ApplyState(map[string]CheckState{"api": {LastStatus: "pass", LastRun: lastRun}})
+	if status.Delay != want || status.NextRun.Before(time.Now()) {
+		t.Errorf("an overdue check should keep its splay after a restart: next %v, delay %v", status.NextRun, status.Delay)
+	}
+}
+
+func TestCronJitter(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	check := &JitterMockCheckConfig{MockCheckConfig{name: "report", schedule: "0 * * * *", enabled: true}, 10 * time.Minute}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+
+	for range 5 {
+		status, _ := scheduler.GetCheckStatus("report")
+		slot := status.NextRun.Add(-status.Delay)
+		if slot.Minute() != 0 || slot.Second() != 0 || status.Delay < 0 || status.Delay >= 10*time.Minute {
+			t.Fatalf("NextRun = %v with delay %v, want the full hour plus less than 10m", status.NextRun, status.Delay)
+		}
+		scheduler.mu.Lock()
+		scheduler.scheduleNextRunLocked(status, time.Now())
+		scheduler.mu.Unlock()
+	}
+}
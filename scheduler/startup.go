@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/pfarrer/foghorn/logger"
+)
+
+const (
+	OnStartRun       = "run"
+	OnStartSkip      = "skip"
+	OnStartIfOverdue = "if_overdue"
+
+	CatchUpNone = "none"
+	CatchUpOnce = "once"
+)
+
+// StartPolicy controls the first run of a check after the daemon starts.
+// OnStart runs the check right away, skips to its next slot or runs it only
+// when it is overdue. CatchUp decides whether a slot missed while the daemon
+// was down makes the check overdue.
+type StartPolicy struct {
+	OnStart string
+	CatchUp string
+}
+
+type StartPolicyCheckConfig interface {
+	CheckConfig
+	GetStartPolicy() StartPolicy
+}
+
+// startPolicy returns the configured policy of a check.
+func startPolicy(check *ScheduledCheck) StartPolicy {
+	var policy StartPolicy
+	if starting, ok := check.Config.(StartPolicyCheckConfig); ok {
+		policy = starting.GetStartPolicy()
+	}
+	return policy.withDefaults(check.ScheduleType)
+}
+
+// withDefaults fills in the defaults. Checks run when they are overdue.
+// Interval checks catch up a missed run, cron checks wait for their next
+// slot.
+func (p StartPolicy) withDefaults(scheduleType ScheduleType) StartPolicy {
+	if p.OnStart == "" {
+		p.OnStart = OnStartIfOverdue
+	}
+	if p.CatchUp == "" {
+		p.CatchUp = CatchUpNone
+		if scheduleType == ScheduleTypeInterval {
+			p.CatchUp = CatchUpOnce
+		}
+	}
+	return p
+}
+
+// slots returns the first slot after the last run (due) and the first slot
+// after now (next), continuing from the last run, with the delays a run at
+// either time gets. due is zero when nothing is due yet. An interval check
+// that never ran is due right away.
+func (c *ScheduledCheck) slots(lastRun *time.Time, now time.Time) (due, next time.Time, delay, nextDelay time.Duration) {
+	if len(c.windows) > 0 {
+		rule, _, ok := c.ruleAt(now)
+		if ok && rule.cron != nil {
+			delay = jitterDelay(c.Jitter)
+		}
+		if lastRun != nil {
+			due, _ = c.nextWindowedRun(*lastRun, *lastRun)
+		} else if ok && rule.interval > 0 {
+			due = now
+		}
+		// Outside all windows, the next window starts with a run.
+		basis := now
+		if !ok {
+			basis = time.Time{}
+		}
+		next, nextDelay = c.scheduleWindowedRun(basis, now)
+		return due, next, delay, nextDelay
+	}
+	if c.ScheduleType == ScheduleTypeInterval && c.Interval > 0 {
+		delay = c.Splay
+		if lastRun == nil {
+			return now, now.Add(c.Interval + delay), delay, delay
+		}
+		due = lastRun.Add(c.Interval)
+		next = due
+		if !next.After(now) {
+			next = next.Add((now.Sub(due)/c.Interval + 1) * c.Interval)
+		}
+		return due, next, delay, 0
+	}
+	delay = jitterDelay(c.Jitter)
+	if lastRun != nil {
+		due = c.nextCronRun(*lastRun)
+	}
+	nextDelay = jitterDelay(c.Jitter)
+	return due, c.nextCronRun(now).Add(nextDelay), delay, nextDelay
+}
+
+// planFirstRun sets the first run of a check from its last run, nil for
+// checks that never ran, following policy. Only a slot that passed since the
+// last run counts as missed.
+func (c *ScheduledCheck) planFirstRun(policy StartPolicy, lastRun *time.Time, now time.Time) {
+	due, next, delay, nextDelay := c.slots(lastRun, now)
+	overdue := !due.IsZero() && !due.After(now)
+	missed := overdue && lastRun != nil
+	run := policy.OnStart == OnStartRun ||
+		policy.OnStart == OnStartIfOverdue && overdue && (!missed || policy.CatchUp == CatchUpOnce)
+	switch {
+	case run && overdue && delay == 0:
+		// Keep the missed slot, so the most overdue checks run first.
+		c.NextRun, c.Delay = due, 0
+	case run:
+		c.NextRun, c.Delay = now.Add(delay), delay
+	default:
+		c.NextRun, c.Delay = next, nextDelay
+	}
+
+	if missed {
+		name := c.Config.GetName()
+		if run {
+			logger.Info("Check %s missed its run at %s, running it now", name, due.Format(time.RFC3339))
+		} else {
+			logger.Info("Check %s missed its run at %s, next run: %s", name, due.Format(time.RFC3339), c.NextRun.Format(time.RFC3339))
+		}
+	}
+}
+
+// reschedule sets the next run after a reload changed the schedule. It
+// continues from the last run on the new schedule and skips slots that
+// already passed; the start policies only apply when the daemon starts.
+func (c *ScheduledCheck) reschedule(now time.Time) {
+	_, c.NextRun, _, c.Delay = c.slots(c.LastRun, now)
+}
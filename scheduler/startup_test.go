@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+type StartMockCheckConfig struct {
+	IntervalMockCheckConfig
+	policy StartPolicy
+}
+
+func (m *StartMockCheckConfig) GetStartPolicy() StartPolicy { return m.policy }
+
+type CronStartMockCheckConfig struct {
+	MockCheckConfig
+	policy StartPolicy
+}
+
+func (m *CronStartMockCheckConfig) GetStartPolicy() StartPolicy { return m.policy }
+
+func TestPlanFirstRun(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) *time.Time {
+		t := time.Date(2026, 3, day, hour, minute, 5, 0, time.UTC)
+		return &t
+	}
+	cron := func(policy StartPolicy) CheckConfig {
+		return &CronStartMockCheckConfig{MockCheckConfig{name: "certs", schedule: "0 3 * * *", enabled: true}, policy}
+	}
+	interval := func(policy StartPolicy) CheckConfig {
+		return &StartMockCheckConfig{IntervalMockCheckConfig{name: "api", schedule: "1h", interval: "1h", enabled: true}, policy}
+	}
+
+	tests := []struct {
+		name    string
+		config  CheckConfig
+		lastRun *time.Time
+		want    time.Time
+	}{
+		{"cron drops a missed slot by default", cron(StartPolicy{}), at(8, 3, 0), time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{"cron catches up once", cron(StartPolicy{CatchUp: CatchUpOnce}), at(7, 3, 0), time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)},
+		{"cron ran its last slot", cron(StartPolicy{CatchUp: CatchUpOnce}), at(10, 3, 0), time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{"cron without a last run waits", cron(StartPolicy{CatchUp: CatchUpOnce}), nil, time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{"cron runs on start", cron(StartPolicy{OnStart: OnStartRun}), at(10, 3, 0), now},
+		{"interval catches up once by default", interval(StartPolicy{}), at(10, 6, 0), at(10, 7, 0).Round(time.Second)},
+		{"interval drops missed runs", interval(StartPolicy{CatchUp: CatchUpNone}), at(10, 5, 50), at(10, 9, 50).Round(time.Second)},
+		{"interval that never ran is due", interval(StartPolicy{CatchUp: CatchUpNone}), nil, now},
+		{"interval skips the start", interval(StartPolicy{OnStart: OnStartSkip}), nil, now.Add(time.Hour)},
+		{"interval runs on start", interval(StartPolicy{OnStart: OnStartRun}), at(10, 8, 30), now},
+		{"interval keeps its slot", interval(StartPolicy{OnStart: OnStartIfOverdue}), at(10, 8, 30), at(10, 9, 30).Round(time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+			check, err := scheduler.newScheduledCheck(tt.config)
+			if err != nil {
+				t.Fatalf("newScheduledCheck() error = %v", err)
+			}
+			check.planFirstRun(startPolicy(check), tt.lastRun, now)
+			if !check.NextRun.Round(time.Second).Equal(tt.want) {
+				t.Errorf("NextRun = %v, want %v", check.NextRun, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyStateCatchesUpCron(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	check := &CronStartMockCheckConfig{MockCheckConfig{name: "certs", schedule: "0 3 * * *", enabled: true}, StartPolicy{CatchUp: CatchUpOnce}}
+	if err := scheduler.AddCheck(check); err != nil {
+		t.Fatalf("AddCheck() error = %v", err)
+	}
+	if status, _ := scheduler.GetCheckStatus("certs"); !status.NextRun.After(time.Now()) {
+		t.Fatalf("a cron check without a last run should wait for its slot, got %v", status.NextRun)
+	}
+
+	scheduler.ApplyState(map[string]CheckState{"certs": {LastStatus: "pass", LastRun: time.Now().Add(-49 * time.Hour)}})
+	status, _ := scheduler.GetCheckStatus("certs")
+	if status.NextRun.After(time.Now()) {
+		t.Errorf("a missed run should be caught up at startup, next run %v", status.NextRun)
+	}
+}
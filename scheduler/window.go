@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ScheduleWindow is a time of day range on some weekdays during which a check
+// runs on the window's interval or cron schedule instead of its own. Start
+// and End are offsets from midnight; a window whose End is before its Start
+// runs past midnight, and one without either covers whole days. Without Days
+// it applies every day.
+type ScheduleWindow struct {
+	Name     string
+	Days     []time.Weekday
+	Start    time.Duration
+	End      time.Duration
+	Timezone string
+	Interval string
+	Cron     string
+	rule     scheduleRule
+}
+
+type WindowedCheckConfig interface {
+	CheckConfig
+	GetScheduleWindows() []ScheduleWindow
+}
+
+// scheduleRule is the interval or cron schedule of a check or window.
+type scheduleRule struct {
+	interval time.Duration
+	cron     *CronExpression
+	location *time.Location
+}
+
+// maxWindowSearch bounds the search for the next run of a windowed check. A
+// cron schedule that rarely falls into its window, such as a leap day, can
+// take years.
+const maxWindowSearch = 8 * 366 * 24 * time.Hour
+
+func newScheduleWindows(config CheckConfig, location *time.Location) ([]*ScheduleWindow, error) {
+	windowed, ok := config.(WindowedCheckConfig)
+	if !ok {
+		return nil, nil
+	}
+	var windows []*ScheduleWindow
+	for i, w := range windowed.GetScheduleWindows() {
+		if w.Name == "" {
+			w.Name = fmt.Sprintf("window-%d", i+1)
+		}
+		w.rule.location = location
+		if w.Timezone != "" {
+			loc, err := time.LoadLocation(w.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("check %s: window %s: unknown time zone %q", config.GetName(), w.Name, w.Timezone)
+			}
+			w.rule.location = loc
+		}
+		var err error
+		switch {
+		case w.Interval != "" && w.Cron == "":
+			w.rule.interval, err = parseInterval(w.Interval)
+		case w.Cron != "" && w.Interval == "":
+			w.rule.cron, err = ParseCronExpression(w.Cron)
+		default:
+			err = fmt.Errorf("exactly one of cron or interval is required")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("check %s: window %s: %w", config.GetName(), w.Name, err)
+		}
+		windows = append(windows, &w)
+	}
+	return windows, nil
+}
+
+func (w *ScheduleWindow) onDay(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, day)
+}
+
+// activeAt reports whether the window covers t.
+func (w *ScheduleWindow) activeAt(t time.Time) bool {
+	local := t.In(w.rule.location)
+	hour, minute, second := local.Clock()
+	offset := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	switch {
+	case w.Start == w.End:
+		return w.onDay(local.Weekday())
+	case w.Start < w.End:
+		return offset >= w.Start && offset < w.End && w.onDay(local.Weekday())
+	default:
+		return offset >= w.Start && w.onDay(local.Weekday()) ||
+			offset < w.End && w.onDay((local.Weekday()+6)%7)
+	}
+}
+
+// nextBoundary returns the first start or end of the window's hours after t.
+// Whether the window is active can only change there.
+func (w *ScheduleWindow) nextBoundary(t time.Time) time.Time {
+	local := t.In(w.rule.location)
+	year, month, day := local.Date()
+	var next time.Time
+	for days := range 2 {
+		for _, offset := range []time.Duration{w.Start, w.End} {
+			boundary := time.Date(year, month, day+days, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, w.rule.location)
+			if boundary.After(t) && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+	return next
+}
+
+// ruleAt returns the schedule that applies at t and the window that selects
+// it, which is empty for the check's own schedule. ok is false outside all
+// windows of a check without a schedule of its own.
+func (c *ScheduledCheck) ruleAt(t time.Time) (rule scheduleRule, window string, ok bool) {
+	for _, w := range c.windows {
+		if w.activeAt(t) {
+			return w.rule, w.Name, true
+		}
+	}
+	switch c.ScheduleType {
+	case ScheduleTypeInterval:
+		return scheduleRule{interval: c.Interval}, "", c.Interval > 0
+	case ScheduleTypeCron:
+		return scheduleRule{cron: c.cron, location: c.Location}, "", c.cron != nil
+	}
+	return scheduleRule{}, "", false
+}
+
+// nextWindowedRun returns the first run at or after from of a check with
+// schedule windows, and the schedule it follows. Intervals count from
+// lastRun. When a window starts or ends, the schedule changes: an interval
+// runs right away if it has passed since the last run, and a cron schedule
+// runs at its next activation.
+func (c *ScheduledCheck) nextWindowedRun(lastRun, from time.Time) (time.Time, scheduleRule) {
+	limit := from.Add(maxWindowSearch)
+	for start := from; start.Before(limit); {
+		end := time.Time{}
+		for _, w := range c.windows {
+			if boundary := w.nextBoundary(start); end.IsZero() || boundary.Before(end) {
+				end = boundary
+			}
+		}
+		if rule, _, ok := c.ruleAt(start); ok {
+			var next time.Time
+			if rule.interval > 0 {
+				next = lastRun.Add(rule.interval)
+				if lastRun.IsZero() || next.Before(start) {
+					next = start
+				}
+			} else {
+				after := start
+				if start.After(from) {
+					// Boundaries fall on whole minutes, so this includes an
+					// activation right at the boundary.
+					after = start.Add(-time.Second)
+				}
+				next = rule.cron.Next(after.In(rule.location))
+			}
+			if !next.IsZero() && (end.IsZero() || next.Before(end)) {
+				return next, rule
+			}
+		}
+		if end.IsZero() {
+			break
+		}
+		start = end
+	}
+	return time.Time{}, scheduleRule{}
+}
+
+// scheduleWindowedRun returns the next run of a windowed check and its delay.
+// Runs that follow a cron schedule get the check's jitter.
+func (c *ScheduledCheck) scheduleWindowedRun(lastRun, from time.Time) (time.Time, time.Duration) {
+	next, rule := c.nextWindowedRun(lastRun, from)
+	if next.IsZero() || rule.cron == nil {
+		return next, 0
+	}
+	delay := jitterDelay(c.Jitter)
+	return next.Add(delay), delay
+}
+
+// updateWindowsLocked records the schedule window that is active for every
+// check.
+func (s *Scheduler) updateWindowsLocked(now time.Time) {
+	for _, check := range s.checks {
+		if len(check.windows) > 0 {
+			_, check.Window, _ = check.ruleAt(now)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/config"
+)
+
+type WindowedMockCheckConfig struct {
+	IntervalMockCheckConfig
+	windows []ScheduleWindow
+}
+
+func (m *WindowedMockCheckConfig) GetScheduleWindows() []ScheduleWindow { return m.windows }
+
+func (m *WindowedMockCheckConfig) GetScheduleType() ScheduleType {
+	if m.interval == "" {
+		return ScheduleTypeWindowed
+	}
+	return ScheduleTypeInterval
+}
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+func TestScheduleWindowActive(t *testing.T) {
+	business := &ScheduleWindow{Days: weekdays, Start: 8 * time.Hour, End: 18 * time.Hour, rule: scheduleRule{location: time.UTC}}
+	night := &ScheduleWindow{Days: []time.Weekday{time.Saturday}, Start: 22 * time.Hour, End: 6 * time.Hour, rule: scheduleRule{location: time.UTC}}
+	sunday := &ScheduleWindow{Days: []time.Weekday{time.Sunday}, rule: scheduleRule{location: time.UTC}}
+
+	// 2026-03-09 is a Monday.
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		window *ScheduleWindow
+		t      time.Time
+		want   bool
+	}{
+		{business, at(9, 8, 0), true},
+		{business, at(9, 17, 59), true},
+		{business, at(9, 18, 0), false},
+		{business, at(14, 12, 0), false},
+		{night, at(14, 23, 0), true},
+		{night, at(15, 5, 59), true},
+		{night, at(15, 23, 0), false},
+		{night, at(14, 5, 0), false},
+		{sunday, at(15, 0, 0), true},
+		{sunday, at(16, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.activeAt(tt.t); got != tt.want {
+			t.Errorf("window %v-%v on %v: activeAt(%v) = %v, want %v", tt.window.Start, tt.window.End, tt.window.Days, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestWindowedNextRun(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, second, 0, time.UTC)
+	}
+	businessHours := []ScheduleWindow{{Name: "business", Days: weekdays, Start: 8 * time.Hour, End: 18 * time.Hour, Interval: "1m"}}
+	tests := []struct {
+		name     string
+		interval string
+		windows  []ScheduleWindow
+		lastRun  time.Time
+		want     time.Time
+		window   string
+	}{
+		{"inside the window", "15m", businessHours, at(9, 9, 0, 0), at(9, 9, 1, 0), "business"},
+		{"entering the window", "15m", businessHours, at(9, 7, 50, 0), at(9, 8, 0, 0), "business"},
+		{"leaving the window", "15m", businessHours, at(9, 17, 59, 30), at(9, 18, 14, 30), ""},
+		{"weekend", "15m", businessHours, at(14, 10, 0, 0), at(14, 10, 15, 0), ""},
+		{"windows only", "", businessHours, at(13, 17, 59, 30), at(16, 8, 0, 0), "business"},
+		{"cron window", "", []ScheduleWindow{{Name: "weekend", Days: []time.Weekday{time.Saturday, time.Sunday}, Cron: "0 */6 * * *"}}, at(13, 20, 0, 0), at(14, 0, 0, 0), "weekend"},
+		{"window time zone", "1h", []ScheduleWindow{{Name: "ny", Start: 9 * time.Hour, End: 17 * time.Hour, Timezone: "America/New_York", Interval: "1m"}}, at(10, 13, 30, 0), at(10, 13, 31, 0), "ny"},
+		{"first window wins", "1h", append(businessHours, ScheduleWindow{Name: "day", Start: 6 * time.Hour, End: 20 * time.Hour, Interval: "5m"}), at(9, 19, 0, 0), at(9, 19, 5, 0), "day"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+			check, err := scheduler.newScheduledCheck(&WindowedMockCheckConfig{IntervalMockCheckConfig{name: "api", schedule: tt.interval, interval: tt.interval, enabled: true}, tt.windows})
+			if err != nil {
+				t.Fatalf("newScheduledCheck() error = %v", err)
+			}
+			next, _ := check.nextWindowedRun(tt.lastRun, tt.lastRun)
+			if !next.Equal(tt.want) {
+				t.Fatalf("next run = %v (%v), want %v", next, next.In(newYork), tt.want)
+			}
+			if _, window, _ := check.ruleAt(next); window != tt.window {
+				t.Errorf("window at %v = %q, want %q", next, window, tt.window)
+			}
+		})
+	}
+}
+
+func TestSnapshotReportsScheduleWindow(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	always := &WindowedMockCheckConfig{IntervalMockCheckConfig{name: "api", schedule: "15m", interval: "15m", enabled: true}, []ScheduleWindow{{Name: "always", Interval: "1m"}}}
+	never := &WindowedMockCheckConfig{IntervalMockCheckConfig{name: "db", schedule: "15m", interval: "15m", enabled: true}, []ScheduleWindow{{Name: "never", Days: []time.Weekday{time.Now().Add(48 * time.Hour).Weekday()}, Start: time.Hour, End: 2 * time.Hour, Interval: "1m"}}}
+	for _, check := range []CheckConfig{always, never} {
+		if err := scheduler.AddCheck(check); err != nil {
+			t.Fatalf("AddCheck() error = %v", err)
+		}
+	}
+	scheduler.mu.Lock()
+	scheduler.updateWindowsLocked(time.Now())
+	scheduler.mu.Unlock()
+
+	snapshot := scheduler.Snapshot()
+	if got := snapshot.Checks["api"].ScheduleWindow; got != "always" {
+		t.Errorf("api schedule window = %q, want always", got)
+	}
+	if got := snapshot.Checks["db"].ScheduleWindow; got != "" {
+		t.Errorf("db schedule window = %q, want none", got)
+	}
+}
+
+func TestValidateScheduleWindows(t *testing.T) {
+	cfg := &config.Config{Checks: []config.CheckConfig{{
+		Name:  "report",
+		Image: "test/image:1.0.0",
+		Schedule: config.Schedule{Windows: []config.ScheduleWindow{
+			{Name: "morning", Start: "08:00", End: "09:00", Cron: "30 10 * * *"},
+		}},
+	}}}
+	err := ValidateSchedules(cfg)
+	if err == nil || !strings.Contains(err.Error(), "check report: schedule never runs") {
+		t.Fatalf("ValidateSchedules() error = %v, want never runs", err)
+	}
+
+	cfg.Checks[0].Schedule.Windows[0].Cron = "30 8 * * *"
+	if err := ValidateSchedules(cfg); err != nil {
+		t.Fatalf("ValidateSchedules() error = %v", err)
+	}
+}
+
+func TestWindowedFirstRun(t *testing.T) {
+	scheduler := NewScheduler(&MockExecutor{}, time.UTC, 0)
+	now := time.Date(2026, 3, 9, 7, 55, 0, 0, time.UTC)
+	check, err := scheduler.newScheduledCheck(&WindowedMockCheckConfig{
+		IntervalMockCheckConfig{name: "api", enabled: true},
+		[]ScheduleWindow{{Name: "business", Days: weekdays, Start: 8 * time.Hour, End: 18 * time.Hour, Interval: "15m"}},
+	})
+	if err != nil {
+		t.Fatalf("newScheduledCheck() error = %v", err)
+	}
+
+	check.planFirstRun(startPolicy(check), nil, now)
+	if want := now.Add(5 * time.Minute); !check.NextRun.Equal(want) {
+		t.Errorf("outside the window: NextRun = %v, want the window start %v", check.NextRun, want)
+	}
+	check.planFirstRun(startPolicy(check), nil, now.Add(time.Hour))
+	if want := now.Add(time.Hour); !check.NextRun.Equal(want) {
+		t.Errorf("inside the window: NextRun = %v, want %v", check.NextRun, want)
+	}
+}
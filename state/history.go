@@ -0,0 +1,337 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 1000
+)
+
+// HistoryQuery selects the results of one check. Since and Until bound the
+// completion time, Statuses keeps only matching results, and Cursor continues
+// a previous page.
+type HistoryQuery struct {
+	Check    string
+	Since    time.Time
+	Until    time.Time
+	Statuses []string
+	Limit    int
+	Cursor   string
+}
+
+// HistoryPage lists results newest first. The summary covers every result in
+// the time range, independent of the status filter and the page.
+type HistoryPage struct {
+	Check      string         `json:"check"`
+	Since      time.Time      `json:"since,omitzero"`
+	Until      time.Time      `json:"until,omitzero"`
+	Entries    []Record       `json:"entries"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Summary    HistorySummary `json:"summary"`
+}
+
+type HistorySummary struct {
+	Runs           int     `json:"runs"`
+	Pass           int     `json:"pass"`
+	Warn           int     `json:"warn"`
+	Fail           int     `json:"fail"`
+	Error          int     `json:"error"`
+	Failures       int     `json:"failures"`
+	UptimePercent  float64 `json:"uptime_percent"`
+	MeanDurationMs int64   `json:"mean_duration_ms"`
+	P95DurationMs  int64   `json:"p95_duration_ms"`
+	Incidents      int     `json:"incidents"`
+	MTTRSeconds    float64 `json:"mttr_seconds"`
+	Failing        bool    `json:"failing"`
+}
+
+// History answers q from the index of the records within the retention
+// period. Only the records of the returned page are read from disk.
+func (s *StateLog) History(q HistoryQuery) (HistoryPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := time.Now().UTC().Add(-s.retention)
+	if q.Since.After(since) {
+		since = q.Since
+	}
+	var entries []indexEntry
+	for _, entry := range s.index[q.Check] {
+		if entry.completedAt.Before(since) {
+			continue
+		}
+		if !q.Until.IsZero() && !entry.completedAt.Before(q.Until) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].completedAt.Before(entries[j].completedAt)
+	})
+
+	inRange := make([]Record, len(entries))
+	for i, entry := range entries {
+		inRange[i] = Record{CheckName: q.Check, Status: entry.status, DurationMs: entry.durationMs, CompletedAt: entry.completedAt}
+	}
+	page, picked, err := pageHistory(inRange, q)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+	selected := make([]indexEntry, len(picked))
+	for i, p := range picked {
+		selected[i] = entries[p]
+	}
+	if page.Entries, err = s.readEntries(selected); err != nil {
+		return HistoryPage{}, err
+	}
+	return page, nil
+}
+
+// QueryHistory pages through the results of q.Check in records.
+func QueryHistory(records []Record, q HistoryQuery) (HistoryPage, error) {
+	var inRange []Record
+	for _, record := range records {
+		if record.CheckName != q.Check || !record.IsResult() {
+			continue
+		}
+		if !q.Since.IsZero() && record.CompletedAt.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !record.CompletedAt.Before(q.Until) {
+			continue
+		}
+		inRange = append(inRange, record)
+	}
+	sort.SliceStable(inRange, func(i, j int) bool {
+		return inRange[i].CompletedAt.Before(inRange[j].CompletedAt)
+	})
+	page, _, err := pageHistory(inRange, q)
+	return page, err
+}
+
+// pageHistory pages through results sorted by completion time. It also
+// returns the positions in inRange of the returned entries.
+func pageHistory(inRange []Record, q HistoryQuery) (HistoryPage, []int, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	limit = min(limit, MaxHistoryLimit)
+	before, skipped, err := parseCursor(q.Cursor)
+	if err != nil {
+		return HistoryPage{}, nil, err
+	}
+	skip := skipped
+
+	page := HistoryPage{
+		Check:   q.Check,
+		Since:   q.Since,
+		Until:   q.Until,
+		Entries: []Record{},
+		Summary: Summarize(inRange),
+	}
+	var picked []int
+	for i := len(inRange) - 1; i >= 0; i-- {
+		record := inRange[i]
+		if len(q.Statuses) > 0 && !containsStatus(q.Statuses, record.Status) {
+			continue
+		}
+		if !before.IsZero() {
+			if record.CompletedAt.After(before) {
+				continue
+			}
+			if record.CompletedAt.Equal(before) && skip > 0 {
+				skip--
+				continue
+			}
+		}
+		if len(page.Entries) == limit {
+			page.NextCursor = nextCursor(page.Entries, before, skipped)
+			break
+		}
+		page.Entries = append(page.Entries, record)
+		picked = append(picked, i)
+	}
+	return page, picked, nil
+}
+
+// A cursor is the completion time of the last returned entry in Unix
+// nanoseconds and how many returned entries share that time, so results with
+// equal timestamps are not skipped between pages.
+func parseCursor(cursor string) (time.Time, int, error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+	nanosText, skipText, _ := strings.Cut(cursor, ".")
+	nanos, err := strconv.ParseInt(nanosText, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	skip := 1
+	if skipText != "" {
+		if skip, err = strconv.Atoi(skipText); err != nil || skip < 1 {
+			return time.Time{}, 0, fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+	return time.Unix(0, nanos).UTC(), skip, nil
+}
+
+func nextCursor(entries []Record, before time.Time, skipped int) string {
+	last := entries[len(entries)-1].CompletedAt
+	count := 0
+	for i := len(entries) - 1; i >= 0 && entries[i].CompletedAt.Equal(last); i-- {
+		count++
+	}
+	if count == len(entries) && last.Equal(before) {
+		count += skipped
+	}
+	return fmt.Sprintf("%d.%d", last.UnixNano(), count)
+}
+
+// Summarize aggregates results sorted by completion time. Pass and warn
+// count as up; an incident starts with the first fail or error and ends with
+// the next pass or warn. Maintenance results are left out.
+func Summarize(records []Record) HistorySummary {
+	var summary HistorySummary
+	var durations []int64
+	var total int64
+	var incidentStart time.Time
+	var recovered int
+	var downtime time.Duration
+	for _, record := range records {
+		if record.Status == scheduler.StatusMaintenance {
+			continue
+		}
+		summary.Runs++
+		durations = append(durations, record.DurationMs)
+		total += record.DurationMs
+		switch record.Status {
+		case "pass":
+			summary.Pass++
+		case "warn":
+			summary.Warn++
+		case "fail":
+			summary.Fail++
+		case "error":
+			summary.Error++
+		}
+
+		if isFailure(record.Status) {
+			summary.Failures++
+			if incidentStart.IsZero() {
+				incidentStart = record.CompletedAt
+				summary.Incidents++
+			}
+			continue
+		}
+		if !incidentStart.IsZero() {
+			downtime += record.CompletedAt.Sub(incidentStart)
+			recovered++
+			incidentStart = time.Time{}
+		}
+	}
+	if summary.Runs == 0 {
+		return summary
+	}
+
+	summary.Failing = !incidentStart.IsZero()
+	summary.UptimePercent = roundTo(100*float64(summary.Runs-summary.Failures)/float64(summary.Runs), 2)
+	summary.MeanDurationMs = total / int64(summary.Runs)
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	summary.P95DurationMs = durations[int(math.Ceil(0.95*float64(len(durations))))-1]
+	if recovered > 0 {
+		summary.MTTRSeconds = roundTo(downtime.Seconds()/float64(recovered), 1)
+	}
+	return summary
+}
+
+func isFailure(status string) bool {
+	return status == "fail" || status == "error"
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
+
+// ParseTimeBound accepts an RFC 3339 time, or a duration before now such as
+// "90m", "24h" or "7d".
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n) * 24 * time.Hour).UTC(), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or a duration like 24h or 7d", value)
+}
+
+// ReadFile reads the state log without taking its lock, so it can be used
+// while the daemon is running. Segments removed by retention while they are
+// read are skipped, and a record still being written is ignored. SQLite state
+// databases are read as well.
+func ReadFile(path string) ([]Record, error) {
+	if isSQLite(path) {
+		return readSQLite(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("state log not found: %s", path)
+		}
+		return nil, err
+	}
+	records, err := parseSegment(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse state log: %w", err)
+	}
+
+	segments, err := listSegments(path + segmentDirSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, seg := range segments {
+		data, err := os.ReadFile(seg.path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		segmentRecords, err := parseSegment(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse state log: %w", err)
+		}
+		records = append(records, segmentRecords...)
+	}
+	return records, nil
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func historyRecords(start time.Time, statuses ...string) []Record {
+	records := make([]Record, 0, len(statuses))
+	for i, status := range statuses {
+		records = append(records, Record{
+			CheckName:   "api",
+			Status:      status,
+			DurationMs:  int64(100 * (i + 1)),
+			CompletedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return records
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Incidents at minutes 2-4 and 6-8 recover after two minutes each; the
+	// one at minute 19 is still open.
+	records := historyRecords(start, "pass", "pass", "fail", "error", "warn", "pass", "fail", "fail", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "fail")
+
+	summary := Summarize(records)
+	if summary.Runs != 20 || summary.Pass != 14 || summary.Warn != 1 || summary.Fail != 4 || summary.Error != 1 {
+		t.Fatalf("counts = %+v", summary)
+	}
+	if summary.Failures != 5 || summary.Incidents != 3 || !summary.Failing {
+		t.Fatalf("failures = %d incidents = %d failing = %v", summary.Failures, summary.Incidents, summary.Failing)
+	}
+	if summary.UptimePercent != 75 {
+		t.Fatalf("UptimePercent = %v, want 75", summary.UptimePercent)
+	}
+	if summary.MeanDurationMs != 1050 || summary.P95DurationMs != 1900 {
+		t.Fatalf("mean = %d p95 = %d, want 1050 and 1900", summary.MeanDurationMs, summary.P95DurationMs)
+	}
+	if summary.MTTRSeconds != 120 {
+		t.Fatalf("MTTRSeconds = %v, want 120", summary.MTTRSeconds)
+	}
+
+	maintenance := historyRecords(start, "pass", "maintenance", "maintenance", "fail", "pass")
+	if summary := Summarize(maintenance); summary.Runs != 3 || summary.UptimePercent != 66.67 {
+		t.Fatalf("maintenance results should be left out: %+v", summary)
+	}
+}
+
+func TestQueryHistoryPagination(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := historyRecords(start, "pass", "fail", "pass", "fail", "pass", "pass")
+	// Results with the same completion time must not be lost between pages.
+	records[4].CompletedAt = records[5].CompletedAt
+	records = append(records, Record{Kind: KindPause, CheckName: "api", CompletedAt: start}, Record{CheckName: "other", Status: "fail", CompletedAt: start})
+
+	var got []int64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		page, err := QueryHistory(records, HistoryQuery{Check: "api", Limit: 1, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("QueryHistory() error = %v", err)
+		}
+		if page.Summary.Runs != 6 {
+			t.Fatalf("summary should cover the whole range, got %d runs", page.Summary.Runs)
+		}
+		for _, entry := range page.Entries {
+			got = append(got, entry.DurationMs)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if pages > 10 {
+			t.Fatalf("pagination does not terminate")
+		}
+		cursor = page.NextCursor
+	}
+	if len(got) != 6 || got[0] != 600 || got[1] != 500 || got[5] != 100 {
+		t.Fatalf("durations newest first = %v", got)
+	}
+
+	page, err := QueryHistory(records, HistoryQuery{Check: "api", Statuses: []string{"fail"}, Since: start.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatalf("QueryHistory() error = %v", err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].DurationMs != 400 || page.Summary.Runs != 4 {
+		t.Fatalf("filtered page = %+v", page)
+	}
+
+	if _, err := QueryHistory(records, HistoryQuery{Check: "api", Cursor: "yesterday"}); err == nil {
+		t.Fatalf("invalid cursor should fail")
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"":                     {},
+		"7d":                   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		"90m":                  time.Date(2025, 1, 8, 10, 30, 0, 0, time.UTC),
+		"2025-01-05T00:00:00Z": time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := ParseTimeBound(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTimeBound(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseTimeBound("last week", now); err == nil {
+		t.Errorf("ParseTimeBound should reject free text")
+	}
+}
+
+func TestReadFileWhileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+	if err := log.Append(Record{CheckName: "api", Status: "pass", CompletedAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if len(records) != 1 || records[0].CheckName != "api" {
+		t.Fatalf("records = %+v", records)
+	}
+
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing.log")); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("ReadFile(missing) error = %v", err)
+	}
+}
@@ -1,30 +1,66 @@
 package state
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const (
+	KindPause  = "pause"
+	KindResume = "resume"
 )
 
+// Record is a single state log entry. Check results leave Kind empty; pause
+// and resume records only carry the check name and time.
 type Record struct {
-	CheckName   string    `json:"check_name"`
-	Status      string    `json:"status"`
-	DurationMs  int64     `json:"duration_ms"`
-	CompletedAt time.Time `json:"completed_at"`
+	Kind        string                 `json:"kind,omitempty"`
+	CheckName   string                 `json:"check_name"`
+	Status      string                 `json:"status"`
+	DurationMs  int64                  `json:"duration_ms"`
+	CompletedAt time.Time              `json:"completed_at"`
+	StartedAt   time.Time              `json:"started_at,omitzero"`
+	Message     string                 `json:"message,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	ExitCode    *int                   `json:"exit_code,omitempty"`
+	Image       string                 `json:"image,omitempty"`
+	Error       string                 `json:"error,omitempty"`
 }
 
+// Store persists check results and pause state. StateLog and SQLiteStore
+// implement it.
+type Store interface {
+	scheduler.ResultLogger
+	scheduler.PauseRecorder
+	Load() ([]Record, error)
+	History(q HistoryQuery) (HistoryPage, error)
+	Close() error
+}
+
+// StateLog stores records in append-only segment files in the directory
+// path + ".segments". The file at path holds the lock. Logs written as a
+// single file by earlier versions are migrated into segments on Open.
 type StateLog struct {
 	path      string
+	dir       string
 	retention time.Duration
+	span      time.Duration
 	lockFile  *os.File
 	mu        sync.Mutex
+
+	segments []*segment
+	current  *os.File
+	index    map[string][]indexEntry
+	control  map[string]controlEntry
+	loadErr  error
 }
 
 func Open(path string, retention time.Duration) (*StateLog, error) {
@@ -52,11 +88,24 @@ func Open(path string, retention time.Duration) (*StateLog, error) {
 		return nil, fmt.Errorf("state log file is locked by another process")
 	}
 
-	return &StateLog{
+	s := &StateLog{
 		path:      path,
+		dir:       path + segmentDirSuffix,
 		retention: retention,
+		span:      segmentSpan(retention),
 		lockFile:  file,
-	}, nil
+		index:     make(map[string][]indexEntry),
+		control:   make(map[string]controlEntry),
+	}
+	if err := s.openSegments(); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	if err := s.migrate(time.Now().UTC()); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
 }
 
 func (s *StateLog) Close() error {
@@ -66,87 +115,163 @@ func (s *StateLog) Close() error {
 	if s.lockFile == nil {
 		return nil
 	}
+	if s.current != nil {
+		_ = s.current.Close()
+		s.current = nil
+	}
 	_ = syscall.Flock(int(s.lockFile.Fd()), syscall.LOCK_UN)
 	err := s.lockFile.Close()
 	s.lockFile = nil
 	return err
 }
 
-func (s *StateLog) RecordResult(checkName string, status string, duration time.Duration, completedAt time.Time) error {
+func (s *StateLog) RecordResult(result scheduler.Result) error {
+	return s.Append(NewRecord(result))
+}
+
+func (s *StateLog) RecordPause(checkName string, paused bool, at time.Time) error {
+	kind := KindResume
+	if paused {
+		kind = KindPause
+	}
+	return s.Append(Record{Kind: kind, CheckName: checkName, CompletedAt: at.UTC()})
+}
+
+func (r Record) IsResult() bool {
+	return r.Kind == ""
+}
+
+func NewRecord(result scheduler.Result) Record {
+	completedAt := result.FinishedAt
 	if completedAt.IsZero() {
-		completedAt = time.Now().UTC()
+		completedAt = time.Now()
 	}
 	record := Record{
-		CheckName:   checkName,
-		Status:      status,
-		DurationMs:  duration.Milliseconds(),
+		CheckName:   result.CheckName,
+		Status:      result.Status,
+		DurationMs:  result.Duration().Milliseconds(),
 		CompletedAt: completedAt.UTC(),
+		Message:     result.Message,
+		Data:        result.Data,
+		ExitCode:    result.ExitCode,
+		Image:       result.Image,
+		Error:       result.Error,
+	}
+	if !result.StartedAt.IsZero() {
+		record.StartedAt = result.StartedAt.UTC()
+	}
+	return record
+}
+
+// Result rebuilds the check result stored in the record. Records written
+// before start times were persisted derive it from the duration.
+func (r Record) Result() scheduler.Result {
+	startedAt := r.StartedAt
+	if startedAt.IsZero() {
+		startedAt = r.CompletedAt.Add(-time.Duration(r.DurationMs) * time.Millisecond)
+	}
+	return scheduler.Result{
+		CheckName:  r.CheckName,
+		Status:     r.Status,
+		Message:    r.Message,
+		Data:       r.Data,
+		ExitCode:   r.ExitCode,
+		Image:      r.Image,
+		Error:      r.Error,
+		StartedAt:  startedAt,
+		FinishedAt: r.CompletedAt,
 	}
-	return s.Append(record)
 }
 
+// Load drops expired segments and returns the records within the retention
+// period. Corruption found while opening the log is reported once, together
+// with the records that could be read.
 func (s *StateLog) Load() ([]Record, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	records, err := s.readAll()
-	if err != nil {
+	loadErr := s.loadErr
+	s.loadErr = nil
+	now := time.Now().UTC()
+	if err := s.expire(now); err != nil {
 		return nil, err
 	}
-
-	filtered := s.filter(records, time.Now().UTC())
-	if len(filtered) != len(records) {
-		if err := s.writeAll(filtered); err != nil {
-			return nil, err
-		}
+	records, err := s.readSegments()
+	if err != nil {
+		return nil, err
 	}
-
-	return filtered, nil
+	return s.filter(records, now), loadErr
 }
 
+// Append adds a record to the end of the log. It only writes the record, so
+// its cost does not grow with the size of the log.
 func (s *StateLog) Append(record Record) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	now := time.Now().UTC()
-	records, readErr := s.readAll()
-	if readErr != nil {
-		records = nil
-	}
-
-	filtered := s.filter(records, now)
 	if record.CompletedAt.IsZero() {
 		record.CompletedAt = now
 	}
-	if s.retention > 0 {
-		cutoff := now.Add(-s.retention)
-		if record.CompletedAt.Before(cutoff) {
-			if err := s.writeAll(filtered); err != nil {
-				return err
-			}
-			if readErr != nil {
-				return fmt.Errorf("state log is corrupt: %w", readErr)
-			}
-			return nil
-		}
+	if record.CompletedAt.Before(now.Add(-s.retention)) {
+		return nil
 	}
+	return s.appendLocked(record, now)
+}
 
-	filtered = append(filtered, record)
-	if err := s.writeAll(filtered); err != nil {
+// migrate moves the records of a single-file log into segments and empties
+// the file. Records already in the segments are skipped, so a migration
+// interrupted before the file was emptied completes on the next Open. Lines
+// that do not decode are kept in path + ".corrupt".
+func (s *StateLog) migrate(now time.Time) error {
+	if _, err := s.lockFile.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
+	data, err := io.ReadAll(s.lockFile)
+	if err != nil {
+		return fmt.Errorf("failed to read state log file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
 
-	if readErr != nil {
-		return fmt.Errorf("state log is corrupt: %w", readErr)
+	records, parseErr := parseSegment(append(data, '\n'))
+	if parseErr != nil {
+		s.setCorrupt(parseErr)
+		if err := os.WriteFile(s.path+".corrupt", data, 0o644); err != nil {
+			return fmt.Errorf("failed to save corrupt state log: %w", err)
+		}
+	}
+	records = s.filter(records, now)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CompletedAt.Before(records[j].CompletedAt)
+	})
+	if len(s.segments) == 0 {
+		err = s.writeAll(records)
+	} else {
+		for _, record := range records {
+			if s.contains(record) {
+				continue
+			}
+			if err = s.appendLocked(record, now); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate state log: %w", err)
 	}
 
-	return nil
+	if err := s.lockFile.Truncate(0); err != nil {
+		return err
+	}
+	return s.lockFile.Sync()
 }
 
 func LatestByCheck(records []Record) map[string]Record {
 	latest := make(map[string]Record, len(records))
 	for _, record := range records {
-		if record.CheckName == "" {
+		if record.CheckName == "" || !record.IsResult() {
 			continue
 		}
 		existing, ok := latest[record.CheckName]
@@ -157,85 +282,52 @@ func LatestByCheck(records []Record) map[string]Record {
 	return latest
 }
 
-func (s *StateLog) filter(records []Record, now time.Time) []Record {
-	if s.retention <= 0 {
-		return records
-	}
-	cutoff := now.Add(-s.retention)
-	filtered := make([]Record, 0, len(records))
-	for _, record := range records {
-		if record.CompletedAt.Before(cutoff) {
-			continue
+// PausedChecks returns the checks whose latest pause or resume record is a
+// pause.
+func PausedChecks(records []Record) map[string]bool {
+	latest := latestControlRecords(records)
+	paused := make(map[string]bool, len(latest))
+	for name, record := range latest {
+		if record.Kind == KindPause {
+			paused[name] = true
 		}
-		filtered = append(filtered, record)
 	}
-	return filtered
+	return paused
 }
 
-func (s *StateLog) readAll() ([]Record, error) {
-	if s.lockFile == nil {
-		return nil, fmt.Errorf("state log is closed")
-	}
-	if _, err := s.lockFile.Seek(0, io.SeekStart); err != nil {
-		return nil, err
-	}
-
-	data, err := io.ReadAll(s.lockFile)
-	if err != nil {
-		return nil, err
-	}
-	if len(data) == 0 {
-		return nil, nil
-	}
-
-	scanner := bufio.NewScanner(bytes.NewReader(data))
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-	var records []Record
-	for scanner.Scan() {
-		line := bytes.TrimSpace(scanner.Bytes())
-		if len(line) == 0 {
+func latestControlRecords(records []Record) map[string]Record {
+	latest := make(map[string]Record)
+	for _, record := range records {
+		if record.CheckName == "" || record.IsResult() {
 			continue
 		}
-		var record Record
-		if err := json.Unmarshal(line, &record); err != nil {
-			return nil, err
+		existing, ok := latest[record.CheckName]
+		if !ok || !record.CompletedAt.Before(existing.CompletedAt) {
+			latest[record.CheckName] = record
 		}
-		records = append(records, record)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return records, nil
+	return latest
 }
 
-func (s *StateLog) writeAll(records []Record) error {
-	if s.lockFile == nil {
-		return fmt.Errorf("state log is closed")
-	}
-	if err := s.lockFile.Truncate(0); err != nil {
-		return err
-	}
-	if _, err := s.lockFile.Seek(0, io.SeekStart); err != nil {
-		return err
+// filter drops records older than the retention period. The latest pause
+// record of a check is kept regardless of its age so a paused check stays
+// paused across restarts.
+func (s *StateLog) filter(records []Record, now time.Time) []Record {
+	if s.retention <= 0 {
+		return records
 	}
-
-	writer := bufio.NewWriter(s.lockFile)
+	cutoff := now.Add(-s.retention)
+	control := latestControlRecords(records)
+	filtered := make([]Record, 0, len(records))
 	for _, record := range records {
-		payload, err := json.Marshal(record)
-		if err != nil {
-			return err
-		}
-		if _, err := writer.Write(payload); err != nil {
-			return err
-		}
-		if err := writer.WriteByte('\n'); err != nil {
-			return err
+		if record.CompletedAt.Before(cutoff) {
+			latest, ok := control[record.CheckName]
+			keep := ok && record.Kind == KindPause && latest.Kind == KindPause && latest.CompletedAt.Equal(record.CompletedAt)
+			if !keep {
+				continue
+			}
 		}
+		filtered = append(filtered, record)
 	}
-	if err := writer.Flush(); err != nil {
-		return err
-	}
-
-	return s.lockFile.Sync()
+	return filtered
 }
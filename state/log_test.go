@@ -1,10 +1,13 @@
 package state
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
 )
 
 func TestStateLogRetention(t *testing.T) {
@@ -73,3 +76,151 @@ func TestLoadCorruptStateLog(t *testing.T) {
 		t.Fatalf("expected load error for corrupt log")
 	}
 }
+
+func TestLoadKeepsRecordsAroundCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	now := time.Now().UTC()
+	var data []byte
+	for i, name := range []string{"api", "db"} {
+		line, _ := json.Marshal(Record{CheckName: name, Status: "pass", CompletedAt: now.Add(time.Duration(i-5) * time.Minute)})
+		data = append(append(data, line...), '\n')
+		if i == 0 {
+			data = append(data, "{\"check_name\": \"web\", truncated\n"...)
+		}
+	}
+	paused, _ := json.Marshal(Record{Kind: KindPause, CheckName: "db", CompletedAt: now})
+	data = append(append(data, paused...), '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write state log: %v", err)
+	}
+
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	records, err := log.Load()
+	if err == nil {
+		t.Fatalf("expected the corrupt line to be reported")
+	}
+	latest := LatestByCheck(records)
+	if len(records) != 3 || latest["api"].Status != "pass" || latest["db"].Status != "pass" || !PausedChecks(records)["db"] {
+		t.Fatalf("Load() = %+v, want the readable records", records)
+	}
+}
+
+func TestRecordResultRoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+	log, err := Open(filepath.Join(tmp, "state.log"), time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	exitCode := 2
+	finished := time.Now().UTC().Truncate(time.Millisecond)
+	result := scheduler.Result{
+		CheckName:  "tls",
+		Status:     "error",
+		Message:    "handshake failed",
+		Data:       map[string]interface{}{"days_remaining": float64(3)},
+		ExitCode:   &exitCode,
+		Image:      "example/openssl-check:1.0.0",
+		Error:      "check failed with exit code 2",
+		StartedAt:  finished.Add(-1500 * time.Millisecond),
+		FinishedAt: finished,
+	}
+	if err := log.RecordResult(result); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0].DurationMs != 1500 {
+		t.Fatalf("DurationMs = %d, want 1500", records[0].DurationMs)
+	}
+
+	got := records[0].Result()
+	if got.Message != result.Message || got.Image != result.Image || got.Error != result.Error {
+		t.Fatalf("Result() = %+v, want %+v", got, result)
+	}
+	if got.ExitCode == nil || *got.ExitCode != exitCode {
+		t.Fatalf("ExitCode = %v, want %d", got.ExitCode, exitCode)
+	}
+	if got.Data["days_remaining"] != float64(3) {
+		t.Fatalf("Data = %v, want days_remaining", got.Data)
+	}
+	if !got.StartedAt.Equal(result.StartedAt) || !got.FinishedAt.Equal(result.FinishedAt) {
+		t.Fatalf("times = %v..%v, want %v..%v", got.StartedAt, got.FinishedAt, result.StartedAt, result.FinishedAt)
+	}
+}
+
+func TestRecordWithoutStartTime(t *testing.T) {
+	completed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	record := Record{CheckName: "old", Status: "pass", DurationMs: 2000, CompletedAt: completed}
+
+	result := record.Result()
+	if !result.StartedAt.Equal(completed.Add(-2 * time.Second)) {
+		t.Fatalf("StartedAt = %v, want 2s before completion", result.StartedAt)
+	}
+}
+
+func TestPausedChecksSurviveRetention(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	now := time.Now().UTC()
+	if err := log.writeAll([]Record{
+		{Kind: KindPause, CheckName: "api", CompletedAt: now.Add(-3 * time.Hour)},
+		{Kind: KindPause, CheckName: "db", CompletedAt: now.Add(-3 * time.Hour)},
+		{Kind: KindResume, CheckName: "db", CompletedAt: now.Add(-2 * time.Hour)},
+		{CheckName: "api", Status: "pass", CompletedAt: now.Add(-2 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("write records: %v", err)
+	}
+	if err := log.RecordPause("disk", true, now); err != nil {
+		t.Fatalf("record pause: %v", err)
+	}
+
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected api pause and disk pause to remain, got %+v", records)
+	}
+
+	paused := PausedChecks(records)
+	if !paused["api"] || !paused["disk"] || paused["db"] {
+		t.Fatalf("PausedChecks() = %v, want api and disk", paused)
+	}
+	if len(LatestByCheck(records)) != 0 {
+		t.Fatalf("LatestByCheck() should ignore pause records")
+	}
+}
+
+func TestPausedChecksLatestRecordWins(t *testing.T) {
+	now := time.Now().UTC()
+	records := []Record{
+		{Kind: KindPause, CheckName: "api", CompletedAt: now.Add(-2 * time.Minute)},
+		{Kind: KindResume, CheckName: "api", CompletedAt: now.Add(-1 * time.Minute)},
+		{Kind: KindResume, CheckName: "db", CompletedAt: now.Add(-2 * time.Minute)},
+		{Kind: KindPause, CheckName: "db", CompletedAt: now.Add(-1 * time.Minute)},
+	}
+
+	paused := PausedChecks(records)
+	if paused["api"] || !paused["db"] {
+		t.Fatalf("PausedChecks() = %v, want only db", paused)
+	}
+}
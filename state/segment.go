@@ -0,0 +1,498 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	segmentDirSuffix = ".segments"
+	segmentExt       = ".jsonl"
+	maxSegmentSize   = 64 << 20
+	minSegmentSpan   = time.Minute
+	maxSegmentSpan   = 24 * time.Hour
+)
+
+// segment is one append-only file of the log. Segments are numbered in the
+// order they were created, so records are read back in append order.
+type segment struct {
+	seq    uint64
+	path   string
+	size   int64
+	start  time.Time
+	newest time.Time
+}
+
+// indexEntry locates a check result and keeps the fields history summaries
+// need, so a query only reads the records of the requested page.
+type indexEntry struct {
+	seq         uint64
+	offset      int64
+	length      int
+	completedAt time.Time
+	status      string
+	durationMs  int64
+}
+
+type controlEntry struct {
+	record Record
+	seq    uint64
+}
+
+// segmentSpan is how long a segment receives records before a new one is
+// started. Retention drops whole segments, so a shorter span keeps less
+// expired data on disk.
+func segmentSpan(retention time.Duration) time.Duration {
+	return min(max(retention/8, minSegmentSpan), maxSegmentSpan)
+}
+
+func segmentPath(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", seq, segmentExt))
+}
+
+// listSegments returns the segment files in dir ordered by sequence number.
+func listSegments(dir string) ([]*segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []*segment
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, &segment{seq: seq, path: filepath.Join(dir, entry.Name())})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+	return segments, nil
+}
+
+// openSegments builds the index from the segment files. A record torn by a
+// crash at the end of a segment was never acknowledged and is cut off; lines
+// that do not decode are skipped and reported by the next Load.
+func (s *StateLog) openSegments() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state log directory: %w", err)
+	}
+	segments, err := listSegments(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read state log segments: %w", err)
+	}
+	for _, seg := range segments {
+		if err := s.scanSegment(seg); err != nil {
+			return fmt.Errorf("failed to read state log segment: %w", err)
+		}
+	}
+	s.segments = segments
+	return nil
+}
+
+func (s *StateLog) scanSegment(seg *segment) error {
+	data, err := os.ReadFile(seg.path)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	for offset < len(data) {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			if err := os.Truncate(seg.path, int64(offset)); err != nil {
+				return err
+			}
+			break
+		}
+		line := data[offset : offset+end+1]
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var record Record
+			if err := json.Unmarshal(trimmed, &record); err != nil {
+				s.setCorrupt(fmt.Errorf("%s: %w", filepath.Base(seg.path), err))
+			} else {
+				s.indexRecord(record, seg, int64(offset), len(line))
+			}
+		}
+		offset += end + 1
+	}
+	seg.size = int64(offset)
+	if seg.start.IsZero() {
+		if info, err := os.Stat(seg.path); err == nil {
+			seg.start = info.ModTime().UTC()
+		}
+	}
+	return nil
+}
+
+func (s *StateLog) setCorrupt(err error) {
+	if s.loadErr == nil {
+		s.loadErr = fmt.Errorf("state log is corrupt: %w", err)
+	}
+}
+
+// indexRecord adds a record written to seg. A segment's start is the time of
+// its first record, or its creation time when it was started empty.
+func (s *StateLog) indexRecord(record Record, seg *segment, offset int64, length int) {
+	if seg.start.IsZero() {
+		seg.start = record.CompletedAt
+	}
+	if record.CompletedAt.After(seg.newest) {
+		seg.newest = record.CompletedAt
+	}
+	if record.CheckName == "" {
+		return
+	}
+	if !record.IsResult() {
+		existing, ok := s.control[record.CheckName]
+		if !ok || !record.CompletedAt.Before(existing.record.CompletedAt) {
+			s.control[record.CheckName] = controlEntry{record: record, seq: seg.seq}
+		}
+		return
+	}
+	s.index[record.CheckName] = append(s.index[record.CheckName], indexEntry{
+		seq:         seg.seq,
+		offset:      offset,
+		length:      length,
+		completedAt: record.CompletedAt,
+		status:      internStatus(record.Status),
+		durationMs:  record.DurationMs,
+	})
+}
+
+func internStatus(status string) string {
+	switch status {
+	case "pass":
+		return "pass"
+	case "warn":
+		return "warn"
+	case "fail":
+		return "fail"
+	case "error":
+		return "error"
+	}
+	return status
+}
+
+// contains reports whether record is already stored. Control records older
+// than the latest one of their check no longer matter and count as stored.
+func (s *StateLog) contains(record Record) bool {
+	if !record.IsResult() {
+		existing, ok := s.control[record.CheckName]
+		return ok && !record.CompletedAt.After(existing.record.CompletedAt)
+	}
+	for _, entry := range s.index[record.CheckName] {
+		if entry.completedAt.Equal(record.CompletedAt) && entry.status == record.Status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *StateLog) lastSegment() *segment {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	return s.segments[len(s.segments)-1]
+}
+
+// appendLocked starts a new segment when the current one is full or older
+// than the segment span, then writes the record.
+func (s *StateLog) appendLocked(record Record, now time.Time) error {
+	if s.lockFile == nil {
+		return fmt.Errorf("state log is closed")
+	}
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	payload = append(payload, '\n')
+
+	seg := s.lastSegment()
+	switch {
+	case seg == nil:
+		if _, err := s.createSegment(now); err != nil {
+			return err
+		}
+	case seg.size == 0:
+		seg.start = now
+	case seg.size+int64(len(payload)) > maxSegmentSize || now.Sub(seg.start) >= s.span:
+		if _, err := s.createSegment(now); err != nil {
+			return err
+		}
+		if err := s.expire(now); err != nil {
+			return err
+		}
+	}
+	return s.write(record, payload)
+}
+
+// write appends an encoded record to the last segment and syncs it.
+func (s *StateLog) write(record Record, payload []byte) error {
+	seg := s.lastSegment()
+	if s.current == nil {
+		file, err := os.OpenFile(seg.path, os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open state log segment: %w", err)
+		}
+		s.current = file
+	}
+	offset := seg.size
+	_, err := s.current.Write(payload)
+	if err == nil {
+		err = s.current.Sync()
+	}
+	if err != nil {
+		s.discardWrite(seg, offset)
+		return err
+	}
+	seg.size += int64(len(payload))
+	s.indexRecord(record, seg, offset, len(payload))
+	return nil
+}
+
+// discardWrite cuts a failed write off the end of seg, so the next record
+// does not follow a torn line and the index offsets stay valid. The segment
+// is reopened by the next write. If it cannot be truncated, it is treated as
+// full and the next record starts a new segment.
+func (s *StateLog) discardWrite(seg *segment, offset int64) {
+	_ = s.current.Close()
+	s.current = nil
+	if err := os.Truncate(seg.path, offset); err != nil {
+		seg.size = maxSegmentSize
+	}
+}
+
+func (s *StateLog) createSegment(now time.Time) (*segment, error) {
+	seq := uint64(1)
+	if last := s.lastSegment(); last != nil {
+		seq = last.seq + 1
+	}
+	seg := &segment{seq: seq, path: segmentPath(s.dir, seq), start: now}
+	file, err := os.OpenFile(seg.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state log segment: %w", err)
+	}
+	if s.current != nil {
+		_ = s.current.Close()
+	}
+	s.current = file
+	s.segments = append(s.segments, seg)
+	syncDir(s.dir)
+	return seg, nil
+}
+
+// expire deletes every segment but the last whose records are all older than
+// the retention period. The latest pause of a check is carried over into the
+// last segment first so the check stays paused.
+func (s *StateLog) expire(now time.Time) error {
+	if len(s.segments) < 2 {
+		return nil
+	}
+	cutoff := now.Add(-s.retention)
+	dropped := make(map[uint64]bool)
+	kept := make([]*segment, 0, len(s.segments))
+	for _, seg := range s.segments[:len(s.segments)-1] {
+		if seg.newest.Before(cutoff) {
+			dropped[seg.seq] = true
+			continue
+		}
+		kept = append(kept, seg)
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	for name, entry := range s.control {
+		if !dropped[entry.seq] {
+			continue
+		}
+		if entry.record.Kind != KindPause {
+			delete(s.control, name)
+			continue
+		}
+		payload, err := json.Marshal(entry.record)
+		if err != nil {
+			return err
+		}
+		if err := s.write(entry.record, append(payload, '\n')); err != nil {
+			return err
+		}
+	}
+	for name, entries := range s.index {
+		remaining := entries[:0]
+		for _, entry := range entries {
+			if !dropped[entry.seq] {
+				remaining = append(remaining, entry)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(s.index, name)
+			continue
+		}
+		s.index[name] = remaining
+	}
+
+	for _, seg := range s.segments {
+		if dropped[seg.seq] {
+			if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove state log segment: %w", err)
+			}
+		}
+	}
+	s.segments = append(kept, s.lastSegment())
+	return nil
+}
+
+// writeAll replaces the log with a single segment holding records.
+func (s *StateLog) writeAll(records []Record) error {
+	if s.lockFile == nil {
+		return fmt.Errorf("state log is closed")
+	}
+	seq := uint64(1)
+	if last := s.lastSegment(); last != nil {
+		seq = last.seq + 1
+	}
+	seg := &segment{seq: seq, path: segmentPath(s.dir, seq)}
+	file, err := os.OpenFile(seg.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create state log segment: %w", err)
+	}
+
+	index := make(map[string][]indexEntry)
+	control := make(map[string]controlEntry)
+	s.index, s.control = index, control
+	writer := bufio.NewWriter(file)
+	for _, record := range records {
+		payload, err := json.Marshal(record)
+		if err == nil {
+			payload = append(payload, '\n')
+			_, err = writer.Write(payload)
+		}
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		s.indexRecord(record, seg, seg.size, len(payload))
+		seg.size += int64(len(payload))
+	}
+	if err := writer.Flush(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if s.current != nil {
+		_ = s.current.Close()
+	}
+	for _, old := range s.segments {
+		if err := os.Remove(old.path); err != nil && !os.IsNotExist(err) {
+			_ = file.Close()
+			return fmt.Errorf("failed to remove state log segment: %w", err)
+		}
+	}
+	if seg.start.IsZero() {
+		seg.start = time.Now().UTC()
+	}
+	s.current = file
+	s.segments = []*segment{seg}
+	syncDir(s.dir)
+	return nil
+}
+
+// readSegments returns every record in the segments in append order.
+func (s *StateLog) readSegments() ([]Record, error) {
+	var records []Record
+	for _, seg := range s.segments {
+		data, err := os.ReadFile(seg.path)
+		if err != nil {
+			return nil, err
+		}
+		segmentRecords, _ := parseSegment(data)
+		records = append(records, segmentRecords...)
+	}
+	return records, nil
+}
+
+// readEntries loads the records behind index entries.
+func (s *StateLog) readEntries(entries []indexEntry) ([]Record, error) {
+	paths := make(map[uint64]string, len(s.segments))
+	for _, seg := range s.segments {
+		paths[seg.seq] = seg.path
+	}
+	files := make(map[uint64]*os.File)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	records := make([]Record, 0, len(entries))
+	for _, entry := range entries {
+		file, ok := files[entry.seq]
+		if !ok {
+			var err error
+			if file, err = os.Open(paths[entry.seq]); err != nil {
+				return nil, err
+			}
+			files[entry.seq] = file
+		}
+		buf := make([]byte, entry.length)
+		if _, err := file.ReadAt(buf, entry.offset); err != nil {
+			return nil, err
+		}
+		var record Record
+		if err := json.Unmarshal(bytes.TrimSpace(buf), &record); err != nil {
+			return nil, fmt.Errorf("state log is corrupt: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseSegment decodes the complete lines of a segment. A last line without a
+// newline is a write in progress and is ignored; lines that do not decode are
+// skipped and the first error is returned.
+func parseSegment(data []byte) ([]Record, error) {
+	if i := bytes.LastIndexByte(data, '\n'); i < len(data)-1 {
+		data = data[:i+1]
+	}
+	var records []Record
+	var firstErr error
+	for len(data) > 0 {
+		line, rest, _ := bytes.Cut(data, []byte{'\n'})
+		data = rest
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, firstErr
+}
+
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
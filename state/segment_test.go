@@ -0,0 +1,351 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func segmentFiles(t *testing.T, path string) []string {
+	t.Helper()
+	segments, err := listSegments(path + segmentDirSuffix)
+	if err != nil {
+		t.Fatalf("list segments: %v", err)
+	}
+	paths := make([]string, len(segments))
+	for i, seg := range segments {
+		paths[i] = seg.path
+	}
+	return paths
+}
+
+func writeLegacyLog(t *testing.T, path string, records ...Record) {
+	t.Helper()
+	var b strings.Builder
+	for _, record := range records {
+		payload, err := json.Marshal(record)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		b.Write(payload)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write legacy log: %v", err)
+	}
+}
+
+func TestMigrateLegacyStateLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	now := time.Now().UTC()
+	legacy := []Record{
+		{Kind: KindPause, CheckName: "db", CompletedAt: now.Add(-3 * time.Hour)},
+		{CheckName: "api", Status: "pass", CompletedAt: now.Add(-2 * time.Hour)},
+		{CheckName: "api", Status: "fail", CompletedAt: now.Add(-20 * time.Minute), Message: "HTTP 503"},
+		{CheckName: "api", Status: "pass", CompletedAt: now.Add(-10 * time.Minute)},
+	}
+	writeLegacyLog(t, path, legacy...)
+
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Fatalf("legacy file should be emptied after migration: %v, %v", info, err)
+	}
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 3 || !PausedChecks(records)["db"] || records[1].Message != "HTTP 503" {
+		t.Fatalf("migrated records = %+v", records)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// An interrupted migration leaves the legacy file in place; records that
+	// already reached the segments must not be duplicated.
+	writeLegacyLog(t, path, append(legacy, Record{CheckName: "api", Status: "warn", CompletedAt: now.Add(-5 * time.Minute)})...)
+	log, err = Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("reopen state log: %v", err)
+	}
+	defer log.Close()
+	records, err = log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 4 || records[3].Status != "warn" {
+		t.Fatalf("records after second migration = %+v", records)
+	}
+}
+
+func TestStateLogLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	if _, err := Open(path, time.Hour); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Fatalf("second Open() error = %v, want locked", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	log, err = Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open after close: %v", err)
+	}
+	log.Close()
+}
+
+func TestRetentionDropsWholeSegments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	now := time.Now().UTC()
+	if err := log.writeAll([]Record{
+		{Kind: KindPause, CheckName: "api", CompletedAt: now.Add(-3 * time.Hour)},
+		{CheckName: "api", Status: "pass", CompletedAt: now.Add(-3 * time.Hour)},
+		{CheckName: "db", Status: "fail", CompletedAt: now.Add(-2 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("write records: %v", err)
+	}
+	old := segmentFiles(t, path)
+
+	// The old segment has outlived the segment span, so this append starts a
+	// new one and the old one expires.
+	if err := log.Append(Record{CheckName: "db", Status: "pass", CompletedAt: now}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	current := segmentFiles(t, path)
+	if len(current) != 1 || current[0] == old[0] {
+		t.Fatalf("segments = %v, old segment %v should be removed", current, old)
+	}
+	if _, ok := log.index["api"]; ok {
+		t.Fatalf("index still references dropped results")
+	}
+
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 2 || !PausedChecks(records)["api"] || LatestByCheck(records)["db"].Status != "pass" {
+		t.Fatalf("records = %+v", records)
+	}
+}
+
+func TestTornSegmentTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := log.Append(Record{CheckName: "api", Status: "pass", CompletedAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	log.Close()
+
+	segment := segmentFiles(t, path)[0]
+	file, err := os.OpenFile(segment, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	file.WriteString(`{"check_name":"api","sta`)
+	file.Close()
+
+	if records, err := ReadFile(path); err != nil || len(records) != 1 {
+		t.Fatalf("ReadFile() = %v, %v; want the complete record", records, err)
+	}
+
+	log, err = Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("reopen state log: %v", err)
+	}
+	defer log.Close()
+	if err := log.Append(Record{CheckName: "api", Status: "fail", CompletedAt: now}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	if len(records) != 2 || records[1].Status != "fail" {
+		t.Fatalf("records = %+v", records)
+	}
+}
+
+func TestFailedWriteIsCutOff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+	now := time.Now().UTC()
+	if err := log.Append(Record{CheckName: "api", Status: "pass", CompletedAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	// Simulate a write that got part of the record to disk before failing.
+	segment := segmentFiles(t, path)[0]
+	file, err := os.OpenFile(segment, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	file.WriteString(`{"check_name":"api","sta`)
+	file.Close()
+	readOnly, err := os.Open(segment)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	log.mu.Lock()
+	_ = log.current.Close()
+	log.current = readOnly
+	log.mu.Unlock()
+	if err := log.Append(Record{CheckName: "api", Status: "error", CompletedAt: now.Add(-time.Second)}); err == nil {
+		t.Fatalf("append to a read-only segment should fail")
+	}
+
+	if err := log.Append(Record{CheckName: "api", Status: "fail", CompletedAt: now}); err != nil {
+		t.Fatalf("append after the failed write: %v", err)
+	}
+	records, err := log.Load()
+	if err != nil || len(records) != 2 || records[1].Status != "fail" {
+		t.Fatalf("Load() = %+v, %v; want the two written records", records, err)
+	}
+	page, err := log.History(HistoryQuery{Check: "api"})
+	if err != nil || len(page.Entries) != 2 || page.Entries[0].Status != "fail" {
+		t.Fatalf("History() = %+v, %v; want both records read through the index", page, err)
+	}
+}
+
+func TestCorruptSegmentReportedOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	dir := path + segmentDirSuffix
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	line, _ := json.Marshal(Record{CheckName: "api", Status: "pass", CompletedAt: time.Now().UTC()})
+	if err := os.WriteFile(segmentPath(dir, 1), []byte("not-json\n"+string(line)+"\n"), 0o644); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+
+	log, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+	if records, err := log.Load(); err == nil || len(records) != 1 {
+		t.Fatalf("first Load() = %v, %v; want the readable record and an error", records, err)
+	}
+	records, err := log.Load()
+	if err != nil || len(records) != 1 {
+		t.Fatalf("second Load() = %v, %v; want the readable record", records, err)
+	}
+}
+
+func TestStateLogHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	log, err := Open(path, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	start := time.Now().UTC().Add(-time.Hour)
+	for i, record := range historyRecords(start, "pass", "fail", "pass", "fail", "pass", "pass") {
+		record.Message = fmt.Sprintf("run %d", i)
+		if err := log.Append(record); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	if err := log.Append(Record{CheckName: "other", Status: "fail", CompletedAt: start}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	records, err := log.Load()
+	if err != nil {
+		t.Fatalf("load state log: %v", err)
+	}
+	query := HistoryQuery{Check: "api", Limit: 2, Statuses: []string{"pass"}}
+	for {
+		page, err := log.History(query)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		want, _ := QueryHistory(records, query)
+		if fmt.Sprint(page) != fmt.Sprint(want) {
+			t.Fatalf("History() = %+v\nwant %+v", page, want)
+		}
+		if len(page.Entries) == 0 || page.Entries[0].Message == "" {
+			t.Fatalf("entries should be read in full: %+v", page.Entries)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	for _, size := range []int{0, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			log, err := Open(filepath.Join(b.TempDir(), "state.log"), 24*time.Hour)
+			if err != nil {
+				b.Fatalf("open state log: %v", err)
+			}
+			defer log.Close()
+
+			now := time.Now().UTC()
+			records := make([]Record, size)
+			for i := range records {
+				records[i] = Record{CheckName: fmt.Sprintf("check-%d", i%50), Status: "pass", DurationMs: 120, CompletedAt: now.Add(-time.Duration(size-i) * time.Second)}
+			}
+			if err := log.writeAll(records); err != nil {
+				b.Fatalf("write records: %v", err)
+			}
+
+			record := Record{CheckName: "api", Status: "pass", DurationMs: 120, Message: "HTTP 200"}
+			for b.Loop() {
+				record.CompletedAt = time.Time{}
+				if err := log.Append(record); err != nil {
+					b.Fatalf("append: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHistory(b *testing.B) {
+	log, err := Open(filepath.Join(b.TempDir(), "state.log"), 24*time.Hour)
+	if err != nil {
+		b.Fatalf("open state log: %v", err)
+	}
+	defer log.Close()
+
+	now := time.Now().UTC()
+	records := make([]Record, 100_000)
+	for i := range records {
+		records[i] = Record{CheckName: fmt.Sprintf("check-%d", i%50), Status: "pass", DurationMs: 120, CompletedAt: now.Add(-time.Duration(len(records)-i) * time.Second)}
+	}
+	if err := log.writeAll(records); err != nil {
+		b.Fatalf("write records: %v", err)
+	}
+
+	for b.Loop() {
+		if _, err := log.History(HistoryQuery{Check: "check-7", Limit: DefaultHistoryLimit}); err != nil {
+			b.Fatalf("History() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package state
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteTimeFormat has a fixed width, so stored times sort as text and can be
+// passed to SQLite's date functions.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS results (
+	id           INTEGER PRIMARY KEY,
+	check_name   TEXT    NOT NULL,
+	status       TEXT    NOT NULL,
+	duration_ms  INTEGER NOT NULL,
+	completed_at TEXT    NOT NULL,
+	started_at   TEXT,
+	message      TEXT    NOT NULL DEFAULT '',
+	data         TEXT,
+	exit_code    INTEGER,
+	image        TEXT    NOT NULL DEFAULT '',
+	error        TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS results_check_completed ON results (check_name, completed_at);
+CREATE INDEX IF NOT EXISTS results_completed ON results (completed_at);
+CREATE TABLE IF NOT EXISTS paused_checks (
+	check_name TEXT PRIMARY KEY,
+	paused_at  TEXT NOT NULL
+);
+`
+
+const resultColumns = "check_name, status, duration_ms, completed_at, started_at, message, data, exit_code, image, error"
+
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// SQLiteStore keeps check results in an SQLite database. Results older than
+// the retention period are deleted periodically; pause state is kept in its
+// own table and never expires. Like the state log, the database is held by
+// one process at a time through a lock on path + ".lock".
+type SQLiteStore struct {
+	db        *sql.DB
+	lockFile  *os.File
+	retention time.Duration
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func OpenSQLite(path string, retention time.Duration) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("state database path is required")
+	}
+	if retention <= 0 {
+		return nil, fmt.Errorf("state log retention must be positive")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create state database directory: %w", err)
+		}
+	}
+
+	lockFile, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database lock: %w", err)
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = lockFile.Close()
+		return nil, fmt.Errorf("state database is locked by another process")
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		_ = lockFile.Close()
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		_ = lockFile.Close()
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+
+	s := &SQLiteStore{
+		db:        db,
+		lockFile:  lockFile,
+		retention: retention,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err := s.prune(time.Now().UTC()); err != nil {
+		_ = db.Close()
+		_ = lockFile.Close()
+		return nil, err
+	}
+	go s.pruneLoop(segmentSpan(retention))
+	return s, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		err = s.db.Close()
+		_ = syscall.Flock(int(s.lockFile.Fd()), syscall.LOCK_UN)
+		_ = s.lockFile.Close()
+	})
+	return err
+}
+
+func (s *SQLiteStore) pruneLoop(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			_ = s.prune(now.UTC())
+		}
+	}
+}
+
+// prune deletes the results older than the retention period.
+func (s *SQLiteStore) prune(now time.Time) error {
+	cutoff := now.Add(-s.retention).Format(sqliteTimeFormat)
+	if _, err := s.db.Exec("DELETE FROM results WHERE completed_at < ?", cutoff); err != nil {
+		return fmt.Errorf("failed to prune state database: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) RecordResult(result scheduler.Result) error {
+	return s.Append(NewRecord(result))
+}
+
+func (s *SQLiteStore) RecordPause(checkName string, paused bool, at time.Time) error {
+	if !paused {
+		_, err := s.db.Exec("DELETE FROM paused_checks WHERE check_name = ?", checkName)
+		return err
+	}
+	_, err := s.db.Exec(`INSERT INTO paused_checks (check_name, paused_at) VALUES (?, ?)
+		ON CONFLICT (check_name) DO UPDATE SET paused_at = excluded.paused_at`,
+		checkName, at.UTC().Format(sqliteTimeFormat))
+	return err
+}
+
+// Append stores a record. Pause and resume records update the pause state.
+func (s *SQLiteStore) Append(record Record) error {
+	now := time.Now().UTC()
+	if record.CompletedAt.IsZero() {
+		record.CompletedAt = now
+	}
+	if !record.IsResult() {
+		return s.RecordPause(record.CheckName, record.Kind == KindPause, record.CompletedAt)
+	}
+	if record.CompletedAt.Before(now.Add(-s.retention)) {
+		return nil
+	}
+
+	var startedAt, data any
+	if !record.StartedAt.IsZero() {
+		startedAt = record.StartedAt.UTC().Format(sqliteTimeFormat)
+	}
+	if len(record.Data) > 0 {
+		payload, err := json.Marshal(record.Data)
+		if err != nil {
+			return err
+		}
+		data = string(payload)
+	}
+	var exitCode any
+	if record.ExitCode != nil {
+		exitCode = *record.ExitCode
+	}
+	_, err := s.db.Exec("INSERT INTO results ("+resultColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		record.CheckName, record.Status, record.DurationMs, record.CompletedAt.UTC().Format(sqliteTimeFormat),
+		startedAt, record.Message, data, exitCode, record.Image, record.Error)
+	if err != nil {
+		return fmt.Errorf("failed to store result: %w", err)
+	}
+	return nil
+}
+
+// Load returns the results within the retention period in the order they were
+// stored, followed by a pause record for every paused check.
+func (s *SQLiteStore) Load() ([]Record, error) {
+	now := time.Now().UTC()
+	if err := s.prune(now); err != nil {
+		return nil, err
+	}
+	return readSQLiteRecords(s.db, now.Add(-s.retention))
+}
+
+// History answers q with indexed queries. Summaries are computed from the
+// status, duration and time of the results in range; only the page's rows
+// are loaded in full.
+func (s *SQLiteStore) History(q HistoryQuery) (HistoryPage, error) {
+	since := time.Now().UTC().Add(-s.retention)
+	if q.Since.After(since) {
+		since = q.Since
+	}
+	query := "SELECT id, status, duration_ms, completed_at FROM results WHERE check_name = ? AND completed_at >= ?"
+	args := []any{q.Check, since.UTC().Format(sqliteTimeFormat)}
+	if !q.Until.IsZero() {
+		query += " AND completed_at < ?"
+		args = append(args, q.Until.UTC().Format(sqliteTimeFormat))
+	}
+	rows, err := s.db.Query(query+" ORDER BY completed_at, id", args...)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+	var ids []int64
+	var inRange []Record
+	for rows.Next() {
+		var id int64
+		var completedAt string
+		record := Record{CheckName: q.Check}
+		if err := rows.Scan(&id, &record.Status, &record.DurationMs, &completedAt); err != nil {
+			_ = rows.Close()
+			return HistoryPage{}, err
+		}
+		if record.CompletedAt, err = time.Parse(time.RFC3339Nano, completedAt); err != nil {
+			_ = rows.Close()
+			return HistoryPage{}, err
+		}
+		ids = append(ids, id)
+		inRange = append(inRange, record)
+	}
+	if err := rows.Close(); err != nil {
+		return HistoryPage{}, err
+	}
+
+	page, picked, err := pageHistory(inRange, q)
+	if err != nil || len(picked) == 0 {
+		return page, err
+	}
+	placeholders := make([]string, len(picked))
+	pickedIDs := make([]any, len(picked))
+	for i, p := range picked {
+		placeholders[i] = "?"
+		pickedIDs[i] = ids[p]
+	}
+	rows, err = s.db.Query("SELECT id, "+resultColumns+" FROM results WHERE id IN ("+strings.Join(placeholders, ", ")+")", pickedIDs...)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+	defer rows.Close()
+	byID := make(map[int64]Record, len(picked))
+	for rows.Next() {
+		var id int64
+		record, err := scanResult(rows, &id)
+		if err != nil {
+			return HistoryPage{}, err
+		}
+		byID[id] = record
+	}
+	if err := rows.Err(); err != nil {
+		return HistoryPage{}, err
+	}
+	for i, p := range picked {
+		if record, ok := byID[ids[p]]; ok {
+			page.Entries[i] = record
+		}
+	}
+	return page, nil
+}
+
+func readSQLiteRecords(db *sql.DB, since time.Time) ([]Record, error) {
+	rows, err := db.Query("SELECT id, "+resultColumns+" FROM results WHERE completed_at >= ? ORDER BY id", since.UTC().Format(sqliteTimeFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state database: %w", err)
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		var id int64
+		record, err := scanResult(rows, &id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state database: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state database: %w", err)
+	}
+
+	pauses, err := db.Query("SELECT check_name, paused_at FROM paused_checks ORDER BY check_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state database: %w", err)
+	}
+	defer pauses.Close()
+	for pauses.Next() {
+		var name, pausedAt string
+		if err := pauses.Scan(&name, &pausedAt); err != nil {
+			return nil, fmt.Errorf("failed to read state database: %w", err)
+		}
+		at, err := time.Parse(time.RFC3339Nano, pausedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state database: %w", err)
+		}
+		records = append(records, Record{Kind: KindPause, CheckName: name, CompletedAt: at})
+	}
+	return records, pauses.Err()
+}
+
+func scanResult(rows *sql.Rows, id *int64) (Record, error) {
+	var record Record
+	var completedAt string
+	var startedAt, data sql.NullString
+	var exitCode sql.NullInt64
+	err := rows.Scan(id, &record.CheckName, &record.Status, &record.DurationMs, &completedAt, &startedAt,
+		&record.Message, &data, &exitCode, &record.Image, &record.Error)
+	if err != nil {
+		return Record{}, err
+	}
+	if record.CompletedAt, err = time.Parse(time.RFC3339Nano, completedAt); err != nil {
+		return Record{}, err
+	}
+	if startedAt.Valid {
+		if record.StartedAt, err = time.Parse(time.RFC3339Nano, startedAt.String); err != nil {
+			return Record{}, err
+		}
+	}
+	if data.Valid && data.String != "" {
+		if err := json.Unmarshal([]byte(data.String), &record.Data); err != nil {
+			return Record{}, err
+		}
+	}
+	if exitCode.Valid {
+		code := int(exitCode.Int64)
+		record.ExitCode = &code
+	}
+	return record, nil
+}
+
+// isSQLite reports whether the file at path is an SQLite database.
+func isSQLite(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	header := make([]byte, len(sqliteHeader))
+	if _, err := file.Read(header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, sqliteHeader)
+}
+
+// readSQLite reads every stored record from a database without changing it.
+func readSQLite(path string) ([]Record, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	defer db.Close()
+	return readSQLiteRecords(db, time.Time{})
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+func openSQLite(t *testing.T, retention time.Duration) (*SQLiteStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state.db")
+	store, err := OpenSQLite(path, retention)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, path
+}
+
+func TestSQLiteRecordRoundTrip(t *testing.T) {
+	store, path := openSQLite(t, time.Hour)
+
+	exitCode := 2
+	started := time.Now().UTC().Add(-2 * time.Second)
+	result := scheduler.Result{
+		CheckName:  "api",
+		Status:     "fail",
+		Message:    "HTTP 503",
+		Data:       map[string]interface{}{"status_code": float64(503)},
+		ExitCode:   &exitCode,
+		Image:      "foghorn/http:1.0.0",
+		Error:      "timeout",
+		StartedAt:  started,
+		FinishedAt: started.Add(1500 * time.Millisecond),
+	}
+	if err := store.RecordResult(result); err != nil {
+		t.Fatalf("RecordResult() error = %v", err)
+	}
+	if err := store.Append(Record{CheckName: "old", Status: "pass", CompletedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("records = %+v, want only the recent result", records)
+	}
+	got := records[0].Result()
+	if got.Message != result.Message || got.Data["status_code"] != float64(503) || got.ExitCode == nil || *got.ExitCode != 2 ||
+		got.Image != result.Image || got.Error != result.Error || !got.StartedAt.Equal(started) || !got.FinishedAt.Equal(result.FinishedAt) {
+		t.Fatalf("Result() = %+v, want %+v", got, result)
+	}
+
+	store.Close()
+	if records, err := ReadFile(path); err != nil || len(records) != 1 || records[0].CheckName != "api" {
+		t.Fatalf("ReadFile() = %+v, %v", records, err)
+	}
+}
+
+func TestSQLiteLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	store, err := OpenSQLite(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	if _, err := OpenSQLite(path, time.Hour); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Fatalf("second OpenSQLite() error = %v, want locked", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	store, err = OpenSQLite(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenSQLite() after close error = %v", err)
+	}
+	store.Close()
+}
+
+func TestSQLiteRetentionAndPauses(t *testing.T) {
+	store, _ := openSQLite(t, time.Hour)
+
+	now := time.Now().UTC()
+	if _, err := store.db.Exec("INSERT INTO results (check_name, status, duration_ms, completed_at) VALUES ('api', 'pass', 10, ?)",
+		now.Add(-3*time.Hour).Format(sqliteTimeFormat)); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := store.RecordPause("api", true, now.Add(-3*time.Hour)); err != nil {
+		t.Fatalf("RecordPause() error = %v", err)
+	}
+	if err := store.RecordPause("db", true, now); err != nil {
+		t.Fatalf("RecordPause() error = %v", err)
+	}
+	if err := store.RecordPause("db", false, now); err != nil {
+		t.Fatalf("RecordPause() error = %v", err)
+	}
+
+	if err := store.prune(now); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+	var count int
+	if err := store.db.QueryRow("SELECT COUNT(*) FROM results").Scan(&count); err != nil || count != 0 {
+		t.Fatalf("results after prune = %d, %v", count, err)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	paused := PausedChecks(records)
+	if len(records) != 1 || !paused["api"] || paused["db"] {
+		t.Fatalf("records = %+v, want the api pause only", records)
+	}
+}
+
+func TestSQLiteHistory(t *testing.T) {
+	store, _ := openSQLite(t, 24*time.Hour)
+
+	start := time.Now().UTC().Add(-time.Hour)
+	records := historyRecords(start, "pass", "fail", "pass", "fail", "pass", "pass")
+	records[4].CompletedAt = records[5].CompletedAt
+	for i := range records {
+		records[i].Message = fmt.Sprintf("run %d", i)
+		if err := store.Append(records[i]); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	query := HistoryQuery{Check: "api", Limit: 2}
+	for pages := 0; ; pages++ {
+		page, err := store.History(query)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		want, _ := QueryHistory(records, query)
+		if fmt.Sprint(page) != fmt.Sprint(want) {
+			t.Fatalf("History() = %+v\nwant %+v", page, want)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if pages > 5 {
+			t.Fatalf("pagination does not terminate")
+		}
+		query.Cursor = page.NextCursor
+	}
+}
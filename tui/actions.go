@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const (
+	actionTimeout = 5 * time.Second
+	noticeTTL     = 10 * time.Second
+)
+
+var errReloadUnavailable = errors.New("reload is only available through the daemon status API")
+
+type checkController interface {
+	TriggerCheck(ctx context.Context, name string) error
+	PauseCheck(ctx context.Context, name string) error
+	ResumeCheck(ctx context.Context, name string) error
+	Reload(ctx context.Context) (scheduler.ReconcileResult, error)
+}
+
+// localController adapts an in-process scheduler to checkController.
+type localController struct {
+	sched *scheduler.Scheduler
+}
+
+func (c localController) TriggerCheck(_ context.Context, name string) error {
+	return c.sched.TriggerCheck(name)
+}
+
+func (c localController) PauseCheck(_ context.Context, name string) error {
+	return c.sched.PauseCheck(name)
+}
+
+func (c localController) ResumeCheck(_ context.Context, name string) error {
+	return c.sched.ResumeCheck(name)
+}
+
+func (c localController) Reload(context.Context) (scheduler.ReconcileResult, error) {
+	return scheduler.ReconcileResult{}, errReloadUnavailable
+}
+
+type actionMsg struct {
+	check  string
+	action string
+	text   string
+	err    error
+}
+
+// runAction starts a control request for the selected check ("run" or
+// "pause") or the daemon ("reload") and reports back with an actionMsg.
+func (m model) runAction(action string) (model, tea.Cmd) {
+	if m.controller == nil {
+		return m.setNotice("Check control is not available", true), nil
+	}
+	controller := m.controller
+
+	if action == "reload" {
+		return m, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+			defer cancel()
+			result, err := controller.Reload(ctx)
+			text := fmt.Sprintf("Config reloaded: %d added, %d updated, %d removed", len(result.Added), len(result.Updated), len(result.Removed))
+			return actionMsg{action: action, text: text, err: err}
+		}
+	}
+
+	rows := m.visibleChecks()
+	if len(rows) == 0 {
+		return m, nil
+	}
+	row := rows[selectedIndex(rows, m.selected)]
+	name := row.name
+	if action == "run" {
+		if m.pendingRuns == nil {
+			m.pendingRuns = make(map[string]time.Time)
+		}
+		m.pendingRuns[name] = lastFinished(row.check)
+	}
+	if action == "pause" && row.check.Paused {
+		action = "resume"
+	}
+
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+		var err error
+		var text string
+		switch action {
+		case "run":
+			err = controller.TriggerCheck(ctx, name)
+			text = fmt.Sprintf("%s: run requested", name)
+		case "pause":
+			err = controller.PauseCheck(ctx, name)
+			text = fmt.Sprintf("%s: paused", name)
+		case "resume":
+			err = controller.ResumeCheck(ctx, name)
+			text = fmt.Sprintf("%s: resumed", name)
+		}
+		return actionMsg{check: name, action: action, text: text, err: err}
+	}
+}
+
+func (m model) handleAction(msg actionMsg) model {
+	if msg.err != nil {
+		if msg.action == "run" {
+			delete(m.pendingRuns, msg.check)
+		}
+		if msg.check != "" {
+			return m.setNotice(fmt.Sprintf("%s: %s failed: %v", msg.check, msg.action, msg.err), true)
+		}
+		return m.setNotice(fmt.Sprintf("%s failed: %v", msg.action, msg.err), true)
+	}
+	return m.setNotice(msg.text, false)
+}
+
+// checkPendingRuns announces the results of manually triggered runs once
+// they show up in the status.
+func (m model) checkPendingRuns() model {
+	if len(m.pendingRuns) == 0 {
+		return m
+	}
+	checks := m.status.GetAllChecks()
+	for name, previous := range m.pendingRuns {
+		check, ok := checks[name]
+		if !ok {
+			delete(m.pendingRuns, name)
+			continue
+		}
+		if check.LastResult == nil || !check.LastResult.FinishedAt.After(previous) {
+			continue
+		}
+		delete(m.pendingRuns, name)
+		text := fmt.Sprintf("%s finished: %s", name, check.LastResult.Status)
+		if check.LastResult.Message != "" {
+			text += " - " + check.LastResult.Message
+		}
+		failed := check.LastResult.Status == "fail" || check.LastResult.Status == "error"
+		m = m.setNotice(text, failed)
+	}
+	return m
+}
+
+func lastFinished(check *scheduler.ScheduledCheck) time.Time {
+	if check == nil || check.LastResult == nil {
+		return time.Time{}
+	}
+	return check.LastResult.FinishedAt
+}
+
+func (m model) setNotice(text string, isError bool) model {
+	m.notice = text
+	m.noticeError = isError
+	m.noticeAt = time.Now()
+	return m
+}
+
+func (m model) expireNotice(now time.Time) model {
+	if m.notice != "" && now.Sub(m.noticeAt) > noticeTTL {
+		m.notice = ""
+	}
+	return m
+}
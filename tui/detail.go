@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+const minDetailHeight = 8
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func (m model) detailHeight() int {
+	if !m.showDetail {
+		return 0
+	}
+	return max(minDetailHeight, (m.height-3)/2)
+}
+
+func (m model) renderDetail(row checkRow, styles styles) string {
+	width := max(1, styles.width-2)
+	check := row.check
+	now := time.Now()
+
+	title := "─ " + row.name + " "
+	lines := []string{styles.detailTitle.Render(title) + styles.divider.Render(strings.Repeat("─", max(0, width-len([]rune(title)))))}
+	field := func(label, value string) {
+		if value == "" {
+			value = "-"
+		}
+		lines = append(lines, styles.detailLabel.Render(padRight(label, 14))+value)
+	}
+
+	field("State", checkState(check))
+	if described, ok := check.Config.(scheduler.DescribedCheckConfig); ok {
+		field("Description", described.GetDescription())
+		image := described.GetImage()
+		if check.LastResult != nil && check.LastResult.Image != "" && check.LastResult.Image != image {
+			image += " (resolved " + check.LastResult.Image + ")"
+		}
+		field("Image", image)
+	}
+	field("Tags", strings.Join(checkTags(check), ", "))
+	if dependent, ok := check.Config.(scheduler.DependentCheckConfig); ok && len(dependent.GetDependencies()) > 0 {
+		field("Depends on", strings.Join(dependent.GetDependencies(), ", "))
+	}
+	field("Schedule", formatSchedule(check, now))
+
+	if result := check.LastResult; result != nil {
+		message := result.Message
+		if result.Error != "" {
+			message = strings.TrimSpace(message + " (" + result.Error + ")")
+		}
+		field("Last message", message)
+	} else {
+		field("Last message", "no runs yet")
+	}
+
+	field("History", formatHistoryLine(check.History, styles))
+	field("Duration", formatDurations(check.History))
+
+	if check.LastResult != nil && len(check.LastResult.Data) > 0 {
+		lines = append(lines, styles.detailLabel.Render("Data"))
+		for _, entry := range flattenData("", check.LastResult.Data, nil) {
+			lines = append(lines, "  "+entry)
+		}
+	}
+
+	if len(check.History) > 0 {
+		lines = append(lines, styles.detailLabel.Render("Runs"))
+		for i := len(check.History) - 1; i >= 0; i-- {
+			entry := check.History[i]
+			lines = append(lines, fmt.Sprintf("  %s  %s %-5s  %s",
+				formatAbsoluteTime(entry.CompletedAt), statusSymbol(entry.Status, styles), entry.Status, formatDuration(entry.Duration)))
+		}
+	}
+
+	height := m.detailHeight()
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i := range lines {
+		lines[i] = truncate(lines[i], width)
+	}
+	return styles.checkList.Render(strings.Join(lines, "\n"))
+}
+
+func checkState(check *scheduler.ScheduledCheck) string {
+	var states []string
+	switch {
+	case check.Running && check.Attempt > 1:
+		states = append(states, check.AttemptLabel())
+	case check.Running:
+		states = append(states, "running")
+	case check.IsQueued:
+		states = append(states, "queued")
+	}
+	if check.Maintenance != "" {
+		states = append(states, "in maintenance ("+check.Maintenance+")")
+	}
+	if pending := check.PendingLabel(); pending != "" {
+		states = append(states, pending)
+	}
+	if check.Paused {
+		states = append(states, "paused")
+	}
+	if check.Config != nil && !check.Config.IsEnabled() {
+		states = append(states, "disabled")
+	}
+	status := check.LastStatus
+	if status == "" {
+		status = "unknown"
+	}
+	if status == scheduler.StatusSuppressed && check.SuppressedBy != "" {
+		status += " by " + check.SuppressedBy
+	}
+	return strings.Join(append([]string{status}, states...), ", ")
+}
+
+func formatSchedule(check *scheduler.ScheduledCheck, now time.Time) string {
+	var schedule string
+	if check.Config != nil {
+		schedule = check.Config.GetSchedule()
+		switch check.ScheduleType {
+		case scheduler.ScheduleTypeInterval:
+			schedule = "every " + schedule
+		case scheduler.ScheduleTypeWindowed:
+			schedule = "windows only"
+		default:
+			schedule = "cron " + schedule
+			if check.Location != nil && check.Location != time.UTC {
+				schedule += " " + check.Location.String()
+			}
+		}
+		if check.Window != "" {
+			schedule = "window " + check.Window + ", otherwise " + schedule
+			if check.ScheduleType == scheduler.ScheduleTypeWindowed {
+				schedule = "window " + check.Window
+			}
+		}
+	}
+	next := "due"
+	if check.NextRun.After(now) {
+		next = formatAbsoluteTime(check.NextRun) + " (in " + formatRelativeTime(check.NextRun.Sub(now)) + ")"
+		if check.Delay > 0 {
+			next += " incl. " + formatRelativeTime(check.Delay) + " " + spreadLabel(check)
+		}
+	}
+	return strings.TrimSpace(schedule + "  next: " + next)
+}
+
+func spreadLabel(check *scheduler.ScheduledCheck) string {
+	if check.ScheduleType == scheduler.ScheduleTypeInterval {
+		return "splay"
+	}
+	return "jitter"
+}
+
+func formatHistoryLine(entries []scheduler.CheckHistoryEntry, styles styles) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	symbols := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		symbols = append(symbols, statusSymbol(entry.Status, styles))
+	}
+	return fmt.Sprintf("%s  (%d runs since %s)", strings.Join(symbols, ""), len(entries), formatAbsoluteTime(entries[0].CompletedAt))
+}
+
+func formatDurations(entries []scheduler.CheckHistoryEntry) string {
+	durations := make([]time.Duration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Duration > 0 {
+			durations = append(durations, entry.Duration)
+		}
+	}
+	if len(durations) == 0 {
+		return ""
+	}
+	low, high := durations[0], durations[0]
+	for _, d := range durations {
+		low = min(low, d)
+		high = max(high, d)
+	}
+	return fmt.Sprintf("%s  min %s  max %s  last %s", sparkline(durations), formatDuration(low), formatDuration(high), formatDuration(durations[len(durations)-1]))
+}
+
+// sparkline scales the values between their minimum and maximum onto block
+// characters.
+func sparkline(values []time.Duration) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+	var builder strings.Builder
+	for _, v := range values {
+		index := 0
+		if high > low {
+			index = int(int64(v-low) * int64(len(sparkBlocks)-1) / int64(high-low))
+		}
+		builder.WriteRune(sparkBlocks[index])
+	}
+	return builder.String()
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(100 * time.Millisecond).String()
+	}
+}
+
+// flattenData renders result data as sorted "key: value" lines with dotted
+// names for nested objects.
+func flattenData(prefix string, data map[string]interface{}, out []string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := data[key].(map[string]interface{}); ok {
+			out = flattenData(name, nested, out)
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: %v", name, data[key]))
+	}
+	return out
+}
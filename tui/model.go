@@ -3,7 +3,6 @@ package tui
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
+	"github.com/pfarrer/foghorn/internal/statusapi"
 	"github.com/pfarrer/foghorn/scheduler"
 )
 
@@ -22,6 +22,17 @@ type model struct {
 	width        int
 	height       int
 	maxCheckRows int
+	selected     string
+	offset       int
+	showDetail   bool
+	filter       string
+	filtering    bool
+	sort         sortMode
+	controller   checkController
+	pendingRuns  map[string]time.Time
+	notice       string
+	noticeError  bool
+	noticeAt     time.Time
 }
 
 type statusReader interface {
@@ -45,14 +56,15 @@ func tickEvery(d time.Duration) tea.Cmd {
 func NewModel(sched *scheduler.Scheduler, logLevel string) model {
 	return model{
 		status:       sched,
+		controller:   localController{sched: sched},
 		logLevel:     logLevel,
 		uptime:       sched.GetStartTime(),
 		maxCheckRows: 20,
 	}
 }
 
-func NewRemoteModel(statusURL, logLevel string) (model, error) {
-	remote, err := newRemoteStatusReader(statusURL)
+func NewRemoteModel(statusURL, logLevel string, opts ...statusapi.ClientOption) (model, error) {
+	remote, err := newRemoteStatusReader(statusURL, opts...)
 	if err != nil {
 		return model{}, err
 	}
@@ -65,6 +77,7 @@ func NewRemoteModel(statusURL, logLevel string) (model, error) {
 	return model{
 		status:       remote,
 		refresher:    remote,
+		controller:   remote.client,
 		logLevel:     logLevel,
 		uptime:       remote.GetStartTime(),
 		maxCheckRows: 20,
@@ -78,13 +91,13 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC:
-			return m, tea.Quit
-		}
+		return m.handleKey(msg)
+	case actionMsg:
+		return m.handleAction(msg), nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		return m.moveSelection(0), nil
 	case tickMsg:
 		if m.refresher != nil {
 			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
@@ -95,20 +108,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			cancel()
 		}
-		return m, tickEvery(time.Second)
+		m = m.checkPendingRuns().expireNotice(time.Time(msg))
+		return m.moveSelection(0), tickEvery(time.Second)
 	}
 	return m, nil
 }
 
 func (m model) View() string {
-	if m.width == 0 {
-		m.width = 80
-	}
-	if m.height == 0 {
-		m.height = 24
-	}
-
+	m = m.withDefaultSize()
 	styles := newStyles(m.width)
+	rows := m.visibleChecks()
 
 	var builder strings.Builder
 
@@ -116,21 +125,60 @@ func (m model) View() string {
 	builder.WriteString("\n")
 	builder.WriteString(m.renderSummaryBar(styles))
 	builder.WriteString("\n")
-	builder.WriteString(m.renderCheckList(styles))
+	builder.WriteString(m.renderCheckList(rows, styles))
 	builder.WriteString("\n")
+	if m.showDetail && len(rows) > 0 {
+		builder.WriteString(m.renderDetail(rows[selectedIndex(rows, m.selected)], styles))
+		builder.WriteString("\n")
+	}
+	if m.notice != "" {
+		builder.WriteString(m.renderNotice(styles))
+		builder.WriteString("\n")
+	}
 	builder.WriteString(m.renderFooter(styles))
 
 	return builder.String()
 }
 
+func (m model) withDefaultSize() model {
+	if m.width == 0 {
+		m.width = 80
+	}
+	if m.height == 0 {
+		m.height = 24
+	}
+	return m
+}
+
+const selectionMarker = "> "
+
 func (m model) getAvailableCheckRows() int {
-	available := m.height - 6
+	available := m.withDefaultSize().height - 6 - m.detailHeight()
+	if m.notice != "" {
+		available--
+	}
 	if available < 1 {
 		return 1
 	}
 	return available
 }
 
+// listCapacity returns how many check rows fit on screen, leaving room for
+// the scroll indicator when not all rows fit.
+func (m model) listCapacity(totalRows int) int {
+	maxRows := m.getAvailableCheckRows() - 1
+	if totalRows > maxRows {
+		maxRows--
+	}
+	if maxRows > m.maxCheckRows {
+		maxRows = m.maxCheckRows
+	}
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	return maxRows
+}
+
 func (m model) renderHeader(styles styles) string {
 	uptime := time.Since(m.uptime).Round(time.Second)
 	title := styles.headerText.Render("Foghorn")
@@ -158,55 +206,40 @@ func (m model) renderSummaryBar(styles styles) string {
 	return styles.summaryBar.Render(content)
 }
 
-func (m model) renderCheckList(styles styles) string {
-	checks := m.status.GetAllChecks()
-
-	if len(checks) == 0 {
+func (m model) renderCheckList(rows []checkRow, styles styles) string {
+	if len(rows) == 0 {
+		if m.filter != "" {
+			return styles.empty.Render(fmt.Sprintf("No checks match %q", m.filter))
+		}
 		return styles.empty.Render("No checks configured")
 	}
 
-	var names []string
-	for name := range checks {
-		names = append(names, name)
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.name)
 	}
-	sort.Strings(names)
 
 	availableWidth := styles.width - 2
 	nameWidth := m.calculateNameWidth(names, availableWidth)
-	var rows []string
 	now := time.Now()
 
-	for _, name := range names {
-		check := checks[name]
-		rows = append(rows, m.formatCheckRow(name, nameWidth, check, now, styles))
-	}
-
 	totalRows := len(rows)
-	availableRows := m.getAvailableCheckRows()
-	maxRows := availableRows - 1
-	if totalRows > maxRows {
-		maxRows--
-	}
-	if maxRows > m.maxCheckRows {
-		maxRows = m.maxCheckRows
-	}
-	if maxRows < 1 {
-		maxRows = 1
-	}
-
-	displayStart := 0
-	if totalRows > maxRows {
-		scrollWindow := totalRows - maxRows + 1
-		seconds := int(time.Since(m.uptime).Seconds())
-		displayStart = seconds % scrollWindow
-	}
+	maxRows := m.listCapacity(totalRows)
+	selected := selectedIndex(rows, m.selected)
+	displayStart := scrollOffset(m.offset, selected, totalRows, maxRows)
 	displayEnd := min(displayStart+maxRows, totalRows)
 
 	var displayRows []string
 	displayRows = append(displayRows, m.renderCheckHeader(nameWidth, styles))
 	displayRows = append(displayRows, m.renderCheckDivider(styles))
 	for i := displayStart; i < displayEnd; i++ {
-		displayRows = append(displayRows, rows[i])
+		row := m.formatCheckRow(rows[i].name, nameWidth, rows[i].check, now, styles)
+		if i == selected {
+			row = styles.selected.Render(selectionMarker) + row
+		} else {
+			row = strings.Repeat(" ", len(selectionMarker)) + row
+		}
+		displayRows = append(displayRows, row)
 	}
 
 	content := strings.Join(displayRows, "\n")
@@ -220,7 +253,7 @@ func (m model) renderCheckList(styles styles) string {
 }
 
 func (m model) renderCheckHeader(nameWidth int, styles styles) string {
-	return styles.columnHeader.Render(m.formatCheckRow("Check", nameWidth, nil, time.Now(), styles))
+	return strings.Repeat(" ", len(selectionMarker)) + styles.columnHeader.Render(m.formatCheckRow("Check", nameWidth, nil, time.Now(), styles))
 }
 
 func (m model) renderCheckDivider(styles styles) string {
@@ -237,6 +270,22 @@ func (m model) formatCheckRow(name string, nameWidth int, check *scheduler.Sched
 		result = "Last Status"
 	} else {
 		result = statusSymbol(check.LastStatus, styles)
+		switch {
+		case check.Running && check.Attempt > 1:
+			result += " " + styles.colorRunning.Render(check.AttemptLabel())
+		case check.Running:
+			result += " " + styles.colorRunning.Render("running")
+		case check.IsQueued:
+			result += " " + styles.colorQueued.Render("queued")
+		case check.Paused:
+			result += " " + styles.colorIdle.Render("paused")
+		case check.Maintenance != "":
+			result += " " + styles.colorIdle.Render("maint")
+		case check.SuppressedBy != "":
+			result += " " + styles.colorIdle.Render(truncate(check.SuppressedBy, 9))
+		case check.PendingStatus != "":
+			result += " " + styles.colorWarn.Render(fmt.Sprintf("%s %d/%d", check.PendingStatus, check.PendingCount, check.PendingThreshold))
+		}
 	}
 
 	var lastRun string
@@ -268,7 +317,7 @@ func (m model) formatCheckRow(name string, nameWidth int, check *scheduler.Sched
 	lastWidth := 16
 	nextWidth := 12
 	historyWidth := 24
-	availableWidth := styles.width - 2
+	availableWidth := styles.width - 2 - len(selectionMarker)
 
 	nameCell := padRight(truncate(name, nameWidth), nameWidth)
 	resultCell := padRight(result, resultWidth)
@@ -282,11 +331,29 @@ func (m model) formatCheckRow(name string, nameWidth int, check *scheduler.Sched
 }
 
 func (m model) renderFooter(styles styles) string {
-	help := styles.footerText.Render("Ctrl+C to exit")
-	refresh := styles.footerText.Render("Refresh: 1s")
 	separator := styles.footerText.Render("    ")
+	if m.filtering {
+		prompt := styles.footerText.Render(fmt.Sprintf("Filter: %s_", m.filter))
+		help := styles.footerText.Render("Enter apply  Esc clear")
+		return styles.footer.Render(lipgloss.JoinHorizontal(lipgloss.Top, prompt, separator, help))
+	}
 
-	return styles.footer.Render(lipgloss.JoinHorizontal(lipgloss.Top, help, separator, refresh))
+	help := styles.footerText.Render("↑↓ select  enter details  / filter  s sort  r run  p pause  R reload  q quit")
+	sortInfo := styles.footerText.Render(fmt.Sprintf("Sort: %s", m.sort))
+	parts := []string{help, separator, sortInfo}
+	if m.filter != "" {
+		parts = append(parts, separator, styles.footerText.Render(fmt.Sprintf("Filter: %s", m.filter)))
+	}
+	content := lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+	return styles.footer.Render(truncate(content, max(1, styles.width-2)))
+}
+
+func (m model) renderNotice(styles styles) string {
+	style := styles.notice
+	if m.noticeError {
+		style = styles.noticeError
+	}
+	return styles.checkList.Render(style.Render(truncate(m.notice, max(1, styles.width-2))))
 }
 
 func formatRelativeTime(d time.Duration) string {
@@ -317,6 +384,10 @@ func statusSymbol(status string, styles styles) string {
 		return styles.colorWarn.Render("⚠")
 	case "error":
 		return styles.colorFail.Render("✗")
+	case scheduler.StatusSuppressed:
+		return styles.colorIdle.Render("⊘")
+	case scheduler.StatusMaintenance:
+		return styles.colorIdle.Render("◌")
 	default:
 		return styles.colorUnknown.Render("?")
 	}
@@ -369,7 +440,7 @@ func (m model) calculateNameWidth(names []string, availableWidth int) int {
 	minNameWidth := 10
 	maxNameWidth := 32
 
-	reserved := resultWidth + lastWidth + nextWidth + historyWidth + 8
+	reserved := resultWidth + lastWidth + nextWidth + historyWidth + 8 + len(selectionMarker)
 	nameWidth := availableWidth - reserved
 	if nameWidth < minNameWidth {
 		nameWidth = minNameWidth
@@ -393,17 +464,3 @@ func (m model) calculateNameWidth(names []string, availableWidth int) int {
 	}
 	return maxSeen
 }
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
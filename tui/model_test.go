@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pfarrer/foghorn/scheduler"
 )
 
@@ -21,7 +24,7 @@ func (s *stubConfig) IsEnabled() bool     { return s.enabled }
 type stubExecutor struct{}
 
 func (s *stubExecutor) Execute(check scheduler.CheckConfig) error { return nil }
-func (s *stubExecutor) SetResultCallback(callback func(string, string, time.Duration)) {
+func (s *stubExecutor) SetResultCallback(callback func(scheduler.Result)) {
 }
 
 func TestCheckHeaderColumns(t *testing.T) {
@@ -58,3 +61,269 @@ func TestHistorySymbolsRender(t *testing.T) {
 		t.Fatalf("history should include since timestamp, got %q", history)
 	}
 }
+
+type stubStatus struct {
+	checks map[string]*scheduler.ScheduledCheck
+}
+
+func (s *stubStatus) GetStartTime() time.Time { return time.Now() }
+func (s *stubStatus) GetCounts() (total, running, queued, pass, fail, warn int) {
+	return len(s.checks), 0, 0, 0, 0, 0
+}
+func (s *stubStatus) GetAllChecks() map[string]*scheduler.ScheduledCheck { return s.checks }
+
+type stubTaggedConfig struct {
+	stubConfig
+	tags []string
+}
+
+func (s *stubTaggedConfig) GetTags() []string      { return s.tags }
+func (s *stubTaggedConfig) GetDescription() string { return "Checks " + s.name }
+func (s *stubTaggedConfig) GetImage() string       { return "example/" + s.name + ":1.0.0" }
+
+func newInteractiveModel(count int) model {
+	now := time.Now()
+	checks := make(map[string]*scheduler.ScheduledCheck, count)
+	statuses := []string{"pass", "fail", "warn", "error"}
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("check-%02d", i)
+		tags := []string{"staging"}
+		if i%2 == 0 {
+			tags = []string{"prod"}
+		}
+		checks[name] = &scheduler.ScheduledCheck{
+			Config:       &stubTaggedConfig{stubConfig: stubConfig{name: name, schedule: "1m", enabled: true}, tags: tags},
+			LastStatus:   statuses[i%len(statuses)],
+			NextRun:      now.Add(time.Duration(count-i) * time.Minute),
+			ScheduleType: scheduler.ScheduleTypeInterval,
+		}
+	}
+	return model{status: &stubStatus{checks: checks}, uptime: now, width: 120, height: 20, maxCheckRows: 20}
+}
+
+func pressKeys(m model, keys ...string) model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+	return m
+}
+
+func TestNavigationScrollsToSelection(t *testing.T) {
+	m := pressKeys(newInteractiveModel(30), "j", "down", "j")
+	if m.selected != "check-03" {
+		t.Fatalf("selected = %q, want check-03", m.selected)
+	}
+
+	m = pressKeys(m, "G")
+	if m.selected != "check-29" {
+		t.Fatalf("selected after G = %q, want check-29", m.selected)
+	}
+	view := m.View()
+	if !strings.Contains(view, "> check-29") || strings.Contains(view, "check-00") {
+		t.Fatalf("view should scroll to the last check:\n%s", view)
+	}
+
+	m = pressKeys(m, "g", "k")
+	if m.selected != "check-00" || m.offset != 0 {
+		t.Fatalf("selected = %q offset = %d, want check-00 at the top", m.selected, m.offset)
+	}
+}
+
+func TestFilterByNameOrTag(t *testing.T) {
+	m := pressKeys(newInteractiveModel(6), "/", "p", "r", "o", "d", "enter")
+	if m.filtering || m.filter != "prod" {
+		t.Fatalf("filter = %q (filtering %v), want applied prod filter", m.filter, m.filtering)
+	}
+	rows := m.visibleChecks()
+	if len(rows) != 3 || rows[0].name != "check-00" || rows[2].name != "check-04" {
+		t.Fatalf("filtered rows = %v", rows)
+	}
+
+	m = pressKeys(m, "/", "backspace", "backspace", "backspace", "backspace", "0", "5", "enter")
+	if rows := m.visibleChecks(); len(rows) != 1 || m.selected != "check-05" {
+		t.Fatalf("name filter rows = %v, selected %q", rows, m.selected)
+	}
+
+	m = pressKeys(m, "esc")
+	if m.filter != "" || len(m.visibleChecks()) != 6 {
+		t.Fatalf("esc should clear the filter, got %q", m.filter)
+	}
+}
+
+func TestSortModes(t *testing.T) {
+	m := newInteractiveModel(4)
+	order := func(m model) string {
+		var names []string
+		for _, row := range m.visibleChecks() {
+			names = append(names, row.name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := order(m); got != "check-00,check-01,check-02,check-03" {
+		t.Fatalf("name order = %s", got)
+	}
+	m = pressKeys(m, "s")
+	if got := order(m); got != "check-03,check-01,check-02,check-00" {
+		t.Fatalf("status order = %s", got)
+	}
+	m = pressKeys(m, "s")
+	if got := order(m); got != "check-03,check-02,check-01,check-00" {
+		t.Fatalf("next run order = %s", got)
+	}
+	if !strings.Contains(m.View(), "Sort: next run") {
+		t.Fatalf("footer should show the sort mode")
+	}
+}
+
+func TestDetailPane(t *testing.T) {
+	m := newInteractiveModel(2)
+	m.height = 40
+	check := m.status.GetAllChecks()["check-01"]
+	now := time.Now()
+	check.LastResult = &scheduler.Result{
+		Message: "latency above threshold",
+		Data:    map[string]interface{}{"latency_ms": 812, "tls": map[string]interface{}{"days_left": 30}},
+	}
+	check.History = []scheduler.CheckHistoryEntry{
+		{Status: "pass", CompletedAt: now.Add(-3 * time.Minute), Duration: time.Second},
+		{Status: "pass", CompletedAt: now.Add(-2 * time.Minute), Duration: 2 * time.Second},
+		{Status: "warn", CompletedAt: now.Add(-time.Minute), Duration: 4 * time.Second},
+	}
+
+	m = pressKeys(m, "j", "enter")
+	view := m.View()
+	for _, want := range []string{"Checks check-01", "example/check-01:1.0.0", "staging", "every 1m", "latency above threshold", "latency_ms: 812", "tls.days_left: 30", "3 runs", "▁▃█", "max 4s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail pane missing %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKeys(m, "esc")
+	if m.showDetail {
+		t.Fatalf("esc should close the detail pane")
+	}
+}
+
+type stubController struct {
+	actions []string
+	err     error
+}
+
+func (c *stubController) TriggerCheck(_ context.Context, name string) error {
+	return c.record("run:" + name)
+}
+
+func (c *stubController) PauseCheck(_ context.Context, name string) error {
+	return c.record("pause:" + name)
+}
+
+func (c *stubController) ResumeCheck(_ context.Context, name string) error {
+	return c.record("resume:" + name)
+}
+
+func (c *stubController) Reload(context.Context) (scheduler.ReconcileResult, error) {
+	if err := c.record("reload"); err != nil {
+		return scheduler.ReconcileResult{}, err
+	}
+	return scheduler.ReconcileResult{Added: []string{"new"}}, nil
+}
+
+func (c *stubController) record(action string) error {
+	c.actions = append(c.actions, action)
+	return c.err
+}
+
+// pressAction sends a key and delivers the message produced by its command.
+func pressAction(t *testing.T, m model, key string) model {
+	t.Helper()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	m = updated.(model)
+	if cmd == nil {
+		t.Fatalf("key %q returned no command", key)
+	}
+	updated, _ = m.Update(cmd())
+	return updated.(model)
+}
+
+func TestCheckControlKeys(t *testing.T) {
+	controller := &stubController{}
+	m := newInteractiveModel(3)
+	m.controller = controller
+
+	m = pressAction(t, pressKeys(m, "j"), "r")
+	if m.notice != "check-01: run requested" || m.noticeError {
+		t.Fatalf("notice = %q (error %v)", m.notice, m.noticeError)
+	}
+	if _, ok := m.pendingRuns["check-01"]; !ok {
+		t.Fatalf("run should be pending")
+	}
+
+	m = pressAction(t, m, "p")
+	m.status.GetAllChecks()["check-01"].Paused = true
+	m = pressAction(t, m, "p")
+	m = pressAction(t, m, "R")
+	if !strings.Contains(m.notice, "1 added") {
+		t.Fatalf("reload notice = %q", m.notice)
+	}
+	if !strings.Contains(m.View(), "1 added, 0 updated, 0 removed") {
+		t.Fatalf("view should show the notice:\n%s", m.View())
+	}
+
+	want := "run:check-01,pause:check-01,resume:check-01,reload"
+	if got := strings.Join(controller.actions, ","); got != want {
+		t.Fatalf("actions = %s, want %s", got, want)
+	}
+}
+
+func TestCheckControlErrors(t *testing.T) {
+	m := newInteractiveModel(1)
+	m.controller = &stubController{err: scheduler.ErrCheckRunning}
+
+	m = pressAction(t, m, "r")
+	if !m.noticeError || !strings.Contains(m.notice, "check-00: run failed: check is already running") {
+		t.Fatalf("notice = %q (error %v)", m.notice, m.noticeError)
+	}
+	if len(m.pendingRuns) != 0 {
+		t.Fatalf("failed run should not stay pending")
+	}
+
+	m = m.expireNotice(m.noticeAt.Add(noticeTTL + time.Second))
+	if m.notice != "" {
+		t.Fatalf("notice should expire, got %q", m.notice)
+	}
+}
+
+func TestPendingRunReportsResult(t *testing.T) {
+	m := newInteractiveModel(1)
+	m.controller = &stubController{}
+	m = pressAction(t, m, "r")
+
+	check := m.status.GetAllChecks()["check-00"]
+	check.LastResult = &scheduler.Result{Status: "fail", Message: "connection refused", FinishedAt: time.Now()}
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+
+	if m.notice != "check-00 finished: fail - connection refused" || !m.noticeError {
+		t.Fatalf("notice = %q (error %v)", m.notice, m.noticeError)
+	}
+	if len(m.pendingRuns) != 0 {
+		t.Fatalf("finished run should no longer be pending")
+	}
+}
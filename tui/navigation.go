@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pfarrer/foghorn/scheduler"
+)
+
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortByStatus
+	sortByNextRun
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortByStatus:
+		return "status"
+	case sortByNextRun:
+		return "next run"
+	default:
+		return "name"
+	}
+}
+
+func (s sortMode) next() sortMode {
+	return (s + 1) % 3
+}
+
+type checkRow struct {
+	name  string
+	check *scheduler.ScheduledCheck
+}
+
+// visibleChecks returns the checks matching the filter in display order.
+func (m model) visibleChecks() []checkRow {
+	checks := m.status.GetAllChecks()
+	rows := make([]checkRow, 0, len(checks))
+	for name, check := range checks {
+		if matchesFilter(name, check, m.filter) {
+			rows = append(rows, checkRow{name: name, check: check})
+		}
+	}
+	sortRows(rows, m.sort)
+	return rows
+}
+
+// matchesFilter matches the filter text against the check name and tags,
+// ignoring case.
+func matchesFilter(name string, check *scheduler.ScheduledCheck, filter string) bool {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" || strings.Contains(strings.ToLower(name), filter) {
+		return true
+	}
+	for _, tag := range checkTags(check) {
+		if strings.Contains(strings.ToLower(tag), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortRows(rows []checkRow, mode sortMode) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch mode {
+		case sortByStatus:
+			if ra, rb := statusRank(a.check.LastStatus), statusRank(b.check.LastStatus); ra != rb {
+				return ra < rb
+			}
+		case sortByNextRun:
+			if !a.check.NextRun.Equal(b.check.NextRun) {
+				return a.check.NextRun.Before(b.check.NextRun)
+			}
+		}
+		return a.name < b.name
+	})
+}
+
+// statusRank orders the most severe status first.
+func statusRank(status string) int {
+	switch status {
+	case "error":
+		return 0
+	case "fail":
+		return 1
+	case "warn":
+		return 2
+	case "pass":
+		return 4
+	default:
+		return 3
+	}
+}
+
+func checkTags(check *scheduler.ScheduledCheck) []string {
+	if check == nil {
+		return nil
+	}
+	tagged, ok := check.Config.(scheduler.TaggedCheckConfig)
+	if !ok {
+		return nil
+	}
+	return tagged.GetTags()
+}
+
+func (m model) handleKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.filtering {
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEnter:
+			m.filtering = false
+		case tea.KeyEsc:
+			m.filtering = false
+			m.filter = ""
+		case tea.KeyBackspace:
+			if runes := []rune(m.filter); len(runes) > 0 {
+				m.filter = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.filter += string(msg.Runes)
+		}
+		return m.moveSelection(0), nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		return m.moveSelection(-1), nil
+	case "down", "j":
+		return m.moveSelection(1), nil
+	case "pgup":
+		return m.moveSelection(-m.pageSize()), nil
+	case "pgdown":
+		return m.moveSelection(m.pageSize()), nil
+	case "home", "g":
+		return m.moveSelection(-len(m.status.GetAllChecks())), nil
+	case "end", "G":
+		return m.moveSelection(len(m.status.GetAllChecks())), nil
+	case "enter":
+		m.showDetail = !m.showDetail
+		return m.moveSelection(0), nil
+	case "esc":
+		if m.showDetail {
+			m.showDetail = false
+		} else {
+			m.filter = ""
+		}
+		return m.moveSelection(0), nil
+	case "/":
+		m.filtering = true
+	case "s":
+		m.sort = m.sort.next()
+		return m.moveSelection(0), nil
+	case "r":
+		return m.runAction("run")
+	case "p":
+		return m.runAction("pause")
+	case "R":
+		return m.runAction("reload")
+	}
+	return m, nil
+}
+
+// moveSelection moves the selection by delta rows and scrolls the list so
+// the selected check stays visible. The selection follows the check name,
+// so it survives re-sorting and refreshes.
+func (m model) moveSelection(delta int) model {
+	rows := m.visibleChecks()
+	if len(rows) == 0 {
+		m.selected = ""
+		m.offset = 0
+		return m
+	}
+	index := selectedIndex(rows, m.selected) + delta
+	index = max(0, min(index, len(rows)-1))
+	m.selected = rows[index].name
+	m.offset = scrollOffset(m.offset, index, len(rows), m.listCapacity(len(rows)))
+	return m
+}
+
+func selectedIndex(rows []checkRow, selected string) int {
+	for i, row := range rows {
+		if row.name == selected {
+			return i
+		}
+	}
+	return 0
+}
+
+// scrollOffset returns the first visible row so that index is within a
+// window of capacity rows, moving the previous offset as little as possible.
+func scrollOffset(offset, index, total, capacity int) int {
+	if index < offset {
+		offset = index
+	}
+	if index >= offset+capacity {
+		offset = index - capacity + 1
+	}
+	return max(0, min(offset, total-capacity))
+}
+
+func (m model) pageSize() int {
+	return max(1, m.listCapacity(len(m.status.GetAllChecks()))-1)
+}
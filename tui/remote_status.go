@@ -19,13 +19,13 @@ type remoteStatusReader struct {
 	snapshot time.Time
 }
 
-func newRemoteStatusReader(statusURL string) (*remoteStatusReader, error) {
+func newRemoteStatusReader(statusURL string, opts ...statusapi.ClientOption) (*remoteStatusReader, error) {
 	url := strings.TrimSpace(statusURL)
 	if url == "" {
 		url = statusapi.DefaultBaseURL
 	}
 	return &remoteStatusReader{
-		client: statusapi.NewClient(url),
+		client: statusapi.NewClient(url, opts...),
 		checks: make(map[string]*scheduler.ScheduledCheck),
 	}, nil
 }
@@ -42,14 +42,27 @@ func (r *remoteStatusReader) Refresh(ctx context.Context) error {
 		copy(history, check.History)
 		duration := time.Duration(check.LastDurationMs) * time.Millisecond
 		checks[name] = &scheduler.ScheduledCheck{
-			NextRun:      check.NextRun,
-			LastRun:      copyTime(check.LastRun),
-			LastStatus:   check.LastStatus,
-			LastDuration: duration,
-			Running:      check.Running,
-			ScheduleType: check.ScheduleType,
-			IsQueued:     check.Queued,
-			History:      history,
+			Config:           newRemoteCheckConfig(name, check),
+			NextRun:          check.NextRun,
+			Delay:            time.Duration(check.NextRunDelayMs) * time.Millisecond,
+			LastRun:          copyTime(check.LastRun),
+			LastStatus:       check.LastStatus,
+			LastDuration:     duration,
+			LastResult:       check.LastResult,
+			Running:          check.Running,
+			Paused:           check.Paused,
+			ScheduleType:     check.ScheduleType,
+			Location:         remoteLocation(check.Timezone),
+			Window:           check.ScheduleWindow,
+			IsQueued:         check.Queued,
+			History:          history,
+			SuppressedBy:     check.SuppressedBy,
+			Maintenance:      check.Maintenance,
+			PendingStatus:    check.PendingStatus,
+			PendingCount:     check.PendingCount,
+			PendingThreshold: check.PendingThreshold,
+			Attempt:          check.Attempt,
+			MaxAttempts:      check.MaxAttempts,
 		}
 	}
 
@@ -83,19 +96,76 @@ func (r *remoteStatusReader) GetAllChecks() map[string]*scheduler.ScheduledCheck
 		history := make([]scheduler.CheckHistoryEntry, len(check.History))
 		copy(history, check.History)
 		out[name] = &scheduler.ScheduledCheck{
-			NextRun:      check.NextRun,
-			LastRun:      copyTime(check.LastRun),
-			LastStatus:   check.LastStatus,
-			LastDuration: check.LastDuration,
-			Running:      check.Running,
-			ScheduleType: check.ScheduleType,
-			IsQueued:     check.IsQueued,
-			History:      history,
+			Config:           check.Config,
+			NextRun:          check.NextRun,
+			Delay:            check.Delay,
+			LastRun:          copyTime(check.LastRun),
+			LastStatus:       check.LastStatus,
+			LastDuration:     check.LastDuration,
+			LastResult:       check.LastResult,
+			Running:          check.Running,
+			Paused:           check.Paused,
+			ScheduleType:     check.ScheduleType,
+			Location:         check.Location,
+			Window:           check.Window,
+			IsQueued:         check.IsQueued,
+			History:          history,
+			SuppressedBy:     check.SuppressedBy,
+			Maintenance:      check.Maintenance,
+			PendingStatus:    check.PendingStatus,
+			PendingCount:     check.PendingCount,
+			PendingThreshold: check.PendingThreshold,
+			Attempt:          check.Attempt,
+			MaxAttempts:      check.MaxAttempts,
 		}
 	}
 	return out
 }
 
+// remoteCheckConfig exposes the check settings reported by the status API,
+// so the TUI can treat remote and local checks alike.
+type remoteCheckConfig struct {
+	name        string
+	schedule    string
+	enabled     bool
+	tags        []string
+	description string
+	image       string
+	dependsOn   []string
+}
+
+func newRemoteCheckConfig(name string, check scheduler.CheckStatus) *remoteCheckConfig {
+	return &remoteCheckConfig{
+		name:        name,
+		schedule:    check.Schedule,
+		enabled:     check.Enabled,
+		tags:        check.Tags,
+		description: check.Description,
+		image:       check.Image,
+		dependsOn:   check.DependsOn,
+	}
+}
+
+func (c *remoteCheckConfig) GetName() string           { return c.name }
+func (c *remoteCheckConfig) GetSchedule() string       { return c.schedule }
+func (c *remoteCheckConfig) IsEnabled() bool           { return c.enabled }
+func (c *remoteCheckConfig) GetTags() []string         { return c.tags }
+func (c *remoteCheckConfig) GetDescription() string    { return c.description }
+func (c *remoteCheckConfig) GetImage() string          { return c.image }
+func (c *remoteCheckConfig) GetDependencies() []string { return c.dependsOn }
+
+// remoteLocation resolves the time zone reported for a check. The TUI only
+// displays it, so a zone missing from the local database keeps its name.
+func remoteLocation(name string) *time.Location {
+	if name == "" {
+		return nil
+	}
+	if location, err := time.LoadLocation(name); err == nil {
+		return location
+	}
+	return time.FixedZone(name, 0)
+}
+
 func copyTime(t *time.Time) *time.Time {
 	if t == nil {
 		return nil
@@ -22,8 +22,12 @@ func TestRemoteStatusReaderRefresh(t *testing.T) {
 			},
 			Checks: map[string]scheduler.CheckStatus{
 				"api-check": {
-					Name:       "api-check",
-					LastStatus: "pass",
+					Name:        "api-check",
+					Description: "API health",
+					Tags:        []string{"prod"},
+					Schedule:    "*/5 * * * *",
+					Enabled:     true,
+					LastStatus:  "pass",
 					History: []scheduler.CheckHistoryEntry{
 						{Status: "pass", CompletedAt: now.Add(-time.Minute)},
 					},
@@ -52,4 +56,11 @@ func TestRemoteStatusReaderRefresh(t *testing.T) {
 	if checks["api-check"].LastStatus != "pass" {
 		t.Fatalf("LastStatus = %q, want pass", checks["api-check"].LastStatus)
 	}
+	config, ok := checks["api-check"].Config.(scheduler.DescribedCheckConfig)
+	if !ok || config.GetDescription() != "API health" || config.GetSchedule() != "*/5 * * * *" || !config.IsEnabled() {
+		t.Fatalf("Config = %+v, want remote check settings", checks["api-check"].Config)
+	}
+	if tags := checkTags(checks["api-check"]); len(tags) != 1 || tags[0] != "prod" {
+		t.Fatalf("tags = %v, want [prod]", tags)
+	}
 }
@@ -19,6 +19,11 @@ type styles struct {
 	scrollInfo   lipgloss.Style
 	columnHeader lipgloss.Style
 	divider      lipgloss.Style
+	selected     lipgloss.Style
+	detailTitle  lipgloss.Style
+	detailLabel  lipgloss.Style
+	notice       lipgloss.Style
+	noticeError  lipgloss.Style
 	colorPass    lipgloss.Style
 	colorFail    lipgloss.Style
 	colorWarn    lipgloss.Style
@@ -78,6 +83,23 @@ func newStyles(width int) styles {
 		divider: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("238")),
 
+		selected: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFD700")),
+
+		detailTitle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("252")),
+
+		detailLabel: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245")),
+
+		notice: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#61AFEF")),
+
+		noticeError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#E06C75")),
+
 		footer: lipgloss.NewStyle().
 			Background(footerBg).
 			Foreground(lipgloss.Color("243")).